@@ -88,6 +88,17 @@ type Bookmark struct {
 	Url         string             `json:"url"`
 }
 
+// BookmarkCreate defines model for BookmarkCreate.
+type BookmarkCreate struct {
+	FolderPath *string `json:"folder_path,omitempty"`
+
+	// Notes Free-form notes, stored as the bookmark description
+	Notes *string   `json:"notes,omitempty"`
+	Tags  *[]string `json:"tags,omitempty"`
+	Title *string   `json:"title,omitempty"`
+	Url   string    `json:"url"`
+}
+
 // BookmarkDetail defines model for BookmarkDetail.
 type BookmarkDetail struct {
 	// Content Scraped content of the bookmark
@@ -160,6 +171,12 @@ type Category struct {
 	UsageCount int `json:"usage_count"`
 }
 
+// ChatMetadata Details about how the answer was produced, for debugging retrieval
+type ChatMetadata struct {
+	// RewrittenQuery Standalone search query used for retrieval, condensed from the conversation history and the new message
+	RewrittenQuery string `json:"rewritten_query"`
+}
+
 // ChatRequest defines model for ChatRequest.
 type ChatRequest struct {
 	// Context Additional context bookmark IDs
@@ -170,9 +187,47 @@ type ChatRequest struct {
 
 // ChatResponse defines model for ChatResponse.
 type ChatResponse struct {
+	// Citations Resolution of the citation markers used in the reply
+	Citations      *[]Citation        `json:"citations,omitempty"`
 	ConversationId openapi_types.UUID `json:"conversation_id"`
-	Reply          string             `json:"reply"`
-	Sources        *[]Bookmark        `json:"sources,omitempty"`
+
+	// Metadata Details about how the answer was produced, for debugging retrieval
+	Metadata *ChatMetadata `json:"metadata,omitempty"`
+	Reply    string        `json:"reply"`
+	Sources  *[]Bookmark   `json:"sources,omitempty"`
+}
+
+// ChatStreamDelta Payload of a `delta` event on the chat stream
+type ChatStreamDelta struct {
+	// Content Next chunk of the reply text
+	Content string `json:"content"`
+}
+
+// ChatStreamDone Payload of the final `done` event on the chat stream
+type ChatStreamDone struct {
+	Citations      []Citation         `json:"citations"`
+	ConversationId openapi_types.UUID `json:"conversation_id"`
+
+	// MessageId ID of the stored assistant message
+	MessageId openapi_types.UUID `json:"message_id"`
+
+	// Metadata Details about how the answer was produced, for debugging retrieval
+	Metadata *ChatMetadata `json:"metadata,omitempty"`
+	Sources  []Bookmark    `json:"sources"`
+}
+
+// Citation Resolves an inline citation marker in an answer to the bookmark chunk it came from
+type Citation struct {
+	BookmarkId openapi_types.UUID `json:"bookmark_id"`
+
+	// ChunkIndex Index of the chunk in the bookmark's embeddings; omitted when the bookmark has no embeddings and its scraped text was cited
+	ChunkIndex *int `json:"chunk_index,omitempty"`
+
+	// Marker Number used in the answer text, e.g. 1 for [1]
+	Marker int `json:"marker"`
+
+	// Quote Text of the cited chunk
+	Quote string `json:"quote"`
 }
 
 // ConversationDetail defines model for ConversationDetail.
@@ -205,6 +260,25 @@ type Error struct {
 	Message string                  `json:"message"`
 }
 
+// Event A progress event delivered on the event stream
+type Event struct {
+	// Data Type-specific payload, such as the bookmark ID and URL of a scrape
+	Data map[string]interface{} `json:"data"`
+
+	// Id Increases with every event published since the server started
+	Id   int64     `json:"id"`
+	Time time.Time `json:"time"`
+
+	// Type Event type, such as scrape.finished
+	Type string `json:"type"`
+}
+
+// FacetCount defines model for FacetCount.
+type FacetCount struct {
+	Count int    `json:"count"`
+	Value string `json:"value"`
+}
+
 // HealthResponse defines model for HealthResponse.
 type HealthResponse struct {
 	Services *struct {
@@ -246,9 +320,49 @@ type ImportResponse struct {
 // ImportResponseStatus defines model for ImportResponse.Status.
 type ImportResponseStatus string
 
+// Job defines model for Job.
+type Job struct {
+	Attempts int `json:"attempts"`
+
+	// BatchId Groups jobs enqueued together, such as one bulk scrape
+	BatchId    *string             `json:"batch_id,omitempty"`
+	BookmarkId *openapi_types.UUID `json:"bookmark_id,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+	Id         openapi_types.UUID  `json:"id"`
+	LastError  *string             `json:"last_error,omitempty"`
+
+	// LeaseExpiresAt When a running job becomes claimable again if its worker stops renewing the lease
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+	MaxAttempts    int        `json:"max_attempts"`
+
+	// Priority Higher runs first
+	Priority int `json:"priority"`
+
+	// Result Output of a succeeded job, such as a link check's status code
+	Result *map[string]interface{} `json:"result,omitempty"`
+
+	// RunAt Earliest time the job runs; later than now while waiting to retry
+	RunAt time.Time `json:"run_at"`
+
+	// State queued, paused, running, succeeded, dead (failed on its last attempt) or cancelled
+	State string `json:"state"`
+
+	// Type scrape, embed, categorize or link_check
+	Type      string    `json:"type"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// JobListResponse defines model for JobListResponse.
+type JobListResponse struct {
+	Jobs       []Job      `json:"jobs"`
+	Pagination Pagination `json:"pagination"`
+}
+
 // Message defines model for Message.
 type Message struct {
 	BookmarkRefs *[]openapi_types.UUID `json:"bookmark_refs,omitempty"`
+	Citations    *[]Citation           `json:"citations,omitempty"`
 	Content      string                `json:"content"`
 	CreatedAt    time.Time             `json:"created_at"`
 	Id           openapi_types.UUID    `json:"id"`
@@ -266,11 +380,84 @@ type Pagination struct {
 	TotalPages int `json:"total_pages"`
 }
 
+// PurgeJobsResponse defines model for PurgeJobsResponse.
+type PurgeJobsResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// SearchFacets Counts of the returned bookmarks by attribute. A search with only filters counts every bookmark passing them.
+type SearchFacets struct {
+	// Categories Primary categories
+	Categories  []FacetCount `json:"categories"`
+	Domains     []FacetCount `json:"domains"`
+	FolderPaths []FacetCount `json:"folder_paths"`
+	Statuses    []FacetCount `json:"statuses"`
+	Tags        []FacetCount `json:"tags"`
+
+	// Years Years the bookmarks were created, newest first
+	Years []FacetCount `json:"years"`
+}
+
+// SearchFilters Restricts search results; all given filters must match
+type SearchFilters struct {
+	// Categories Results must belong to at least one category
+	Categories    *[]string  `json:"categories,omitempty"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+
+	// Domain Host to match, including its subdomains
+	Domain *string `json:"domain,omitempty"`
+
+	// FolderPath Folder to search, including its subfolders
+	FolderPath    *string    `json:"folder_path,omitempty"`
+	ScrapedAfter  *time.Time `json:"scraped_after,omitempty"`
+	ScrapedBefore *time.Time `json:"scraped_before,omitempty"`
+
+	// Status Processing status (pending, processing, completed or failed)
+	Status *string `json:"status,omitempty"`
+
+	// Tags Results must carry every tag
+	Tags *[]string `json:"tags,omitempty"`
+}
+
+// SearchIndexRebuildResponse defines model for SearchIndexRebuildResponse.
+type SearchIndexRebuildResponse struct {
+	// BookmarksIndexed Number of bookmarks written to the title/description index
+	BookmarksIndexed int `json:"bookmarks_indexed"`
+
+	// ContentIndexed Number of content rows written to the content index
+	ContentIndexed int `json:"content_indexed"`
+
+	// DurationMs Time taken to rebuild the indexes in milliseconds
+	DurationMs int `json:"duration_ms"`
+}
+
+// SearchPassage defines model for SearchPassage.
+type SearchPassage struct {
+	// ChunkIndex Position of the chunk within the bookmark's content
+	ChunkIndex int     `json:"chunk_index"`
+	Score      float32 `json:"score"`
+
+	// Text Excerpt of the chunk with query words wrapped in <mark> tags. Text outside the tags is
+	// page text that is not HTML-escaped; escape it before rendering the excerpt as HTML.
+	Text string `json:"text"`
+}
+
 // SearchRequest defines model for SearchRequest.
 type SearchRequest struct {
-	Limit      *int                     `json:"limit,omitempty"`
-	Query      string                   `json:"query"`
-	SearchType *SearchRequestSearchType `json:"search_type,omitempty"`
+	// Filters Restricts search results; all given filters must match
+	Filters *SearchFilters `json:"filters,omitempty"`
+	Limit   *int           `json:"limit,omitempty"`
+
+	// MmrLambda Diversifies semantic and hybrid results by maximal marginal relevance. 1 keeps the relevance order, lower values push near-duplicate results down. Omit or send 0 to disable.
+	MmrLambda *float64 `json:"mmr_lambda,omitempty"`
+
+	// Query Free text with optional inline operators: site:github.com, tag:golang, folder:dev/tools, after:2024-01, before:2024-06-30, "exact phrase" and -excluded. Operators are combined with filters.
+	Query string `json:"query"`
+
+	// RankingProfile Named ranking profile used to fuse and boost hybrid results (balanced, semantic or keyword). Defaults to the server's RANKING_PROFILE.
+	RankingProfile *string                  `json:"ranking_profile,omitempty"`
+	SearchType     *SearchRequestSearchType `json:"search_type,omitempty"`
 }
 
 // SearchRequestSearchType defines model for SearchRequest.SearchType.
@@ -278,14 +465,19 @@ type SearchRequestSearchType string
 
 // SearchResponse defines model for SearchResponse.
 type SearchResponse struct {
+	// Facets Counts of the returned bookmarks by attribute. A search with only filters counts every bookmark passing them.
+	Facets       *SearchFacets  `json:"facets,omitempty"`
 	Results      []SearchResult `json:"results"`
 	TotalResults int            `json:"total_results"`
 }
 
 // SearchResult defines model for SearchResult.
 type SearchResult struct {
-	Bookmark       Bookmark `json:"bookmark"`
-	RelevanceScore float32  `json:"relevance_score"`
+	Bookmark Bookmark `json:"bookmark"`
+
+	// Passages Best matching content chunks, best first. Only present for semantic matches.
+	Passages       *[]SearchPassage `json:"passages,omitempty"`
+	RelevanceScore float32          `json:"relevance_score"`
 
 	// Snippet Highlighted snippet from the content
 	Snippet *string `json:"snippet,omitempty"`
@@ -309,6 +501,9 @@ type BookmarkId = openapi_types.UUID
 // ConversationId defines model for ConversationId.
 type ConversationId = openapi_types.UUID
 
+// JobId defines model for JobId.
+type JobId = openapi_types.UUID
+
 // BadRequest defines model for BadRequest.
 type BadRequest = Error
 
@@ -318,6 +513,12 @@ type InternalServerError = Error
 // NotFound defines model for NotFound.
 type NotFound = Error
 
+// GetRelatedBookmarksParams defines parameters for GetRelatedBookmarks.
+type GetRelatedBookmarksParams struct {
+	// Limit Maximum number of related bookmarks
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // ListBookmarksParams defines parameters for ListBookmarks.
 type ListBookmarksParams struct {
 	// Page Page number (1-based)
@@ -336,6 +537,36 @@ type ListBookmarksParams struct {
 // ListBookmarksParamsSort defines parameters for ListBookmarks.
 type ListBookmarksParamsSort string
 
+// ListJobsParams defines parameters for ListJobs.
+type ListJobsParams struct {
+	// Type Only jobs of this type (scrape, embed, categorize or link_check)
+	Type *string `form:"type,omitempty" json:"type,omitempty"`
+
+	// State Only jobs in this state (queued, paused, running, succeeded, dead or cancelled)
+	State *string `form:"state,omitempty" json:"state,omitempty"`
+
+	// Page Page number (1-based)
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Number of items per page
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PurgeJobsParams defines parameters for PurgeJobs.
+type PurgeJobsParams struct {
+	// State Finished state to purge (succeeded, dead or cancelled)
+	State *string `form:"state,omitempty" json:"state,omitempty"`
+
+	// Before Only purge jobs that finished before this time
+	Before *time.Time `form:"before,omitempty" json:"before,omitempty"`
+}
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// Types Comma-separated event types to receive; all types when omitted
+	Types *string `form:"types,omitempty" json:"types,omitempty"`
+}
+
 // CategorizeBulkJSONBody defines parameters for CategorizeBulk.
 type CategorizeBulkJSONBody struct {
 	// AutoApply Automatically apply categorizations above threshold
@@ -360,6 +591,9 @@ type StartScrapingJSONBody struct {
 	BookmarkIds []openapi_types.UUID `json:"bookmark_ids"`
 }
 
+// CreateBookmarkJSONRequestBody defines body for CreateBookmark for application/json ContentType.
+type CreateBookmarkJSONRequestBody = BookmarkCreate
+
 // CategorizeBulkJSONRequestBody defines body for CategorizeBulk for application/json ContentType.
 type CategorizeBulkJSONRequestBody CategorizeBulkJSONBody
 
@@ -372,6 +606,9 @@ type UpdateBookmarkJSONRequestBody = BookmarkUpdate
 // SendChatMessageJSONRequestBody defines body for SendChatMessage for application/json ContentType.
 type SendChatMessageJSONRequestBody = ChatRequest
 
+// StreamChatMessageJSONRequestBody defines body for StreamChatMessage for application/json ContentType.
+type StreamChatMessageJSONRequestBody = ChatRequest
+
 // StartScrapingJSONRequestBody defines body for StartScraping for application/json ContentType.
 type StartScrapingJSONRequestBody StartScrapingJSONBody
 
@@ -380,9 +617,15 @@ type SearchBookmarksJSONRequestBody = SearchRequest
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Rebuild full-text search index
+	// (POST /api/admin/search-index/rebuild)
+	RebuildSearchIndex(ctx echo.Context) error
 	// List all bookmarks
 	// (GET /api/bookmarks)
 	ListBookmarks(ctx echo.Context, params ListBookmarksParams) error
+	// Create bookmark
+	// (POST /api/bookmarks)
+	CreateBookmark(ctx echo.Context) error
 	// Bulk categorize bookmarks
 	// (POST /api/bookmarks/categorize/bulk)
 	CategorizeBulk(ctx echo.Context) error
@@ -401,6 +644,9 @@ type ServerInterface interface {
 	// Categorize a single bookmark using AI
 	// (POST /api/bookmarks/{id}/categorize)
 	CategorizeBookmark(ctx echo.Context, id BookmarkId) error
+	// Find related bookmarks
+	// (GET /api/bookmarks/{id}/related)
+	GetRelatedBookmarks(ctx echo.Context, id BookmarkId, params GetRelatedBookmarksParams) error
 	// Re-scrape bookmark content
 	// (POST /api/bookmarks/{id}/rescrape)
 	RescrapeBookmark(ctx echo.Context, id BookmarkId) error
@@ -416,9 +662,30 @@ type ServerInterface interface {
 	// Get conversation history
 	// (GET /api/chat/conversations/{id})
 	GetConversation(ctx echo.Context, id ConversationId) error
+	// Stream chat message
+	// (POST /api/chat/stream)
+	StreamChatMessage(ctx echo.Context) error
+	// Stream progress events
+	// (GET /api/events)
+	StreamEvents(ctx echo.Context, params StreamEventsParams) error
 	// Health check
 	// (GET /api/health)
 	HealthCheck(ctx echo.Context) error
+	// Purge finished jobs
+	// (DELETE /api/jobs)
+	PurgeJobs(ctx echo.Context, params PurgeJobsParams) error
+	// List background jobs
+	// (GET /api/jobs)
+	ListJobs(ctx echo.Context, params ListJobsParams) error
+	// Get job details
+	// (GET /api/jobs/{id})
+	GetJob(ctx echo.Context, id JobId) error
+	// Cancel job
+	// (POST /api/jobs/{id}/cancel)
+	CancelJob(ctx echo.Context, id JobId) error
+	// Retry job
+	// (POST /api/jobs/{id}/retry)
+	RetryJob(ctx echo.Context, id JobId) error
 	// Pause scraping process
 	// (POST /api/scraping/pause)
 	PauseScraping(ctx echo.Context) error
@@ -447,6 +714,15 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// RebuildSearchIndex converts echo context to params.
+func (w *ServerInterfaceWrapper) RebuildSearchIndex(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RebuildSearchIndex(ctx)
+	return err
+}
+
 // ListBookmarks converts echo context to params.
 func (w *ServerInterfaceWrapper) ListBookmarks(ctx echo.Context) error {
 	var err error
@@ -486,6 +762,15 @@ func (w *ServerInterfaceWrapper) ListBookmarks(ctx echo.Context) error {
 	return err
 }
 
+// CreateBookmark converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateBookmark(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateBookmark(ctx)
+	return err
+}
+
 // CategorizeBulk converts echo context to params.
 func (w *ServerInterfaceWrapper) CategorizeBulk(ctx echo.Context) error {
 	var err error
@@ -568,6 +853,31 @@ func (w *ServerInterfaceWrapper) CategorizeBookmark(ctx echo.Context) error {
 	return err
 }
 
+// GetRelatedBookmarks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRelatedBookmarks(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id BookmarkId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRelatedBookmarksParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRelatedBookmarks(ctx, id, params)
+	return err
+}
+
 // RescrapeBookmark converts echo context to params.
 func (w *ServerInterfaceWrapper) RescrapeBookmark(ctx echo.Context) error {
 	var err error
@@ -627,6 +937,33 @@ func (w *ServerInterfaceWrapper) GetConversation(ctx echo.Context) error {
 	return err
 }
 
+// StreamChatMessage converts echo context to params.
+func (w *ServerInterfaceWrapper) StreamChatMessage(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.StreamChatMessage(ctx)
+	return err
+}
+
+// StreamEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) StreamEvents(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamEventsParams
+	// ------------- Optional query parameter "types" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "types", ctx.QueryParams(), &params.Types)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter types: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.StreamEvents(ctx, params)
+	return err
+}
+
 // HealthCheck converts echo context to params.
 func (w *ServerInterfaceWrapper) HealthCheck(ctx echo.Context) error {
 	var err error
@@ -636,6 +973,118 @@ func (w *ServerInterfaceWrapper) HealthCheck(ctx echo.Context) error {
 	return err
 }
 
+// PurgeJobs converts echo context to params.
+func (w *ServerInterfaceWrapper) PurgeJobs(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PurgeJobsParams
+	// ------------- Optional query parameter "state" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "state", ctx.QueryParams(), &params.State)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter state: %s", err))
+	}
+
+	// ------------- Optional query parameter "before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "before", ctx.QueryParams(), &params.Before)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter before: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PurgeJobs(ctx, params)
+	return err
+}
+
+// ListJobs converts echo context to params.
+func (w *ServerInterfaceWrapper) ListJobs(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListJobsParams
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", ctx.QueryParams(), &params.Type)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter type: %s", err))
+	}
+
+	// ------------- Optional query parameter "state" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "state", ctx.QueryParams(), &params.State)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter state: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListJobs(ctx, params)
+	return err
+}
+
+// GetJob converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id JobId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJob(ctx, id)
+	return err
+}
+
+// CancelJob converts echo context to params.
+func (w *ServerInterfaceWrapper) CancelJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id JobId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CancelJob(ctx, id)
+	return err
+}
+
+// RetryJob converts echo context to params.
+func (w *ServerInterfaceWrapper) RetryJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id JobId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RetryJob(ctx, id)
+	return err
+}
+
 // PauseScraping converts echo context to params.
 func (w *ServerInterfaceWrapper) PauseScraping(ctx echo.Context) error {
 	var err error
@@ -727,19 +1176,29 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/api/admin/search-index/rebuild", wrapper.RebuildSearchIndex)
 	router.GET(baseURL+"/api/bookmarks", wrapper.ListBookmarks)
+	router.POST(baseURL+"/api/bookmarks", wrapper.CreateBookmark)
 	router.POST(baseURL+"/api/bookmarks/categorize/bulk", wrapper.CategorizeBulk)
 	router.POST(baseURL+"/api/bookmarks/import", wrapper.ImportBookmarks)
 	router.DELETE(baseURL+"/api/bookmarks/:id", wrapper.DeleteBookmark)
 	router.GET(baseURL+"/api/bookmarks/:id", wrapper.GetBookmark)
 	router.PUT(baseURL+"/api/bookmarks/:id", wrapper.UpdateBookmark)
 	router.POST(baseURL+"/api/bookmarks/:id/categorize", wrapper.CategorizeBookmark)
+	router.GET(baseURL+"/api/bookmarks/:id/related", wrapper.GetRelatedBookmarks)
 	router.POST(baseURL+"/api/bookmarks/:id/rescrape", wrapper.RescrapeBookmark)
 	router.GET(baseURL+"/api/categories", wrapper.GetCategories)
 	router.POST(baseURL+"/api/chat", wrapper.SendChatMessage)
 	router.GET(baseURL+"/api/chat/conversations", wrapper.ListConversations)
 	router.GET(baseURL+"/api/chat/conversations/:id", wrapper.GetConversation)
+	router.POST(baseURL+"/api/chat/stream", wrapper.StreamChatMessage)
+	router.GET(baseURL+"/api/events", wrapper.StreamEvents)
 	router.GET(baseURL+"/api/health", wrapper.HealthCheck)
+	router.DELETE(baseURL+"/api/jobs", wrapper.PurgeJobs)
+	router.GET(baseURL+"/api/jobs", wrapper.ListJobs)
+	router.GET(baseURL+"/api/jobs/:id", wrapper.GetJob)
+	router.POST(baseURL+"/api/jobs/:id/cancel", wrapper.CancelJob)
+	router.POST(baseURL+"/api/jobs/:id/retry", wrapper.RetryJob)
 	router.POST(baseURL+"/api/scraping/pause", wrapper.PauseScraping)
 	router.POST(baseURL+"/api/scraping/resume", wrapper.ResumeScraping)
 	router.POST(baseURL+"/api/scraping/start", wrapper.StartScraping)
@@ -753,65 +1212,120 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xc3W/bOBL/VwjdPewCTuxud4FFni5Nr9sc0m6QXJ+6gUFLI4sbiVRJKq238P9+4Ick",
-	"UqJk2XXqxb3FEj+G85sZzpfyNYpZUTIKVIro4mtUYo4LkMD1r1eMPRaYP14n6lcCIuaklITR6KJ5h65f",
-	"R7OIqEclllk0iyguILqISBLNIg6fKsIhiS4kr2AWiTiDAqvVUsYLLKOLqKr0SLkp1SwhOaHraLudRVeM",
-	"PgEXWG0YosB9/1xUbNVkUTIqwHAEJ3fwqQIh1a+YUQlU/4nLMiexpmX+p1D0fXW2+SeHNLqI/jFvuT03",
-	"b8X835wzbrbqcBgniNvNtrPomkrgFOf3wJ+Am1nPTkO9KRJ6VwRm4Cx6z+QbVtHk+Um4A8EqHgOiTKJU",
-	"76kG2XmumGoB5qwELomBK+aAJSRLLD2wEyzhTJIC+oh3dv/af5/iJxIzuqx47ksQJ6HlUpYnwJdaKkPL",
-	"kWSCHKrjclzueRKJ15oLREIhgpvbB5hzvNG/icwhOLIqk705OYlDW1c9Pxp9VRNnLnje/g/NGmz1J8Ra",
-	"OWoReA0SE70rzvPf0+ji47jUNaKznfVkp5VqXyDvDRTIDkAsRTIDtKqXCh2xQ/CDQ/INEfLO2pi+BNer",
-	"+kBOPFIP3xKvCcW1aI+tctuO7ELUkuQtOAbLB41e/3Q7dW2H8hxPwrcB6q+whDXj5C99wDsQVS4DNobR",
-	"lCRAY1iKmHHoC8zlNWoHIUK1uMTe4tGsVZM0Z1rkC/yFFFURXbyYRQWh5u9FQyetihVoW1xyUmC+Wdol",
-	"N30K3jEhEYccnjCV9dYblDK+Q3QV8lgwqn70XQBOIEXwpcyxkYFaFfyzoQRiIswh+3YNYkYTh/qQZESX",
-	"SULUnzjvnUKNn+0jAFZkOjpdQkxSEiP1WvMFaIZpDAkSgHmc4RXJidzssVVHa3ogzfqS8zAshJuQ4OXG",
-	"Bei6RTnjKGYJ6HN8uEYJEWWONyH2+xdkZ6FaTPQgBaQy9ELionTFdfQKIAG/7QMlnypopVCxQJKUAG9X",
-	"IFTC2ki3ceQGidOvAzuXmAOVIzpxqwf4ypAR4AptEuMcMb7GtNVPWuU5XinjYZzIHVekv9kNFhKZAQdw",
-	"sRJ4DcuYVaHr6L22A0r1GsOMKkHoGsmMCOTIW5e3oavXstPdcb+b+CrD0nGRA3fqFzmq4HZMcxp0/Xo/",
-	"DY+duGA50b0qQKgTq7EFoTdA1+rGebHLW6mnDTNi6F4/hEgOZb4JssB4yMfwEToHNFv2eRo8sDOm9cO+",
-	"3SHfD8LpTHhnwXtmPzikZWZ9h+Y9dczh9Lj76OI2nTPu+vdVoe6tnZLi77SL6nrVEwhIa0f7d833wn0f",
-	"sJtIv+s6Kw1zLWI7BeopY3ZunFyzxGzUwr0FnMtsWPgE8CdizVKHeCzxCps5QJVf+zGq1H2YsM9uJNES",
-	"DsUKkoRQ47xNm2NiZj51QigEEBLLytsy04dWNrGi9d+hzdtb/jB5sTu7C4UwuC5KxkcMgMbR1/zAgLDI",
-	"m/B9Ape65lPRToQkcQj6ymSIQIRVMMUkhyT8TlRxDEKkVZ5vlkSffGioZBLny7ROUQVcnwlY2/10nMsl",
-	"wXnU0PcwGT+HGSEA37UaGY78lxxSH8Cdli7gEdWpjB0RwFENLme5r+FCu/hYCCIkpnI3E032lhmbaQ/h",
-	"kRzi6K2X5PCZmpOCDNj+0reLPVnqOqC9AWXtfezws/VGM0uKP9nfK3S4ex2NDjrYzfkSSLFOV/y0cDMJ",
-	"i4WTS3gRCrc+VWDu5VE/WAXuipClee7sF2WbFde4NVoEBaaSxNEseoTNZ8bVSztqpwQYcsY4MWT5uM7X",
-	"THd6mvXUMUI+oUbGWXUHzPXI7szRswQzTCsnvz3Vq7dpEjcpdXiCSVBSlhAI296SdZaTdSYhQXYQSjkr",
-	"TBaoUdhxiJ3sU5fqIK8klmJ3wnTMzfOimZFxhCbwZdneC52bs3FJlmugwPHgZeSMLIEmNpnWH5djIZd6",
-	"U0j2cBsCNxnjytMV5C9YFqsg+B2QBzBpUwB9lnX408dqq1mYsjryx7E0fNOhYSSqUt3g/6r3ijMsz2NW",
-	"tMW7y9trdG9GRYHyWPwINEFqUMp4my9Q6yCxERIK9JnIDNUGyKbyEKYJen9z2/jjTjFTBe1qxWgWqeOa",
-	"nV6cL84XigBWAsUliS6il+eL85faL5CZFoY5LsncS9avQwrzG0iEkc2aQ4JyIqSfutEUs9ImQ1KSS1BA",
-	"a6IF41KBrinhTWE0UjHgKy8r31ZxP/bzXmtABnb0w4sz5YYnP9bFU2NuGwDsVdVW8Boz/2L8HtnOhtNU",
-	"2hqjEjiyy4d2rm/HwNb73Wh9St5onjosX22QFgTEOPpwdzNAkYHCI6mnir20MuMSpQTyROPHeKKYDufr",
-	"8xn6w/FjLtS0P6IhGBTuYV5013CuXecN1i/6Q9vI0w5xHtghmjH2rflbvwjc2w+dUvlPi8XRisPBWlmg",
-	"VnzbKFZjDZSGKd392ZAT2qUhe+6U97ez6JcpU0JleV2frjMcWjsRznPkFs5MGcItpj2oWb4ZmTeFFJiv",
-	"qtwUuJkYztWTvwAVVS5JmYMj4ISiFZZx1jEuuJLsTIGy6VmUdr1Xal9zOYCyMslmL1T9K1PtuDQ7ulKc",
-	"4lxAF8vLSrICSxLjPN8gPalTWBIIr9gTIJlxEBnLnSBkxVgOmCoUm6uMJKHKknLvXAuMrl8LJFm7FbjJ",
-	"50NCr7rG05Lpnn1x/uus39hSFwubOfqK8wCb6L0NXeyKG+Er2++W2X6jWu92yjsiolbzHKkwlpPQ6BRZ",
-	"d6U9Q/XeSQkP4+H3Se/HiJzFIARMTEoEmoKq/LFbX62ZelIr5xMGBxg7k9AZtnEm1eWYNR1pvCEcUvYF",
-	"/ef+9/fqAr/KOCsAvf3vuxtk5aNr3MxCrsM0bN2MNcVcztViZwmWeEy+U5LDSK+ceo1+qEl1aPzRrQWu",
-	"CMV8szNu0nt9DxUe05lO/jHUQmZgU3Nz0IGik8U7rcyGJUrzdarQfiXJ1gCuDheoMgMvsKIs3yAzBuH2",
-	"rlFOIZECYSFYTLTjoiWsK7Gv9cxXbaDc8fBDjGiHzJ0+zoCb9vOIwBqSQ5j9vBuApkfweIgZRngNKwGY",
-	"ZsPBlzpBy38VnSqlU1aU0DivVGzeNHYpdAqQOAjJbyCfCY/ju822Ghu6TlqgTTnphNgqeFZdeoYALqsA",
-	"wKbNrF2kBk8j6RjqLpZm3vHgPMxVnoKkbaT7zkZ+txwZutqw60C7firR6wjOXtbfCdKGfZdLivON4xV5",
-	"NkZU6zUIt6NNP9ataKaT5/cS6OX1WJT2dzdFA751T5D8cTVrdE/BCeXDCa8xUog44bWFSMOzh9RwMOXp",
-	"YZm5g7MUpM1X1r1jbV5dBa3YFVlfNu7s+v8Hl1TXuJg0BoXPTYnhhKJxB2eG0T3d3iEPfr9r0GG5A8kJ",
-	"PIHOHjnWQZ9ft+ghp7oc8FCu3B7Zb0JwWt9Q3WvYbxbqgXpjM9/DxxJH9i0UEysB3O8criFyHjoYZXgk",
-	"IL0HmiCMbIuMVlMOMRAFGEWX12dNWQjVtJozNj3MbpDsg6fWvsqwrNsDnsetcJs1v7NP4bVHhi6CDMsO",
-	"2+oux5PGjBp0XWIqGmgaGVLi4kvPvNeBN1IXqqtBWt3VFv7kUNnnqjPi+fAa6jkc0233Cz3DQgLi2Kn1",
-	"LpMmo9EE7+PRoncKLYgKH6d1s292nRl737udzx6f1yvrt+wG4HwT5kLDgRPHjB5lGRHSdruHxcD07A2i",
-	"fpVB/IiI+Y7l8vYaEYF4RWldgm3b/3zUTS+knv2cWthpuQyAdW+aLhXdNa2a3S9PQ0PbJOkDZxZBsWVY",
-	"DZYp2ztwad+K0PW8xJUYcZZv1WvjHldcf9hRz0QNUD3Q9KR7Oy46aqHDabSFL7godZtBvRXShwl/59k2",
-	"ITbThkZPqRd0tuwl8o6kh4b9DcttmcMFtuZyAFoOoipGAyH1XoNbn2I3tmbSKcA1x5mMrrUu3wiv3fS5",
-	"8LUQHAiwkHissHOvXrdr1/FtyjgSkEPsxn4BT1nN9nA+RqX6wKqxjekPrxj/vcq1YUFXDHeV8Be/2DdF",
-	"6IVZI8gPXSj1Wqmaeb/MDiqaNloiatL/NiUoI/urKn/8BuWy7B10Y71rUQ+vv9QN7NnzZmvu3dcd7UcU",
-	"sEa+DVm2BaD5GPDWGzz1w4V+Iz9l0mZpEt1idVZytubmvO1zs+K0D0N6Dyx/PXF9GWqwtgPr/x/R6kQm",
-	"ZSku5nP7xLZC9j9srUn3dlrMdrZ4hhhDEl3rbO+gxtdo6rX6EwZWluajhwn3llVhj74Xi8M096rrzNkj",
-	"HDeC6K4+VfFYOXapsXJff1TNOYXHUuM72XgPDN/TFutVnstj0fzf06Tq9uCRGAO40rGmn3iG7PcMM8Q4",
-	"Ml801D3GjI4m99SYaQ0oh8dp/qci3zm91/k6IxgkakbZ5iUvMRqri4nxU6f63rqIuqJjHjiCo7PVYzew",
-	"bUdvs/VOu4FTN6hsRVA31rfWqH8l6+X0lxDPmW3wP7UIYdg91jEVuLd2KE2gv0RSS4Ra3V/DE+SsLLT5",
-	"1aPsPxcyl+3FfJ6zGOcZE/Li18WvC51ss3sMtsQUmOI16DUbXETbq91qfb8d/PL6rGSfgUPSbeELreRU",
-	"JAKd5dbMBOZZ8ezP0Sl1JV5exiy4dYZlaFMDicnomNK1i069vwFn+7D9XwAAAP//s8qNc15OAAA=",
+	"H4sIAAAAAAAC/+R9a28bt7boXyHmXmCnwFh2+tjY1/1yU6dp3ZsmuXGLg4MmcKmZJYnNDDklOXa0C//3",
+	"g7VIzpMzklw5bs/5Zkt8rveLS38kmSorJUFak5z/kVRc8xIsaPrvG6U+lFx/uMzxvxxMpkVlhZLJefMd",
+	"u3yepInAjypuN0maSF5Ccp6IPEkTDb/XQkOenFtdQ5qYbAMlx9VWSpfcJudJXdNIu61wlrFayHVyd5cm",
+	"F0regDYcN4ydoPv9w53iB7WMbf6DWj7Unnc42VRKGnBY4Plb+L0GY/G/TEkLkv7kVVWIjO5/+pvBY/3R",
+	"2eZ/a1gl58n/Om0xfOq+Naffaq2022qAVZ4z7TdzKFgVIvsEG78Fo2qdAeOFBp5vGXwUxho8xKW0oCUv",
+	"rkDfgHYrPPh5wqbM0K4M3MA0eaXsC1XL/BOCRCrLVrQnDvLzuvyJf1daVaCtcDSTaeAW8mtuexSXcwsn",
+	"VpQwJrvB7n+Mv1/xG5EpeV3rok/GWsSWW6kiB31NrBFbTuR7MANeV/PqwJtYviYoCAuliW7uP+Ba8y39",
+	"L2wB0ZF1lR8Myb0gdNeVEb84oYET0y7yevu/b9ZQy98gIw4NJHBBc8aEsAsLUlk3sE97LzTACZ6f0YCU",
+	"Gas05IwbZjfAlkH4d2c9NCLuAVScMwe152C5oGV5UbxeJee/zPNqmJfcpSOOa2VBH5RXjoCZH8DUqgfC",
+	"6B0GB37fOfJLYexbrx7G6A6r9qG+55VGyKj4WkgeBMLcKm/akUMctEfqLTiHlp+J5se32ymhdhD78cjx",
+	"LnL6C25hrbT4N13wLZi6sBHJrORK5CAzuDaZ0jAmmGeXrB3EhCRyyXqLJ2nLB6tCkaAo+UdR1mVy/jRN",
+	"SiHd32fNOWVdLoE0WKVFyfX22i+5HZ/gR2Us01DADZc2bL1lK6V3kC5inhsl8Z+xxagFrBh8rAruaCCw",
+	"Qv9uLIdMmAmBYiBTMu+cPkYZybM8F/gnL0a3wPHpIQTA15ENrirIxEpkDL8muIDccJlBzgxwnW34UhTC",
+	"bg/YasA1IySlY8p5P02E2xjhFUqPr3KBH7NM5UD3+PmS5cJUBd/GwN83KwYLBTKhQYhIVI/G8rLqkuus",
+	"4hQRS/tnKX6voaVCBIEVKwG6XUFIC2tH3c4GnzwcfR3ZueIapJ3hiTc0oM8MGwEasS0yXjCl11y2/Cnr",
+	"ouDLAoL9v8Ow6G/2khvL3IB7QLE2fA3Xmapj6ugVyQFkvUYws9oIuWZ2Iwzr0NsQtjGDxYOzu+Nh9svF",
+	"htsfwfKcWz4+rNPPhvGlqi3bqFuSF1yaW9DslhtWaZXXGeQp4SOHZb1e4100WC3ghhdJOmADDbdaWAvy",
+	"+vcaYoi+slzmvFASPC8zGshqAznt0qydopzOQdIXWpVOmHW90o0wFomFy5y+lHDLSjAIrZ22y/CgU9Dr",
+	"+IYRi+SjnRWPfkxr0F0+P0w+dq97vadJHyBw/gcqqpcg16ivn+4CSJg2DYgpqygTlg4YkeToZBV1Txv5",
+	"wQzhAdo4xHs1rKEqelJ9zii68CsdD24tn8zu2+UpAiIeOoZN52Aew1gcEa+D0/CaU7i7shp4+RyKmBR4",
+	"w7eF4jkiiLNfcxz0K4MbMqa9dbThlhlaY8Twk4b5KyT8bFPLDwH1dGpGTLOLGMOqOy6kJMzeB3ddCeTE",
+	"X3Ml4ZBrdWn6kciR+PE6prQvn4fbNY6jEcaiIdbKvwcj+Acj6yGYekBo90072IkSiP92QhzdgGFcMiEL",
+	"IUfyCCURl0EHWtX3xx05C7RTSiClNCKcMHZfNNOS10Lm8DGCZ/y4kZxuc9k70j8Mg3IJeS7k2nzNVIk6",
+	"LWe3G+iPYxtumFSdwaQ1hTXMh4CIMUnrZ8JCHrX+HIwmrZ6uKA8QhI82ZbBYL9hTUu+/PH0fXfr3WtkI",
+	"M/+Eh2pVB+QODDsFiD9p2sNH2CVKNB3aa+MWfz7sdxi3789UP7oJDx1ti1mlbv3OmQ+0STuQng+3dOXB",
+	"AYK4M+uqLtHPO0jymJ2nDqs+AoG0fseYhT4V3g9BdpNPGIaayPPoHLadAmHKnGU7f1y3RDpr0357EzVb",
+	"nqHTs9ZgjDcWcijEDaCS9WaD+3jCbgj6lDdOwJvO985XHUi4bQUnJkQ8Kme8pMzU2WYUD758TlL757cv",
+	"nbXmRHcSuV3UapCIOQOG3Qq7wXvorb9NVS8LYTaQMyMwLIa7+tSMsVw7fdDQjZD2n19GpTgR0/6JBPpg",
+	"eExCDMPvWii4iy5WQtIpkzSBj7ysClpv9N0+tLx1cHMnI6zFiOQFz8BeBJYbSqdJTrzhRb0HmbphqV8p",
+	"tv/3wAu7mZaQiCPhbbExIS65mwMSg5W/JHWFd1W33fBwi43WNNh/joO93ndCLK5rLLd1b8sNXXqLwkWG",
+	"v2Obt6Gb+wk1v3N3oRgOLstK6RktRcKmr54iA+aSLntAaajj8ezCWJHFUF+7ZCmYOHWuuCggj39n6iwD",
+	"Y1Z1UWyvBd18aqhVlhfXq5CtjcSz9sC134+SF9oKiij5873fG38dYMQQ+INajoHErYWyshMgWnKbbaKu",
+	"13da1ZVhv6mlYSB/r6FG01mtwW5AtwJLSWDLuvgwEtEt8g/2FO5hXASh+BAWScGNvZ6m7QK4gWv4WAkN",
+	"JhqG/Q90UjjTtcSMBoKULSFTJRiWFVyUGN9lfM2FZGJFrsqtIh/NWFUZpkHCrYusAqPd9g7hlvzj9TwB",
+	"VFooLWwkgPm9WG9A46kNWwltbFQR6iY/tb8l8Lq2VW29Zke+gBxyBEtLVpwVQqIfChm6fo4DKL0QMwJ0",
+	"LaOA/5brQoCxFPwm8CHs8UZfs4JbwDwUl0yqW3a7EQWwWy4sQVpReHa7N6DxgBEV79gmZRVHlzENJJC2",
+	"105ZDjxnT5wsQNML8Y8kxzziPmNKs4zLDIoC8v3NC8eQqfOE0zY7BrgeQveaoJukD2ZGO9PDgaZDaWkr",
+	"kwYU2mDyMNv7B7Wc969QiO3tVqEUfagkNh1kZ/76x9b8n4i5aFj1L7RTiA2vc/S4XwiOHkWc7ymZtSr6",
+	"dp+hUEgTJNytWmldWqa9RO/IMfy86ZFCH0WFKMWEsVz1XbqRhTHMlowGVCFwsiOlVrm4qDtKf3J/r+jl",
+	"ar2GH9TSTLNTDgVMWEuDk4SRsY2uKCtGjoeJ5ZNraU0bUbe1lpB3ko3LLQpILZa1hQV7FpJs5PEpWWzZ",
+	"ShQWNCoNWsl5gWE+q5BInE4tF+Oo+ExVwBuXUp+oBJjjoI6TFeGhXJVcHMCP86t1aliOtaRTwnCs5UZV",
+	"NPdfagtcRzD1n/hxL7Bg2C1oYJ6/U0ykgrGNefOnjzIg/x4SWgynSY92CBAd8Ib7zDCNI+1o3N9qkVkT",
+	"2MGZZ+ZrxouCrcUNyIYvytpYVqL9fxD5u4IkP30JhXLmErdkmlpyCDrZ/wNSwEHorizonvifVRVh2hJW",
+	"vgxqv3kOGRGzVxmLFyLIpEzIrKgxWOBSCPWyReKuurFBKSR9iSs71ESWdvPNbPnqYcAJ0w4FTuu/DmWf",
+	"ysAJTjeEPalA5mTSVs13KUO2IcmPtqYzbT+bK+ycIbGMaxe901tm+fqwUqgJ/qF001tY1qLI9yiCdFkr",
+	"yPergvHlFiGnRsHk0840RqtFnSlvgeyznx/KtLodbRm+m94or7VLPpYR8P9EzhL/4BbUDky0sDuXwdRX",
+	"KYpCuDI6s7vIZwzK8WX7p5oWfm/4hHU8m2F8o4zolWfgYLIWxonG1hAcQ85kQ0Y6uHAyXk3z7ccMdGXH",
+	"5/N1Q7dK54hpXlUu+fiuPjv7IsMj01+IsrVZMJdIrK0RuXN58WMmzDuJFqBLftoNt0wYehHw/U8/vjwB",
+	"k6Gc+Jq5PzDz60QG0yBz0CH6AP6Q3NC8xTu5u8KhgxV/9wDFaRxPFiOtWs03p6P7avIubc3yHFacAhaf",
+	"n3VRdnbWQdrTaEq41NcFL5d5rL4MEycGiwlR8ZZcWpFRAmOzXWqRBy2MNittyQtWcr0WncrSDDBx/AGg",
+	"Mt7i9R8zpXPQKSsUppkpkm5YVZsNk8D1SRMDbTbBkPSCvS6FReFrQObsDBk5FwYDTYteTEPVS5fi3Jd4",
+	"J6rdsNDfJ9bJAK98YZgvPkAUcqu0OWdGWDhfC7upl4tMlSnS5/laFRz1htOA5zncnFqlCpMyUnjnn599",
+	"/uXJ2dPUU6X//58nX5yl7B2mSDLLqo3mBt4lBPgT+IjKFfIFex32ZhxNP1UuBXoSdE5PTgiT2RKyNNFc",
+	"fhByfV1ptRJFJNzyipeQMz+M+WGuUsAqtqoN0MGWShk7pIsnS14grvO0JR+l2QfYItd/tmDPHdWaIOFd",
+	"yuofhr199ur/Xb767vrN29cvLl9+u4gXOyMzXLdRIs8BiTtFkrZBar95kiZ+7yQNo3a60tPlhYGnpxTt",
+	"qvEB9+BpN7aJPO7vQjSnwMvH/BFyjDur7tBpYeRw5iwEorX8y877q33riyre1lEM6uMh2PVIiMEWIDFs",
+	"kIGCv7Ngr9FNrjQYHLAiYeGJj6YD8cUBsA2aOQLcRpxdH0GBGimqCmw8ZF2I9QYNTz+oV1Hb1+oTdNx5",
+	"ljA8dRS1lluz24icq2folYPNjCMlet3a5v2d2rTm9RokaD6Z0OqM9NZ7fBwlPjq26J6B4Eg2TGks6TDi",
+	"33BdLqPIHyB5AidtbfgYZAP4jHF1RyBcqVDNyd2DVCipBioxdVUpbf9v2CvbcIsaqn2Q++zNJbtyo5LI",
+	"k9fsA+paHIS81Kml45aZrbFQOqXTcJn30VEtvHr5pik86TyKxopEXDFJE7IwaKeni7PFGR5AVSB5JZLz",
+	"5IvF2eILCiz7cM8pr8Qpz0shT90uJwScU2/NE/UoY2O+V6WquqAXA92KDDxkkCWYND0hZR8cgsBkQjP/",
+	"2NSirWGcA1FxoVmuxQqR5EwB/xY78U5Yxy1LBs+WPz87O9ob2RnvL/Jw1o12d/RuEOH9q7OzqY2ak5/G",
+	"XhvfUd7Zl1SFq3egaTobhrgQamWineQ9Tie89l7nrWOC8DuwjDOfZoCcFcLYgZfaM9OcHYT6AvFslMYs",
+	"2AhZmGT5pvcMr33l/8u4OnkNzLEze/L0ZMmNiwEInxfT25axfMC6RWJjozydN8vv0uG2rYdMiotVoJlf",
+	"PrZziJFHtj7MQRifxHkf/Yg1MTiadj+/fTlxIoeK3pFGInb01kRpVOpQ5IQ/8hjYEyxGRfO4zWac47R3",
+	"yRQaEO9xWAzX6NiMnW84fTEe2qbvzvnwAz+EAHPOO3/TFxGj8/0Dyofo49iIZHjTMFYjH5HDUDh8uY9w",
+	"6LRiOJ48wUNTmLf7UjbIkPYzfAQcF/1X/AYYZxjBKwaSn/LYGBUgKzHTvAqyorElFgyPKHP/uilML8C6",
+	"CuwlRqpAM/hoQaImM4uRgHGvz7/pGmAEpW9Uvj06kt1myV3f1rC6hrsRiT09+u7RrhmNyeBY6J709OXZ",
+	"/9k9pWnJcTwCdADtPeuNUN9IiZ22lQmnWEk0bZs0L6OBlXVhRVV0MztCMqpmGqg2Xlt1gvjajsmtWe8b",
+	"3Pf+5DaouKqtunY7dmXoihdmVArzrLaq5BZffhZbRpMG75jp1eINWmMazEYVnUT4UqkCuBzUWMUeMqMb",
+	"1tX/+D4PbbMW9F1P7/BigvZJcXvM7t3PFv9Kx213/Jz2aiQ5egjb0yecchcQGnFHYBfHn/0JAohFJvoj",
+	"aLWeexbH5V7Y6NPLzjKOWHuBvUoxXZhjfPRxnYLLBMGe5ZIRQYgFhf1rhVjZ4+rY/sFgp6odCztXajot",
+	"41wRbruwc61eCA0r9ZH9cPX6FZqPFxutSqAYPPP0MRRubqGuuT4t3Zw05dqe4mInobZ/ir7jAdBGe+HX",
+	"7Ek4aueMn3VDz0shud7ujMbQXp+Ched4ZlAZHevz5NDW5j279cWPS7NxiiK47ku0f4j8ri38iSTXQJcc",
+	"T1ZsmRvDeN+AFNYwbozKBJnNRGFDin1OMzvW38C/jAGiHXLa6TIXcRK+nCHYHKZw9uVuBDSNvI6HMQeI",
+	"XYZUOu364w1a+AvpmM7lnkPNQQjmIHaaB7JDlHwH9oHwcXynzT9mnLOrw2usR8Qtomc5PM+kn1ZHEOy6",
+	"GrWLBOQRJjuCeohLN+946Hw4z8yd9FML+d105M7VOv339s8eh/QGhHOQ9O84adO2yzPJi+2/u8/IOzLG",
+	"1Os1mG4DJfoYj+Abx7yuQD67nPPS/uqiaMK2HhFSf1wADVVjPyJ9dNzrcRTIoYjQcwDVaChCGiqqrF4I",
+	"39GmmceyQhmkE6wxAi79awxqKqQk+HNQwZzrCNEmsxbsp85KOAaKFROGhVoAIriQc+camKk08JzxTCtj",
+	"mK/sW8S04Ft3kZno9wEkOIrf/ui83BAuVyvmAddzMA6MXz/txq+/2hW+fv/gaZc58/nt8LopK5WxzIhS",
+	"FFz7At1PJG59/Oxhu7L+NO5ZgTVXID1BQ862cEw3gFgtRlYHsbN/5TeTPjxZgfVJzdB5rFOSSG++Ohpo",
+	"mA106/83sDmHtoKLSmLjrnCAR5T0b+HEAXqkqnfQQ78wPCrS37q2ZkCpiHa8uz81eGOdZ6wRUXvR7vEn",
+	"Mbjfs6ZQqz6uHx4h9aVPo05fyxzZVUAg1gY0G78YwNxb+2EHRxs+E1+6ApkzHroneY2YgUCESfbs8qSp",
+	"HWHhrO6OTQfMruzoIw/Xdj2UQmumh/ASus3qPrGL0GsPF7PrNtwOwBZ6OT1qCIiQTnUona6BgYaQXPrU",
+	"czrqRzNTZBBKC4jdcYv+5FgNwcVgxMPha6oDzxxvd+YwB0IB5th52iGQ9sZGE4ubD/70bkGEiPjpNDIa",
+	"i93OjIP17uA3Fh7WyRo3sIqg80UcCg0EHjkEFGvvuYMMfCOe+0h2tMDiop0b5g56coXmGbWkMYt38tmg",
+	"QSJ6Ub7lGrUpds9xFL4RcS8VQgdVqnLZACtVDgUVdGPhOn78TjZeZa9JIT3wCQ8MfPVY6w6Glqfe2wu3",
+	"u3y+eCcvV8xfCaG4ci1eV+5ZP/jGRWRb+9Y+Kaq4XwFRErmXkMYCz92bhoFio6X+BqoNy8hO6WYnLblM",
+	"FxKNuObbTssnFIaeCNKAMcRGD4DmbxYDc5jcUxf6G04J26t6if8ugVnFsHdW21ErzlbsW55tHNz+YaiF",
+	"NNKfsIaaQDV5kpxb/k4KQ9RKU39dMOye5YIWvwbmWIS2NYgf3xzKU3rnk9AZ5df0nWw+oxd5OKgTPXEc",
+	"hh/2M68LH55y3/2mlotwTaKHsCqWALVfPKGgTtsXhulaNn08PsP32pkqS4QNvQ8RhlERupMrX5wx/7Ls",
+	"nbSKHsUwkRfkRUrIXFWEqkAuPGQ7bb1cHw/OskJ4/s6F8fMgJwCip60Be5NDTqLOj6VXUSteFIZZpdiK",
+	"a7aEDbrNpTAGfJ808zUTtK7dgHSnJqniUKzBbGXmmSN9J2833OKl/Jseon/ICd8mJYybjaoL8swVD6KO",
+	"W19shcLTTEskd/tdlZgXqiz5iQEchJtD03fM1+eSknBPhN2n1FXTt9icCDzRwF7gadSoLEjdASGmPSJM",
+	"7lPhd0wxFxcQ/d54XQvNf9BKCde5a1JKXGCTFezrQ0r4zSWSTugERE/EmiZgfQy7jmgXvkXLg1lTg8Zr",
+	"0TJoar2G5w5nJXn8xeOcoW2V1kecW4Q1PW2miqdDL5ipXLZPvgZqdQ2wghRCHLpaSFPyoliwn2V/IMkX",
+	"Yni3fj6OJjcNNnbx7YuwshMIVrEKp7Inw+ZB3f5Ak7W9vgtPrLi3WS9WjTE8lXs0RAdxoCGpGY7qX41S",
+	"uN4/EImdxg1Loj9nNve45CH9inHjkwghtuXldHmP5Mf17ungfYLtMAD9O1ktQM7odKcoFFBtIy5a2kfm",
+	"UWtQvYev5W56aIzd/X1onaiKQKpWnnrQJHqyZxOrz2a01GGl9O1B6F24MJ77nuzdyut+3LjzYP/T3lM8",
+	"JKsPG4bNlvhjy7i/SHX/kmcf1hpdogku7yq53ZEijqt4qxzS0G/OpK79HLl5nZRpLGiEvdIOjRW5X8J8",
+	"aPzGcIo/tvkXKQH6rXOU3Vg8dQJlrjIev2ec9aUUyqJO10l0uzr/kvOJp9V1ZSFv/E+HbvKbuM5jJozb",
+	"7u+F/Isgk/Hqf+28MOIGw1bhN0yDaj9qrQcRzG9quQ/5uV6Yk9T3/8ki5mP1RzdxjU1dDJqtNJgNM+BS",
+	"Bm3fx2Ee2Ort34u8CAZ/E9rynV9G6ErZ4Jf5GgokapSs7nk7PVPtqNlpq7fzpBkiTqck56Yp8w1+TVfK",
+	"ak0/vBZmsobixg4aTrry45KjvgzpNPZvwyVhKy+05/txtdOmRu/zwGKw5ajy+VieCYG/Abl/F9L1zv1X",
+	"MdSiGipnS03we0JuuMVu3LpJj4Fcd529sev19J9Er9/0ofDrUXBPBFN8cCafhV+3azfNB6hPSeHCuTO1",
+	"CDi7h+djPO275zO7pjf6fZ/Y/bXet8UJ3UV7W4R91cPOXkTf/gTHGB70sqzX+aCZ91V6r1dmDZeYcPS/",
+	"zJsdR/tt/uRezOXBO+n+9dQiDW9+bG2858j1C9C7chsdlcAa+u62nI33l9//NyjGv8kglfV1cC7LcRKi",
+	"/knTOTMJP9Oz3298jD7w8O2R6xfR1o9uYPhV/GZwsrG2Muenp/4T35FmdLvm6L2dztKdnXZigMFkW5J2",
+	"dFBjazQP3BBEVlWVa+68h97yLNw739Oz+3HuxdCY81c4ro8+XH1fxlPVnFJT1aH2KM55DIsl4Hdv4T0x",
+	"/EBZTKs8lMVC8D9QpFL1+oyPARp5rGnrlIa+feRO+U5/bhGm5Gz5JI7Z78Xuny/Gf5QCyt0vAa56fat7",
+	"pacZKialH7uY8vsuRruk4z7oEI7ldl4D+65gbT10531mpzK79k+oXCeqRhqNVTItd0XbPiQSex3vYjgc",
+	"XuuYDDxaO5brxSm0RCzX9BxuoFAVVZ+4UYn/hSpStuenp4XKeLFRxp7/6+xfZxRP8ntMviEuueRroDUb",
+	"vJhOwrPh6nGK5dnlSYXNXSEf9jyIrdSp+R4v5bknNs+T53gOFS379m5tTWJ06w23kQW+6WUlmJCmckU6",
+	"YVWrVdGuQqGc8SovewVUTdmFn9QUm/0RpzVXE0Ab9kgjXJ5GJXfv7/5rAPnuDMGqjAAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"bookmark-chat/internal/storage"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: migrate [-db file:bookmarks.db] <status|up>\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  status  List embedded migrations and whether they have been applied\n")
+	fmt.Fprintf(os.Stderr, "  up      Apply all pending migrations\n\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the requested command and returns the process exit code, so deferred cleanup runs
+func run() int {
+	dbPath := flag.String("db", "file:bookmarks.db", "Database connection string")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		usage()
+		return 2
+	}
+
+	// Open without migrating so status reflects the database as it is
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		log.Printf("Failed to open database: %v", err)
+		return 1
+	}
+	defer store.Close()
+
+	switch flag.Arg(0) {
+	case "status":
+		if err := printStatus(store); err != nil {
+			log.Printf("Failed to get migration status: %v", err)
+			return 1
+		}
+	case "up":
+		if err := store.Migrate(); err != nil {
+			log.Printf("Migration failed: %v", err)
+			return 1
+		}
+		fmt.Println("✓ Database is up to date")
+		if err := printStatus(store); err != nil {
+			log.Printf("Failed to get migration status: %v", err)
+			return 1
+		}
+	default:
+		usage()
+		return 2
+	}
+
+	return 0
+}
+
+func printStatus(store *storage.Storage) error {
+	initialized, err := store.MigrationsInitialized()
+	if err != nil {
+		return err
+	}
+	statuses, err := store.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	if !initialized {
+		fmt.Println("Database not initialized; run 'migrate up' to apply migrations")
+	}
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := ""
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%03d  %-30s %-8s %s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return nil
+}
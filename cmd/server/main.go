@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -28,17 +33,33 @@ func main() {
 	}
 	defer store.Close()
 
-	// Create handler instance with storage
-	handler := handlers.NewHandler(store)
+	// Background work (scraping, embedding, categorization, link checks) runs on a persistent job queue
+	jobQueue := services.NewJobQueue(store, services.DefaultJobQueueConfig())
 
-	// Start background processing for pending bookmarks (if OpenAI key is available)
-	if os.Getenv("OPENAI_API_KEY") != "" {
-		log.Println("🤖 OpenAI API key found - starting background embedding processor...")
-		startBackgroundProcessor(store)
-	} else {
-		log.Println("⚠️  No OpenAI API key found - background embedding processing disabled")
-		log.Println("   Set OPENAI_API_KEY environment variable to enable embeddings")
+	// Services publish progress on the event bus, which clients follow through /api/events
+	events := services.NewEventBus()
+	jobQueue.SetEventBus(events)
+
+	// Create handler instance with storage; it registers the job handlers its services support and
+	// restores an interrupted bulk scrape, so it must run before the job queue starts
+	handler := handlers.NewHandler(store, jobQueue, events)
+
+	// Pick up bookmarks that are still pending, e.g. imported while no LLM provider was configured.
+	// Jobs from before a restart are still in the queue and resume on their own.
+	if queued, err := services.EnqueuePendingBookmarks(jobQueue, store); err != nil {
+		log.Printf("❌ Failed to queue pending bookmarks: %v", err)
+	} else if queued > 0 {
+		log.Printf("🔄 Queued %d pending bookmarks for processing", queued)
+	}
+	if !jobQueue.Handles(storage.JobTypeEmbed) {
+		log.Println("⚠️  No LLM provider available - background embedding disabled")
+		log.Println("   Set OPENAI_API_KEY, LLM_BASE_URL or LLM_PROVIDER=local to enable embeddings")
 	}
+	if interval := services.DefaultBackgroundJobsConfig().LinkCheckInterval; interval > 0 {
+		services.ScheduleLinkChecks(jobQueue, store, interval)
+	}
+
+	jobQueue.Start()
 
 	// Register all generated handlers
 	api.RegisterHandlers(e, handler)
@@ -55,10 +76,12 @@ func main() {
 	log.Println("Frontend available at: http://localhost:8080")
 	log.Println("Available endpoints:")
 	log.Println("  GET    /api/bookmarks")
+	log.Println("  POST   /api/bookmarks")
 	log.Println("  POST   /api/bookmarks/import")
 	log.Println("  GET    /api/bookmarks/{id}")
 	log.Println("  PUT    /api/bookmarks/{id}")
 	log.Println("  DELETE /api/bookmarks/{id}")
+	log.Println("  GET    /api/bookmarks/{id}/related")
 	log.Println("  POST   /api/bookmarks/{id}/rescrape")
 	log.Println("  POST   /api/bookmarks/{id}/categorize")
 	log.Println("  POST   /api/bookmarks/categorize/bulk")
@@ -67,71 +90,40 @@ func main() {
 	log.Println("  POST   /api/scraping/resume")
 	log.Println("  POST   /api/scraping/stop")
 	log.Println("  GET    /api/scraping/status")
+	log.Println("  GET    /api/jobs")
+	log.Println("  DELETE /api/jobs")
+	log.Println("  GET    /api/jobs/{id}")
+	log.Println("  POST   /api/jobs/{id}/cancel")
+	log.Println("  POST   /api/jobs/{id}/retry")
 	log.Println("  POST   /api/search")
 	log.Println("  GET    /api/categories")
 	log.Println("  POST   /api/chat")
+	log.Println("  POST   /api/chat/stream")
 	log.Println("  GET    /api/chat/conversations")
 	log.Println("  GET    /api/chat/conversations/{id}")
+	log.Println("  GET    /api/events")
 	log.Println("  GET    /api/health")
 	log.Println("  GET    /api/stats")
+	log.Println("  POST   /api/admin/search-index/rebuild")
 
-	log.Fatal(e.Start(":8080"))
-}
-
-// startBackgroundProcessor starts a background goroutine to process pending bookmarks
-func startBackgroundProcessor(store *storage.Storage) {
 	go func() {
-		// Create content processor
-		processor, err := services.NewContentProcessor(store)
-		if err != nil {
-			log.Printf("❌ Failed to create background ContentProcessor: %v", err)
-			return
-		}
-
-		log.Println("✅ Background embedding processor started")
-		log.Println("   - Checking for pending bookmarks every 30 seconds")
-		log.Println("   - Processing up to 5 bookmarks per batch")
-
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				// Process pending bookmarks in batches
-				bookmarks, err := store.ListBookmarks()
-				if err != nil {
-					log.Printf("❌ Failed to list bookmarks for background processing: %v", err)
-					continue
-				}
-
-				pendingCount := 0
-				processedCount := 0
-				maxBatch := 5 // Process max 5 per cycle to avoid overwhelming
-
-				for _, bookmark := range bookmarks {
-					if bookmark.Status == "pending" {
-						pendingCount++
-						if processedCount >= maxBatch {
-							continue // Skip processing but count total pending
-						}
-
-						log.Printf("🔄 Background processing bookmark: %s", bookmark.URL)
-
-						err := processor.ProcessBookmarkContent(bookmark.ID)
-						if err != nil {
-							log.Printf("❌ Background processing failed for %s: %v", bookmark.URL, err)
-						} else {
-							log.Printf("✅ Background processing completed for %s", bookmark.URL)
-							processedCount++
-						}
-					}
-				}
-
-				if pendingCount > 0 {
-					log.Printf("📊 Background processor: %d pending bookmarks, %d processed this cycle", pendingCount, processedCount)
-				}
-			}
+		if err := e.Start(":8080"); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
 		}
 	}()
+
+	// On shutdown, hand running jobs back to the queue so the next start picks them up
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// Event streams never go idle, so end them before waiting for open connections
+	events.Close()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("❌ Server shutdown failed: %v", err)
+	}
+	jobQueue.Stop()
 }
@@ -4,18 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"bookmark-chat/internal/services"
 	"bookmark-chat/internal/storage"
 )
 
 func main() {
-	// Check if API key is set
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		log.Fatal("OPENAI_API_KEY environment variable must be set")
-	}
-
 	fmt.Println("=== OpenAI Embeddings Test ===\n")
 
 	// Initialize storage
@@ -28,7 +22,12 @@ func main() {
 	fmt.Println("1. Testing embedding generation...")
 
 	// Initialize embedding service directly
-	embeddingService, err := services.NewEmbeddingService()
+	llmProvider, err := services.NewLLMProvider(services.DefaultLLMConfig())
+	if err != nil {
+		log.Fatalf("Failed to create LLM provider: %v", err)
+	}
+
+	embeddingService, err := services.NewEmbeddingService(llmProvider)
 	if err != nil {
 		log.Fatalf("Failed to create embedding service: %v", err)
 	}
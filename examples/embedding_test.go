@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"bookmark-chat/internal/services"
@@ -12,11 +11,6 @@ import (
 )
 
 func main() {
-	// Check if API key is set
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		log.Fatal("OPENAI_API_KEY environment variable must be set")
-	}
-
 	fmt.Println("=== OpenAI Embeddings Test ===\n")
 
 	// Initialize storage
@@ -27,7 +21,12 @@ func main() {
 	defer store.Close()
 
 	// Initialize content processor (includes embedding service)
-	processor, err := services.NewContentProcessor(store)
+	llmProvider, err := services.NewLLMProvider(services.DefaultLLMConfig())
+	if err != nil {
+		log.Fatalf("Failed to create LLM provider: %v", err)
+	}
+
+	processor, err := services.NewContentProcessor(store, llmProvider)
 	if err != nil {
 		log.Fatalf("Failed to create content processor: %v", err)
 	}
@@ -93,6 +93,16 @@ class APIClient {
         return await this.request(`/bookmarks/${id}`);
     }
 
+    /**
+     * Find bookmarks similar to the given one
+     * @param {string} id - Bookmark ID
+     * @param {number} limit - Maximum number of results
+     * @returns {Promise} Related bookmarks in search result format
+     */
+    async getRelatedBookmarks(id, limit = 10) {
+        return await this.request(`/bookmarks/${id}/related?limit=${limit}`);
+    }
+
     /**
      * Delete bookmark by ID
      * @param {string} id - Bookmark ID
@@ -165,10 +175,31 @@ class APIClient {
         return await this.request('/scraping/status');
     }
 
+    /**
+     * Subscribe to live progress events
+     * @param {Object} handlers - Handlers by event type, called with each event's data
+     * @param {Function} onOpen - Called whenever the stream (re)connects or reports missed events; events are not replayed
+     * @returns {EventSource} Open event stream; call close() to unsubscribe
+     */
+    subscribeEvents(handlers, onOpen = null) {
+        const types = Object.keys(handlers).join(',');
+        const source = new EventSource(`${this.baseURL}/events?types=${encodeURIComponent(types)}`);
+
+        for (const [type, handler] of Object.entries(handlers)) {
+            source.addEventListener(type, (e) => handler(JSON.parse(e.data).data));
+        }
+        if (onOpen) {
+            source.addEventListener('open', onOpen);
+            source.addEventListener('events.resync', onOpen);
+        }
+
+        return source;
+    }
+
     /**
      * Search bookmarks using POST method per OpenAPI spec
      * @param {string} query - Search query
-     * @param {Object} options - Search options (limit, searchType)
+     * @param {Object} options - Search options (limit, searchType, filters)
      * @returns {Promise} Search results
      */
     async searchBookmarks(query, options = {}) {
@@ -178,8 +209,11 @@ class APIClient {
         const requestPayload = {
             query: query,
             limit: options.limit || 20,
-            searchType: options.searchType || 'hybrid'
+            search_type: options.searchType || 'hybrid'
         };
+        if (options.filters && Object.keys(options.filters).length > 0) {
+            requestPayload.filters = options.filters;
+        }
         
         console.log('🌐 Search request payload:', requestPayload);
         
@@ -6,7 +6,7 @@ class ScrapingManager {
         this.bookmarks = [];
         this.selectedBookmarks = new Set();
         this.scrapingStatus = 'idle'; // idle, running, paused, stopped
-        this.statusCheckInterval = null;
+        this.eventSource = null;
         this.container = $('#scrapingTree');
         this.init();
     }
@@ -14,6 +14,7 @@ class ScrapingManager {
     async init() {
         await this.loadBookmarks();
         this.bindEvents();
+        this.subscribeToProgress();
         this.updateUI();
     }
 
@@ -257,7 +258,6 @@ class ScrapingManager {
             
             this.scrapingStatus = 'running';
             this.updateUI();
-            this.startStatusChecking();
             
             showToast(`Started scraping ${bookmarkIds.length} bookmarks`, 'success');
             
@@ -292,7 +292,6 @@ class ScrapingManager {
             await this.api.stopScraping();
             this.scrapingStatus = 'stopped';
             this.updateUI();
-            this.stopStatusChecking();
             
             showToast('Scraping stopped', 'info');
             
@@ -302,35 +301,51 @@ class ScrapingManager {
         }
     }
 
-    startStatusChecking() {
-        this.stopStatusChecking(); // Clear any existing interval
-        
-        const interval = (window.CONFIG && window.CONFIG.UI.STATUS_CHECK_INTERVAL) || 2000;
-        
-        this.statusCheckInterval = setInterval(async () => {
-            try {
-                const status = await this.api.getScrapingStatus();
-                this.updateScrapingProgress(status);
-                
-                // Stop checking if scraping is complete
-                if (status.status === 'completed' || status.status === 'stopped') {
-                    this.stopStatusChecking();
-                    this.scrapingStatus = 'idle';
-                    this.updateUI();
-                }
-                
-            } catch (error) {
-                console.error('Failed to get scraping status:', error);
-                this.stopStatusChecking();
-            }
-        }, interval);
+    subscribeToProgress() {
+        this.unsubscribeFromProgress();
+
+        this.eventSource = this.api.subscribeEvents({
+            'scraping.progress': (status) => this.handleScrapingStatus(status),
+            'scrape.started': (event) => this.updateBookmarkStatuses({
+                [event.bookmark_id]: { status: 'in-progress' }
+            }),
+            'scrape.finished': (event) => this.updateBookmarkStatuses({
+                [event.bookmark_id]: { status: 'scraped' }
+            }),
+            'scrape.failed': (event) => this.updateBookmarkStatuses({
+                [event.bookmark_id]: { status: 'error', error: event.error }
+            })
+        }, () => this.syncStatus());
+    }
+
+    unsubscribeFromProgress() {
+        if (this.eventSource) {
+            this.eventSource.close();
+            this.eventSource = null;
+        }
     }
 
-    stopStatusChecking() {
-        if (this.statusCheckInterval) {
-            clearInterval(this.statusCheckInterval);
-            this.statusCheckInterval = null;
+    // Events sent while disconnected or falling behind are not replayed, so catch up whenever the stream
+    // (re)connects or reports missed events
+    async syncStatus() {
+        try {
+            this.handleScrapingStatus(await this.api.getScrapingStatus());
+        } catch (error) {
+            console.error('Failed to get scraping status:', error);
+        }
+    }
+
+    handleScrapingStatus(status) {
+        if (status.status === 'running' || status.status === 'paused') {
+            this.scrapingStatus = status.status;
+            this.updateScrapingProgress(status);
+        } else {
+            this.scrapingStatus = 'idle';
+            if (status.bookmark_statuses) {
+                this.updateBookmarkStatuses(status.bookmark_statuses);
+            }
         }
+        this.updateUI();
     }
 
     updateScrapingProgress(status) {
@@ -479,7 +494,7 @@ class ScrapingManager {
 
     // Clean up when component is destroyed
     destroy() {
-        this.stopStatusChecking();
+        this.unsubscribeFromProgress();
     }
 }
 
@@ -5,6 +5,8 @@ class SearchComponent {
         console.log('🔍 SearchComponent constructor called with apiClient:', apiClient);
         this.apiClient = apiClient;
         this.isSearching = false;
+        // Facet values the user drilled into, keyed by facet name
+        this.activeFacets = {};
         this.init();
     }
 
@@ -31,10 +33,26 @@ class SearchComponent {
             }
         });
 
+        // Facet clicks add a filter, chip clicks remove it
+        $('#searchResults').on('click', '.search-facet-value', (e) => {
+            const $target = $(e.currentTarget);
+            this.toggleFacet($target.data('facet'), String($target.data('value')));
+        });
+        $('#searchResults').on('click', '.search-filter-chip', (e) => {
+            const $target = $(e.currentTarget);
+            this.toggleFacet($target.data('facet'), String($target.data('value')));
+        });
+
+        $('#searchResults').on('click', '.search-result-related', (e) => {
+            const $target = $(e.currentTarget);
+            this.showRelated($target.data('bookmark-id'), $target.data('title'));
+        });
+
         // Clear results when query is empty
         $('#searchQuery').on('input', (e) => {
             console.log('🔍 Search input changed:', e.target.value);
             if (e.target.value.trim() === '') {
+                this.activeFacets = {};
                 this.clearResults();
             }
         });
@@ -76,7 +94,8 @@ class SearchComponent {
             // Call the backend search API using the proper method
             const response = await this.apiClient.searchBookmarks(query, {
                 limit: limit,
-                searchType: searchType
+                searchType: searchType,
+                filters: this.buildFilters()
             });
 
             console.log('Search API response:', response);
@@ -89,6 +108,23 @@ class SearchComponent {
         }
     }
 
+    async showRelated(bookmarkId, title) {
+        if (this.isSearching) return;
+
+        this.showLoading();
+        this.isSearching = true;
+
+        try {
+            const response = await this.apiClient.getRelatedBookmarks(bookmarkId, parseInt($('#searchLimit').val()) || 10);
+            this.displayResults(response, `bookmarks like ${title}`);
+        } catch (error) {
+            console.error('Related search failed:', error);
+            this.showError(`Could not find related bookmarks: ${error.message}`);
+        } finally {
+            this.isSearching = false;
+        }
+    }
+
     showLoading() {
         const $results = $('#searchResults');
         $results.html(`
@@ -104,9 +140,10 @@ class SearchComponent {
         
         if (!response.results || response.results.length === 0) {
             $results.html(`
+                ${this.renderActiveFilters()}
                 <div class="search-no-results">
                     <h3>No results found</h3>
-                    <p>Try adjusting your search query or search type</p>
+                    <p>Try adjusting your search query, search type or filters</p>
                 </div>
             `);
             return;
@@ -117,19 +154,116 @@ class SearchComponent {
         ).join('');
 
         $results.html(`
-            <div class="search-results-header">
-                <p class="text-sm text-secondary mb-4">
-                    Found ${response.total_results} result${response.total_results !== 1 ? 's' : ''} for "${query}"
-                </p>
+            <div class="search-layout">
+                ${this.renderFacets(response.facets)}
+                <div class="search-results-list">
+                    <div class="search-results-header">
+                        <p class="text-sm text-secondary mb-4">
+                            Found ${response.total_results} result${response.total_results !== 1 ? 's' : ''} for "${this.escapeHtml(query)}"
+                        </p>
+                    </div>
+                    ${this.renderActiveFilters()}
+                    ${resultsHtml}
+                </div>
             </div>
-            ${resultsHtml}
         `);
     }
 
+    // Facet groups in sidebar order: API field, facet name and heading
+    static get FACET_GROUPS() {
+        return [
+            { field: 'folder_paths', facet: 'folder', title: 'Folder' },
+            { field: 'domains', facet: 'domain', title: 'Site' },
+            { field: 'categories', facet: 'category', title: 'Category' },
+            { field: 'tags', facet: 'tag', title: 'Tag' },
+            { field: 'statuses', facet: 'status', title: 'Status' },
+            { field: 'years', facet: 'year', title: 'Year' }
+        ];
+    }
+
+    renderFacets(facets) {
+        if (!facets) return '';
+
+        const groups = SearchComponent.FACET_GROUPS
+            .filter(group => facets[group.field] && facets[group.field].length > 0)
+            .map(group => `
+                <div class="search-facet-group">
+                    <h4 class="search-facet-title">${group.title}</h4>
+                    <ul class="search-facet-list">
+                        ${facets[group.field].map(count => `
+                            <li class="search-facet-value ${this.isFacetActive(group.facet, count.value) ? 'active' : ''}"
+                                data-facet="${group.facet}" data-value="${this.escapeHtml(count.value)}">
+                                <span class="search-facet-label">${this.escapeHtml(count.value)}</span>
+                                <span class="search-facet-count">${count.count}</span>
+                            </li>
+                        `).join('')}
+                    </ul>
+                </div>
+            `).join('');
+
+        return groups ? `<aside class="search-facets">${groups}</aside>` : '';
+    }
+
+    renderActiveFilters() {
+        const chips = [];
+        for (const [facet, value] of Object.entries(this.activeFacets)) {
+            const values = Array.isArray(value) ? value : [value];
+            values.forEach(v => chips.push(`
+                <button class="search-filter-chip" data-facet="${facet}" data-value="${this.escapeHtml(v)}" title="Remove filter">
+                    ${facet}: ${this.escapeHtml(v)} ✕
+                </button>
+            `));
+        }
+        return chips.length > 0 ? `<div class="search-active-filters">${chips.join('')}</div>` : '';
+    }
+
+    isFacetActive(facet, value) {
+        const active = this.activeFacets[facet];
+        return Array.isArray(active) ? active.includes(value) : active === value;
+    }
+
+    // Tags accumulate; every other facet holds a single value
+    toggleFacet(facet, value) {
+        if (facet === 'tag') {
+            const tags = this.activeFacets.tag || [];
+            const next = tags.includes(value) ? tags.filter(t => t !== value) : [...tags, value];
+            if (next.length > 0) {
+                this.activeFacets.tag = next;
+            } else {
+                delete this.activeFacets.tag;
+            }
+        } else if (this.activeFacets[facet] === value) {
+            delete this.activeFacets[facet];
+        } else {
+            this.activeFacets[facet] = value;
+        }
+
+        this.performSearch();
+    }
+
+    // Convert the active facets to the API's search filters
+    buildFilters() {
+        const facets = this.activeFacets;
+        const filters = {};
+        if (facets.folder) filters.folder_path = facets.folder;
+        if (facets.domain) filters.domain = facets.domain;
+        if (facets.category) filters.categories = [facets.category];
+        if (facets.tag) filters.tags = facets.tag;
+        if (facets.status) filters.status = facets.status;
+        if (facets.year) {
+            filters.created_after = `${facets.year}-01-01T00:00:00Z`;
+            filters.created_before = `${facets.year}-12-31T23:59:59Z`;
+        }
+        return filters;
+    }
+
     renderSearchResult(result, index) {
         const bookmark = result.bookmark;
         const score = (result.relevance_score * 100).toFixed(1);
         const snippet = result.snippet || this.generateSnippet(bookmark.content, 150);
+        // The snippet already shows the best passage; list the other matching chunks below it
+        const passages = result.passages || [];
+        const morePassages = passages[0] && passages[0].text === snippet ? passages.slice(1) : passages;
         
         // Format dates - check for both null and undefined due to omitempty JSON tag
         const scrapedDate = bookmark.scraped_at ? 
@@ -158,7 +292,17 @@ class SearchComponent {
                 
                 ${snippet ? `
                     <div class="search-result-snippet">
-                        ${this.escapeHtml(snippet)}...
+                        ${this.renderHighlighted(snippet)}
+                    </div>
+                ` : ''}
+                
+                ${morePassages.length > 0 ? `
+                    <div class="search-result-passages">
+                        ${morePassages.map(passage => `
+                            <div class="search-result-passage">
+                                ${this.renderHighlighted(passage.text)}
+                            </div>
+                        `).join('')}
                     </div>
                 ` : ''}
                 
@@ -169,6 +313,10 @@ class SearchComponent {
                     <span class="search-result-date">
                         Scraped: ${scrapedDate}
                     </span>
+                    <button class="search-result-related" data-bookmark-id="${bookmark.id}"
+                            data-title="${this.escapeHtml(bookmark.title || bookmark.url)}">
+                        More like this
+                    </button>
                     ${bookmark.tags ? `
                         <span class="search-result-tags">
                             🏷️ ${JSON.parse(bookmark.tags).map(tag => this.escapeHtml(tag)).join(', ')}
@@ -188,6 +336,13 @@ class SearchComponent {
         return cleanContent.substring(0, maxLength).replace(/\s+\S*$/, '');
     }
 
+    // Escape text but keep the <mark> tags the server puts around matched words
+    renderHighlighted(text) {
+        return this.escapeHtml(text)
+            .replace(/&lt;mark&gt;/g, '<mark>')
+            .replace(/&lt;\/mark&gt;/g, '</mark>');
+    }
+
     escapeHtml(text) {
         if (!text) return '';
         
@@ -17,9 +17,6 @@ const CONFIG = {
     
     // UI Configuration
     UI: {
-        // Status check interval for scraping (milliseconds)
-        STATUS_CHECK_INTERVAL: 2000,
-        
         // Toast notification auto-hide duration (milliseconds)
         TOAST_DURATION: 5000,
         
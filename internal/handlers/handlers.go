@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	api "bookmark-chat/api/generated"
@@ -17,12 +21,17 @@ type Handler struct {
 	importService         *services.ImportService
 	contentProcessor      *services.ContentProcessor
 	categorizationService *services.CategorizationService
+	chatService           *services.ChatService
 	storage               *storage.Storage
 	scraper               services.Scraper
 	bulkScraper           *services.BulkScraper
+	jobs                  *services.JobQueue
+	events                *services.EventBus
 }
 
-func NewHandler(storage *storage.Storage) *Handler {
+// NewHandler creates the API handler and registers job handlers for the services it sets up on jobs.
+// The services publish their progress on events.
+func NewHandler(storage *storage.Storage, jobs *services.JobQueue, events *services.EventBus) *Handler {
 	// Initialize scraper with default config
 	scraperConfig := services.DefaultScraperConfig()
 	scraper, err := services.NewScraper(scraperConfig)
@@ -32,31 +41,78 @@ func NewHandler(storage *storage.Storage) *Handler {
 		scraper = nil
 	}
 
-	// Initialize content processor for embedding generation
-	contentProcessor, err := services.NewContentProcessor(storage)
+	// Initialize the LLM provider used for embeddings, categorization and chat
+	llmProvider, err := services.NewLLMProvider(services.DefaultLLMConfig())
 	if err != nil {
-		fmt.Printf("⚠️  Failed to create ContentProcessor (embeddings disabled): %v\n", err)
-		contentProcessor = nil
-	} else {
-		fmt.Printf("✅ ContentProcessor initialized successfully (embeddings enabled)\n")
+		fmt.Printf("⚠️  Failed to create LLM provider (embeddings, categorization and chat disabled): %v\n", err)
+		llmProvider = nil
 	}
 
-	// Initialize categorization service
-	categorizationService, err := services.NewCategorizationService(storage)
-	if err != nil {
-		fmt.Printf("⚠️  Failed to create CategorizationService (categorization disabled): %v\n", err)
-		categorizationService = nil
-	} else {
-		fmt.Printf("✅ CategorizationService initialized successfully\n")
+	var contentProcessor *services.ContentProcessor
+	var categorizationService *services.CategorizationService
+	var chatService *services.ChatService
+	if llmProvider != nil {
+		// Initialize content processor for embedding generation
+		contentProcessor, err = services.NewContentProcessor(storage, llmProvider)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to create ContentProcessor (embeddings disabled): %v\n", err)
+			contentProcessor = nil
+		} else {
+			contentProcessor.SetEventBus(events)
+			fmt.Printf("✅ ContentProcessor initialized successfully (embeddings enabled)\n")
+		}
+
+		// Initialize categorization service
+		categorizationService, err = services.NewCategorizationService(storage, llmProvider)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to create CategorizationService (categorization disabled): %v\n", err)
+			categorizationService = nil
+		} else {
+			categorizationService.SetEventBus(events)
+			fmt.Printf("✅ CategorizationService initialized successfully\n")
+		}
+
+		// Initialize chat service (requires the content processor for retrieval)
+		if contentProcessor != nil {
+			chatService, err = services.NewChatService(storage, contentProcessor, llmProvider)
+			if err != nil {
+				fmt.Printf("⚠️  Failed to create ChatService (chat disabled): %v\n", err)
+				chatService = nil
+			} else {
+				fmt.Printf("✅ ChatService initialized successfully\n")
+			}
+		}
+	}
+
+	services.RegisterJobHandlers(jobs, services.JobServices{
+		Storage:          storage,
+		Scraper:          scraper,
+		ContentProcessor: contentProcessor,
+		Categorization:   categorizationService,
+		Events:           events,
+	}, services.DefaultBackgroundJobsConfig())
+
+	importService := services.NewImportService(storage)
+	importService.SetEventBus(events)
+
+	// Bring back a bulk scrape interrupted by a restart, paused until the user resumes it
+	bulkScraper := services.NewBulkScraper(storage, jobs, events)
+	if restored, err := bulkScraper.Restore(); err != nil {
+		fmt.Printf("⚠️  Failed to restore bulk scrape: %v\n", err)
+	} else if restored {
+		fmt.Printf("⏸️  Restored interrupted bulk scrape as paused\n")
 	}
 
 	return &Handler{
-		importService:         services.NewImportService(storage),
+		importService:         importService,
 		contentProcessor:      contentProcessor,
 		categorizationService: categorizationService,
+		chatService:           chatService,
 		storage:               storage,
 		scraper:               scraper,
-		bulkScraper:           services.NewBulkScraper(scraper, storage),
+		bulkScraper:           bulkScraper,
+		jobs:                  jobs,
+		events:                events,
 	}
 }
 
@@ -132,7 +188,14 @@ func (h *Handler) ImportBookmarks(ctx echo.Context) error {
 	ctx.Logger().Infof("   📂 Folders: %d", len(parseResult.Folders))
 
 	if importResult.Statistics.SuccessfullyImported > 0 {
-		ctx.Logger().Infof("⚠️  Note: Imported bookmarks are in 'pending' status - use scraping API to generate embeddings")
+		if queued, err := services.EnqueuePendingBookmarks(h.jobs, h.storage); err != nil {
+			ctx.Logger().Errorf("❌ Failed to queue imported bookmarks for processing: %v", err)
+		} else if queued > 0 {
+			ctx.Logger().Infof("🔄 Queued %d pending bookmarks for processing", queued)
+		}
+		if !h.jobs.Handles(storage.JobTypeEmbed) {
+			ctx.Logger().Infof("⚠️  Note: No LLM provider configured - imported bookmarks are scraped but stay 'pending' without embeddings")
+		}
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -141,9 +204,36 @@ func (h *Handler) ImportBookmarks(ctx echo.Context) error {
 // List all bookmarks
 // (GET /api/bookmarks)
 func (h *Handler) ListBookmarks(ctx echo.Context, params api.ListBookmarksParams) error {
-	// Get bookmarks from database
-	bookmarks, err := h.storage.ListBookmarks()
+	query := storage.BookmarkQuery{Page: 1, Limit: 20}
+	if params.Page != nil {
+		query.Page = *params.Page
+	}
+	if params.Limit != nil {
+		query.Limit = *params.Limit
+	}
+	if params.Filter != nil {
+		query.Filter = *params.Filter
+	}
+	if params.Sort != nil {
+		query.Sort = string(*params.Sort)
+	}
+
+	if query.Page < 1 || query.Limit < 1 || query.Limit > 100 {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_pagination",
+			Message: "page must be at least 1 and limit must be between 1 and 100",
+		})
+	}
+
+	// Get the requested page from the database
+	result, err := h.storage.QueryBookmarks(query)
 	if err != nil {
+		if errors.Is(err, storage.ErrInvalidSort) {
+			return ctx.JSON(http.StatusBadRequest, api.Error{
+				Error:   "invalid_sort",
+				Message: err.Error(),
+			})
+		}
 		return ctx.JSON(http.StatusInternalServerError, api.Error{
 			Error:   "database_error",
 			Message: "Failed to retrieve bookmarks from database",
@@ -151,52 +241,100 @@ func (h *Handler) ListBookmarks(ctx echo.Context, params api.ListBookmarksParams
 	}
 
 	// Convert storage bookmarks to API format
-	apiBookmarks := make([]api.Bookmark, len(bookmarks))
-	for i, bookmark := range bookmarks {
-		// Convert string ID to UUID
-		bookmarkUUID, err := uuid.Parse(bookmark.ID)
+	apiBookmarks := make([]api.Bookmark, 0, len(result.Bookmarks))
+	for _, bookmark := range result.Bookmarks {
+		apiBookmark, err := toAPIBookmark(bookmark)
 		if err != nil {
 			ctx.Logger().Errorf("Invalid bookmark UUID: %s", bookmark.ID)
 			continue
 		}
-
-		apiBookmarks[i] = api.Bookmark{
-			Id:          bookmarkUUID,
-			Url:         bookmark.URL,
-			Title:       &bookmark.Title,
-			Description: &bookmark.Description,
-			FolderPath:  &bookmark.FolderPath,
-			FaviconUrl:  &bookmark.FaviconURL,
-			Tags:        &bookmark.Tags,
-			CreatedAt:   bookmark.CreatedAt,
-			UpdatedAt:   bookmark.UpdatedAt,
-			ScrapedAt:   bookmark.ScrapedAt,
-		}
-	}
-
-	totalItems := len(apiBookmarks)
-	page := 1
-	limit := 20
-	if params.Page != nil {
-		page = *params.Page
-	}
-	if params.Limit != nil {
-		limit = *params.Limit
+		apiBookmarks = append(apiBookmarks, apiBookmark)
 	}
 
-	totalPages := (totalItems + limit - 1) / limit
+	totalPages := (result.TotalItems + query.Limit - 1) / query.Limit
 
 	return ctx.JSON(http.StatusOK, api.BookmarkListResponse{
 		Bookmarks: apiBookmarks,
 		Pagination: api.Pagination{
-			Page:       page,
-			Limit:      limit,
+			Page:       query.Page,
+			Limit:      query.Limit,
 			TotalPages: totalPages,
-			TotalItems: totalItems,
+			TotalItems: result.TotalItems,
 		},
 	})
 }
 
+// Create bookmark
+// (POST /api/bookmarks)
+func (h *Handler) CreateBookmark(ctx echo.Context) error {
+	var req api.BookmarkCreate
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+		})
+	}
+
+	bookmarkURL := strings.TrimSpace(req.Url)
+	parsedURL, err := url.Parse(bookmarkURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_url",
+			Message: "url must be an absolute http or https URL",
+		})
+	}
+
+	bookmark := &storage.Bookmark{URL: bookmarkURL}
+	if req.Title != nil {
+		bookmark.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Notes != nil {
+		bookmark.Description = strings.TrimSpace(*req.Notes)
+	}
+	if req.FolderPath != nil {
+		bookmark.FolderPath = strings.Trim(strings.TrimSpace(*req.FolderPath), "/")
+	}
+	if req.Tags != nil {
+		bookmark.Tags = *req.Tags
+	}
+
+	if err := h.storage.CreateBookmark(bookmark); err != nil {
+		var duplicate *storage.DuplicateBookmarkError
+		if errors.As(err, &duplicate) {
+			return ctx.JSON(http.StatusConflict, api.Error{
+				Error:   "bookmark_exists",
+				Message: "A bookmark with this URL already exists",
+				Details: &map[string]interface{}{
+					"bookmark_id": duplicate.BookmarkID,
+				},
+			})
+		}
+		ctx.Logger().Errorf("❌ Failed to create bookmark %s: %v", bookmarkURL, err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to create bookmark",
+		})
+	}
+
+	ctx.Logger().Infof("🔖 Created bookmark %s: %s", bookmark.ID, bookmark.URL)
+
+	// Scrape ahead of background work; the scrape queues embedding when a provider is configured
+	job := &storage.Job{Type: storage.JobTypeScrape, BookmarkID: bookmark.ID, Priority: services.JobPriorityInteractive}
+	if err := h.jobs.Enqueue(job); err != nil {
+		ctx.Logger().Errorf("❌ Failed to queue new bookmark %s for processing: %v", bookmark.URL, err)
+	}
+
+	apiBookmark, err := toAPIBookmark(bookmark)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "internal_error",
+			Message: "Failed to convert bookmark",
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, apiBookmark)
+}
+
 // Get bookmark details
 // (GET /api/bookmarks/{id})
 func (h *Handler) GetBookmark(ctx echo.Context, id api.BookmarkId) error {
@@ -241,24 +379,132 @@ func (h *Handler) UpdateBookmark(ctx echo.Context, id api.BookmarkId) error {
 		})
 	}
 
-	return ctx.JSON(http.StatusOK, api.BookmarkDetail{
-		Id:          id,
-		Url:         "https://example.com",
+	bookmark, textChanged, err := h.storage.UpdateBookmarkMetadata(id.String(), storage.BookmarkUpdate{
 		Title:       req.Title,
 		Description: req.Description,
-		Content:     strPtr("Updated content. Implementation pending."),
-		CreatedAt:   time.Now().Add(-24 * time.Hour),
-		UpdatedAt:   time.Now(),
+		FolderPath:  req.FolderPath,
 		Tags:        req.Tags,
 	})
+	if err != nil {
+		if errors.Is(err, storage.ErrBookmarkNotFound) {
+			return ctx.JSON(http.StatusNotFound, api.Error{
+				Error:   "bookmark_not_found",
+				Message: "Bookmark not found",
+			})
+		}
+		ctx.Logger().Errorf("❌ Failed to update bookmark %s: %v", id, err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to update bookmark",
+		})
+	}
+
+	// Searchable text changed, so queue the bookmark for re-embedding
+	if textChanged {
+		if err := h.storage.UpdateBookmarkStatus(bookmark.ID, "pending"); err != nil {
+			ctx.Logger().Errorf("❌ Failed to queue bookmark %s for re-embedding: %v", id, err)
+		} else {
+			bookmark.Status = "pending"
+			if h.contentProcessor != nil {
+				// Bookmarks that were never scraped need their content first
+				jobType := storage.JobTypeEmbed
+				if _, err := h.storage.GetContent(bookmark.ID); err != nil {
+					jobType = storage.JobTypeScrape
+				}
+				if err := h.jobs.Enqueue(&storage.Job{Type: jobType, BookmarkID: bookmark.ID, Priority: services.JobPriorityInteractive}); err != nil {
+					ctx.Logger().Errorf("❌ Failed to queue bookmark %s for re-embedding: %v", id, err)
+				}
+			}
+			ctx.Logger().Infof("🔄 Bookmark %s queued for re-embedding", id)
+		}
+	}
+
+	// Get content if available
+	var content *string
+	if dbContent, err := h.storage.GetContent(bookmark.ID); err == nil {
+		content = &dbContent.CleanText
+	}
+
+	return ctx.JSON(http.StatusOK, api.BookmarkDetail{
+		Id:          id,
+		Url:         bookmark.URL,
+		Title:       &bookmark.Title,
+		Description: &bookmark.Description,
+		Content:     content,
+		CreatedAt:   bookmark.CreatedAt,
+		UpdatedAt:   bookmark.UpdatedAt,
+		ScrapedAt:   bookmark.ScrapedAt,
+		FolderPath:  &bookmark.FolderPath,
+		FaviconUrl:  &bookmark.FaviconURL,
+		Tags:        &bookmark.Tags,
+	})
 }
 
 // Delete bookmark
 // (DELETE /api/bookmarks/{id})
 func (h *Handler) DeleteBookmark(ctx echo.Context, id api.BookmarkId) error {
+	if err := h.storage.DeleteBookmark(id.String()); err != nil {
+		if errors.Is(err, storage.ErrBookmarkNotFound) {
+			return ctx.JSON(http.StatusNotFound, api.Error{
+				Error:   "bookmark_not_found",
+				Message: "Bookmark not found",
+			})
+		}
+		ctx.Logger().Errorf("❌ Failed to delete bookmark %s: %v", id, err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to delete bookmark",
+		})
+	}
+
+	ctx.Logger().Infof("🗑️  Deleted bookmark %s", id)
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+// Find related bookmarks
+// (GET /api/bookmarks/{id}/related)
+func (h *Handler) GetRelatedBookmarks(ctx echo.Context, id api.BookmarkId, params api.GetRelatedBookmarksParams) error {
+	limit := 10
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 || limit > 50 {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_limit",
+			Message: "limit must be between 1 and 50",
+		})
+	}
+
+	results, err := h.storage.RelatedBookmarks(id.String(), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrBookmarkNotFound):
+			return ctx.JSON(http.StatusNotFound, api.Error{
+				Error:   "bookmark_not_found",
+				Message: "Bookmark not found",
+			})
+		case errors.Is(err, storage.ErrBookmarkNotEmbedded):
+			return ctx.JSON(http.StatusConflict, api.Error{
+				Error:   "bookmark_not_embedded",
+				Message: "Bookmark content has not been processed yet",
+			})
+		}
+		ctx.Logger().Errorf("❌ Failed to find bookmarks related to %s: %v", id, err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "related_search_failed",
+			Message: "Failed to find related bookmarks: " + err.Error(),
+		})
+	}
+
+	apiResults := toAPISearchResults(ctx, results)
+	ctx.Logger().Infof("✅ Found %d bookmarks related to %s", len(apiResults), id)
+
+	return ctx.JSON(http.StatusOK, api.SearchResponse{
+		Results:      apiResults,
+		TotalResults: len(apiResults),
+	})
+}
+
 // Re-scrape bookmark content
 // (POST /api/bookmarks/{id}/rescrape)
 func (h *Handler) RescrapeBookmark(ctx echo.Context, id api.BookmarkId) error {
@@ -325,28 +571,13 @@ func (h *Handler) RescrapeBookmark(ctx echo.Context, id api.BookmarkId) error {
 	} else {
 		ctx.Logger().Infof("✅ Stored content for bookmark %s: %s", bookmark.ID, bookmark.URL)
 
-		// Generate embeddings if ContentProcessor is available
+		// Queue chunked embeddings if ContentProcessor is available
 		if h.contentProcessor != nil {
-			ctx.Logger().Infof("🔄 Generating embeddings for bookmark %s...", bookmark.ID)
-
-			// Get the stored content to get the content ID
-			content, err := h.storage.GetContent(bookmark.ID)
-			if err != nil {
-				ctx.Logger().Errorf("❌ Failed to get content for embedding: %v", err)
+			job := &storage.Job{Type: storage.JobTypeEmbed, BookmarkID: bookmark.ID, Priority: services.JobPriorityInteractive}
+			if err := h.jobs.Enqueue(job); err != nil {
+				ctx.Logger().Errorf("❌ Failed to queue embeddings for bookmark %s: %v", bookmark.ID, err)
 			} else {
-				// Generate embedding for the clean text
-				embedding, err := h.contentProcessor.GenerateQueryEmbedding(content.CleanText)
-				if err != nil {
-					ctx.Logger().Errorf("❌ Failed to generate embedding: %v", err)
-				} else {
-					// Store the embedding
-					err = h.storage.StoreEmbedding(content.ID, embedding)
-					if err != nil {
-						ctx.Logger().Errorf("❌ Failed to store embedding: %v", err)
-					} else {
-						ctx.Logger().Infof("✅ Generated and stored embedding for bookmark %s", bookmark.ID)
-					}
-				}
+				ctx.Logger().Infof("🔄 Queued embeddings for bookmark %s", bookmark.ID)
 			}
 		} else {
 			ctx.Logger().Warnf("⚠️  ContentProcessor not available - embeddings not generated for %s", bookmark.ID)
@@ -383,17 +614,135 @@ func (h *Handler) SearchBookmarks(ctx echo.Context) error {
 
 	ctx.Logger().Infof("🔍 Search request for query: '%s'", req.Query)
 
+	var rankingProfile string
+	if req.RankingProfile != nil {
+		rankingProfile = *req.RankingProfile
+		if _, err := storage.LookupRankingProfile(rankingProfile); err != nil {
+			return ctx.JSON(http.StatusBadRequest, api.Error{
+				Error:   "invalid_ranking_profile",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	limit := 20
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	if limit < 1 || limit > 100 {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_limit",
+			Message: "limit must be between 1 and 100",
+		})
+	}
+
+	searchType := services.SearchTypeHybrid
+	if req.SearchType != nil {
+		searchType = services.SearchType(*req.SearchType)
+	}
+	switch searchType {
+	case services.SearchTypeSemantic, services.SearchTypeKeyword, services.SearchTypeHybrid:
+	default:
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_search_type",
+			Message: "search_type must be one of semantic, keyword or hybrid",
+		})
+	}
+
+	var mmrLambda float64
+	if req.MmrLambda != nil {
+		mmrLambda = *req.MmrLambda
+		if mmrLambda < 0 || mmrLambda > 1 {
+			return ctx.JSON(http.StatusBadRequest, api.Error{
+				Error:   "invalid_mmr_lambda",
+				Message: "mmr_lambda must be between 0 and 1",
+			})
+		}
+	}
+
+	opts := searchOptionsFromFilters(req.Filters)
+	opts.Limit = limit
+
+	// Move inline operators such as site: or tag: into the filters
+	parsed, err := services.ParseSearchQuery(req.Query, opts)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_query",
+			Message: err.Error(),
+		})
+	}
+	queryText, opts := parsed.Text, parsed.Options
+	if strings.TrimSpace(queryText) == "" && !opts.HasFilters() {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_query",
+			Message: "Query must contain search terms or filters",
+		})
+	}
+
 	var results []*storage.SearchResult
-	var err error
 
-	// Try hybrid search if ContentProcessor is available
-	if h.contentProcessor != nil {
+	switch {
+	case strings.TrimSpace(queryText) == "":
+		// Only operators were given, so there is nothing to rank by
+		ctx.Logger().Infof("🔄 Using filter-only search for: '%s'", req.Query)
+		results, err = h.storage.SearchBookmarksWithFilters(opts)
+		if err != nil {
+			ctx.Logger().Errorf("❌ Filtered search failed: %v", err)
+			return ctx.JSON(http.StatusInternalServerError, api.Error{
+				Error:   "search_failed",
+				Message: "Filtered search failed: " + err.Error(),
+			})
+		}
+		ctx.Logger().Infof("✅ Filtered search found %d results", len(results))
+
+	case searchType == services.SearchTypeKeyword:
+		ctx.Logger().Infof("🔄 Using keyword-only search for: '%s'", req.Query)
+		results, err = h.storage.KeywordSearchWithOptions(queryText, opts)
+		if err != nil {
+			ctx.Logger().Errorf("❌ Keyword search failed: %v", err)
+			return ctx.JSON(http.StatusInternalServerError, api.Error{
+				Error:   "search_failed",
+				Message: "Keyword search failed: " + err.Error(),
+			})
+		}
+		ctx.Logger().Infof("✅ Keyword search found %d results", len(results))
+
+	case searchType == services.SearchTypeSemantic:
+		if h.contentProcessor == nil {
+			return ctx.JSON(http.StatusBadRequest, api.Error{
+				Error:   "semantic_search_unavailable",
+				Message: "Semantic search requires an embedding provider",
+			})
+		}
+		ctx.Logger().Infof("🔄 Using semantic-only search for: '%s'", req.Query)
+		results, err = h.contentProcessor.Search(services.SearchQuery{
+			Text:      queryText,
+			Type:      services.SearchTypeSemantic,
+			MMRLambda: mmrLambda,
+			Options:   opts,
+		})
+		if err != nil {
+			ctx.Logger().Errorf("❌ Semantic search failed: %v", err)
+			return ctx.JSON(http.StatusInternalServerError, api.Error{
+				Error:   "search_failed",
+				Message: "Semantic search failed: " + err.Error(),
+			})
+		}
+		ctx.Logger().Infof("✅ Semantic search found %d results", len(results))
+
+	case h.contentProcessor != nil:
 		ctx.Logger().Infof("🔄 Using hybrid search (semantic + keyword) for: '%s'", req.Query)
-		results, err = h.contentProcessor.HybridSearch(req.Query)
+		results, err = h.contentProcessor.Search(services.SearchQuery{
+			Text:           queryText,
+			Type:           services.SearchTypeHybrid,
+			RankingProfile: rankingProfile,
+			MMRLambda:      mmrLambda,
+			Options:        opts,
+		})
 		if err != nil {
 			ctx.Logger().Errorf("❌ Hybrid search failed, falling back to keyword search: %v", err)
 			// Fall back to keyword search
-			results, err = h.storage.KeywordSearch(req.Query, 20)
+			results, err = h.storage.KeywordSearchWithOptions(queryText, opts)
 			if err != nil {
 				ctx.Logger().Errorf("❌ Keyword search also failed: %v", err)
 				return ctx.JSON(http.StatusInternalServerError, api.Error{
@@ -405,10 +754,11 @@ func (h *Handler) SearchBookmarks(ctx echo.Context) error {
 		} else {
 			ctx.Logger().Infof("✅ Hybrid search found %d results", len(results))
 		}
-	} else {
+
+	default:
 		// ContentProcessor not available, use keyword search only
 		ctx.Logger().Infof("🔄 Using keyword-only search for: '%s'", req.Query)
-		results, err = h.storage.KeywordSearch(req.Query, 20)
+		results, err = h.storage.KeywordSearchWithOptions(queryText, opts)
 		if err != nil {
 			ctx.Logger().Errorf("❌ Keyword search failed: %v", err)
 			return ctx.JSON(http.StatusInternalServerError, api.Error{
@@ -420,8 +770,40 @@ func (h *Handler) SearchBookmarks(ctx echo.Context) error {
 	}
 
 	// Convert storage results to API format
-	apiResults := make([]api.SearchResult, len(results))
-	for i, result := range results {
+	apiResults := toAPISearchResults(ctx, results)
+
+	ctx.Logger().Infof("✅ Returning %d search results for query: '%s'", len(apiResults), req.Query)
+
+	response := api.SearchResponse{
+		Results:      apiResults,
+		TotalResults: len(apiResults),
+	}
+
+	// Facets count the bookmarks the search that ran returned; a filter-only search counts every match
+	var facets *storage.SearchFacets
+	if strings.TrimSpace(queryText) == "" {
+		facets, err = h.storage.FilterFacets(opts)
+	} else {
+		resultIDs := make([]string, len(results))
+		for i, result := range results {
+			resultIDs[i] = result.Bookmark.ID
+		}
+		facets, err = h.storage.SearchFacets(resultIDs)
+	}
+	if err != nil {
+		// Results are still useful without facets
+		ctx.Logger().Errorf("❌ Failed to compute search facets: %v", err)
+	} else {
+		response.Facets = toAPISearchFacets(facets)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// toAPISearchResults converts storage search results to API format, skipping invalid bookmark ids
+func toAPISearchResults(ctx echo.Context, results []*storage.SearchResult) []api.SearchResult {
+	apiResults := make([]api.SearchResult, 0, len(results))
+	for _, result := range results {
 		// Convert string ID to UUID
 		bookmarkUUID, err := uuid.Parse(result.Bookmark.ID)
 		if err != nil {
@@ -453,20 +835,87 @@ func (h *Handler) SearchBookmarks(ctx echo.Context) error {
 			RelevanceScore: float32(result.RelevanceScore),
 		}
 
-		// Add snippet if available
+		// Add snippet if available, falling back to the best semantic passage
 		if result.MatchedSnippet != "" {
 			apiResult.Snippet = &result.MatchedSnippet
+		} else if len(result.Passages) > 0 {
+			apiResult.Snippet = &result.Passages[0].Text
+		}
+
+		if len(result.Passages) > 0 {
+			passages := make([]api.SearchPassage, len(result.Passages))
+			for j, passage := range result.Passages {
+				passages[j] = api.SearchPassage{
+					ChunkIndex: passage.ChunkIndex,
+					Text:       passage.Text,
+					Score:      float32(passage.Score),
+				}
+			}
+			apiResult.Passages = &passages
 		}
 
-		apiResults[i] = apiResult
+		apiResults = append(apiResults, apiResult)
 	}
 
-	ctx.Logger().Infof("✅ Returning %d search results for query: '%s'", len(apiResults), req.Query)
+	return apiResults
+}
 
-	return ctx.JSON(http.StatusOK, api.SearchResponse{
-		Results:      apiResults,
-		TotalResults: len(apiResults),
-	})
+// toAPISearchFacets converts storage facets to API format
+func toAPISearchFacets(facets *storage.SearchFacets) *api.SearchFacets {
+	convert := func(counts []storage.FacetCount) []api.FacetCount {
+		apiCounts := make([]api.FacetCount, len(counts))
+		for i, count := range counts {
+			apiCounts[i] = api.FacetCount{Value: count.Value, Count: count.Count}
+		}
+		return apiCounts
+	}
+
+	return &api.SearchFacets{
+		FolderPaths: convert(facets.FolderPaths),
+		Domains:     convert(facets.Domains),
+		Categories:  convert(facets.Categories),
+		Tags:        convert(facets.Tags),
+		Statuses:    convert(facets.Statuses),
+		Years:       convert(facets.Years),
+	}
+}
+
+// searchOptionsFromFilters converts API search filters to storage search options
+func searchOptionsFromFilters(filters *api.SearchFilters) storage.SearchOptions {
+	var opts storage.SearchOptions
+	if filters == nil {
+		return opts
+	}
+
+	if filters.FolderPath != nil {
+		opts.FolderPath = *filters.FolderPath
+	}
+	if filters.Tags != nil {
+		opts.Tags = *filters.Tags
+	}
+	if filters.Categories != nil {
+		opts.Categories = *filters.Categories
+	}
+	if filters.Status != nil {
+		opts.Status = *filters.Status
+	}
+	if filters.Domain != nil {
+		opts.Domain = *filters.Domain
+	}
+	if filters.CreatedAfter != nil {
+		opts.CreatedAfter = *filters.CreatedAfter
+	}
+	if filters.CreatedBefore != nil {
+		opts.CreatedBefore = *filters.CreatedBefore
+	}
+	if filters.ScrapedAfter != nil {
+		opts.ScrapedAfter = *filters.ScrapedAfter
+	}
+	if filters.ScrapedBefore != nil {
+		opts.ScrapedBefore = *filters.ScrapedBefore
+	}
+
+	return opts
 }
 
 // Send chat message
@@ -480,72 +929,327 @@ func (h *Handler) SendChatMessage(ctx echo.Context) error {
 		})
 	}
 
-	conversationId := uuid.New()
-	if req.ConversationId != nil {
-		conversationId = *req.ConversationId
+	conversationID, ok, err := h.prepareChat(ctx, &req)
+	if !ok {
+		return err
 	}
 
-	return ctx.JSON(http.StatusOK, api.ChatResponse{
-		Reply: "Implementation pending. Here's an example of how a response would look like: Based on your bookmarks about " + req.Message + ", I found several relevant resources...",
-		Sources: &[]api.Bookmark{
-			{
-				Id:        uuid.New(),
-				Url:       "https://example.com",
-				Title:     strPtr("Relevant Bookmark"),
-				CreatedAt: time.Now().Add(-24 * time.Hour),
-				UpdatedAt: time.Now().Add(-24 * time.Hour),
-			},
-		},
-		ConversationId: conversationId,
-	})
-}
+	requestCtx := ctx.Request().Context()
+	ctx.Logger().Infof("💬 Answering chat message: '%s'", req.Message)
+
+	answer, err := h.chatService.Answer(requestCtx, conversationID, req.Message, chatContext(&req))
+	if err != nil {
+		ctx.Logger().Errorf("❌ Chat failed: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "chat_failed",
+			Message: err.Error(),
+		})
+	}
+
+	conversationID, _, err = h.saveChatExchange(requestCtx, conversationID, req.Message, answer)
+	if err != nil {
+		ctx.Logger().Errorf("❌ Failed to save chat exchange: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to save chat message",
+		})
+	}
+
+	sources := h.chatSources(ctx, answer)
+	citations := toAPICitations(answer.Citations)
+	ctx.Logger().Infof("✅ Chat answered using %d sources and %d citations (query: '%s')", len(sources), len(citations), answer.RewrittenQuery)
+
+	return ctx.JSON(http.StatusOK, api.ChatResponse{
+		Reply:          answer.Reply,
+		Sources:        &sources,
+		Citations:      &citations,
+		Metadata:       &api.ChatMetadata{RewrittenQuery: answer.RewrittenQuery},
+		ConversationId: uuid.MustParse(conversationID),
+	})
+}
+
+// Stream chat message
+// (POST /api/chat/stream)
+func (h *Handler) StreamChatMessage(ctx echo.Context) error {
+	var req api.ChatRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+		})
+	}
+
+	conversationID, ok, err := h.prepareChat(ctx, &req)
+	if !ok {
+		return err
+	}
+
+	requestCtx := ctx.Request().Context()
+	ctx.Logger().Infof("💬 Streaming answer for chat message: '%s'", req.Message)
+
+	// From here on the response is an event stream; failures are reported as error events
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	answer, err := h.chatService.AnswerStream(requestCtx, conversationID, req.Message, chatContext(&req), func(delta string) error {
+		return writeSSE(res, "delta", api.ChatStreamDelta{Content: delta})
+	})
+	if err != nil {
+		ctx.Logger().Errorf("❌ Chat stream failed: %v", err)
+		return writeSSE(res, "error", api.Error{
+			Error:   "chat_failed",
+			Message: err.Error(),
+		})
+	}
+
+	conversationID, messageID, err := h.saveChatExchange(requestCtx, conversationID, req.Message, answer)
+	if err != nil {
+		ctx.Logger().Errorf("❌ Failed to save chat exchange: %v", err)
+		return writeSSE(res, "error", api.Error{
+			Error:   "database_error",
+			Message: "Failed to save chat message",
+		})
+	}
+
+	sources := h.chatSources(ctx, answer)
+	ctx.Logger().Infof("✅ Chat stream answered using %d sources", len(sources))
+
+	return writeSSE(res, "done", api.ChatStreamDone{
+		ConversationId: uuid.MustParse(conversationID),
+		MessageId:      uuid.MustParse(messageID),
+		Sources:        sources,
+		Citations:      toAPICitations(answer.Citations),
+		Metadata:       &api.ChatMetadata{RewrittenQuery: answer.RewrittenQuery},
+	})
+}
+
+// prepareChat validates a chat request and resolves the conversation it continues.
+// It returns ok=false after writing an error response, in which case err must be returned by the handler.
+func (h *Handler) prepareChat(ctx echo.Context, req *api.ChatRequest) (string, bool, error) {
+	if req.Message == "" {
+		return "", false, ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "bad_request",
+			Message: "Message is required",
+		})
+	}
+
+	if h.chatService == nil {
+		return "", false, ctx.JSON(http.StatusServiceUnavailable, api.Error{
+			Error:   "service_unavailable",
+			Message: "Chat service is not available (LLM provider not configured)",
+		})
+	}
+
+	if req.ConversationId == nil {
+		return "", true, nil
+	}
+
+	conversation, err := h.storage.GetConversation(ctx.Request().Context(), req.ConversationId.String())
+	if err != nil {
+		if errors.Is(err, storage.ErrConversationNotFound) {
+			return "", false, ctx.JSON(http.StatusNotFound, api.Error{
+				Error:   "not_found",
+				Message: "Conversation not found",
+			})
+		}
+		ctx.Logger().Errorf("❌ Failed to load conversation: %v", err)
+		return "", false, ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to load conversation",
+		})
+	}
+
+	return conversation.ID, true, nil
+}
+
+// saveChatExchange stores the user message and the assistant reply, creating the conversation
+// when needed. It returns the conversation ID and the ID of the stored assistant message.
+func (h *Handler) saveChatExchange(requestCtx context.Context, conversationID string, userMessage string, answer *services.ChatAnswer) (string, string, error) {
+	if conversationID == "" {
+		conversation, err := h.storage.CreateConversation(requestCtx, "", conversationTitle(userMessage))
+		if err != nil {
+			return "", "", err
+		}
+		conversationID = conversation.ID
+	}
+
+	bookmarkRefs := make([]string, len(answer.Sources))
+	for i, bookmark := range answer.Sources {
+		bookmarkRefs[i] = bookmark.ID
+	}
+
+	messages := []*storage.Message{
+		{ConversationID: conversationID, Role: "user", Content: userMessage},
+		{ConversationID: conversationID, Role: "assistant", Content: answer.Reply, BookmarkRefs: bookmarkRefs, Citations: answer.Citations},
+	}
+	if err := h.storage.AddMessages(requestCtx, messages...); err != nil {
+		return "", "", err
+	}
+
+	return conversationID, messages[1].ID, nil
+}
+
+// chatSources converts the bookmarks an answer was grounded in to their API representation
+func (h *Handler) chatSources(ctx echo.Context, answer *services.ChatAnswer) []api.Bookmark {
+	sources := make([]api.Bookmark, 0, len(answer.Sources))
+	for _, bookmark := range answer.Sources {
+		apiBookmark, err := toAPIBookmark(bookmark)
+		if err != nil {
+			ctx.Logger().Errorf("Invalid bookmark UUID in chat sources: %s", bookmark.ID)
+			continue
+		}
+		sources = append(sources, apiBookmark)
+	}
+	return sources
+}
+
+// toAPICitations converts stored citations to their API representation
+func toAPICitations(citations []storage.Citation) []api.Citation {
+	apiCitations := make([]api.Citation, 0, len(citations))
+	for _, citation := range citations {
+		bookmarkUUID, err := uuid.Parse(citation.BookmarkID)
+		if err != nil {
+			continue
+		}
+		apiCitations = append(apiCitations, api.Citation{
+			Marker:     citation.Marker,
+			BookmarkId: bookmarkUUID,
+			ChunkIndex: citation.ChunkIndex,
+			Quote:      citation.Quote,
+		})
+	}
+	return apiCitations
+}
+
+// chatContext returns the bookmark IDs pinned to a chat request
+func chatContext(req *api.ChatRequest) []string {
+	if req.Context == nil {
+		return nil
+	}
+	return *req.Context
+}
+
+// writeSSE writes a single Server-Sent Event with a JSON payload and flushes it to the client
+func writeSSE(res *echo.Response, event string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", event, err)
+	}
+
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
+
+// List conversations
+// (GET /api/chat/conversations)
+func (h *Handler) ListConversations(ctx echo.Context) error {
+	conversations, err := h.storage.ListConversations(ctx.Request().Context())
+	if err != nil {
+		ctx.Logger().Errorf("❌ Failed to list conversations: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to retrieve conversations",
+		})
+	}
+
+	summaries := make([]api.ConversationSummary, 0, len(conversations))
+	for _, conversation := range conversations {
+		conversationUUID, err := uuid.Parse(conversation.ID)
+		if err != nil {
+			ctx.Logger().Errorf("Invalid conversation UUID: %s", conversation.ID)
+			continue
+		}
+
+		summaries = append(summaries, api.ConversationSummary{
+			Id:           conversationUUID,
+			Title:        conversation.Title,
+			MessageCount: intPtr(conversation.MessageCount),
+			CreatedAt:    conversation.CreatedAt,
+			UpdatedAt:    conversation.UpdatedAt,
+		})
+	}
 
-// List conversations
-// (GET /api/chat/conversations)
-func (h *Handler) ListConversations(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, api.ConversationListResponse{
-		Conversations: []api.ConversationSummary{
-			{
-				Id:           uuid.New(),
-				Title:        "Example Conversation",
-				MessageCount: intPtr(5),
-				CreatedAt:    time.Now().Add(-2 * time.Hour),
-				UpdatedAt:    time.Now().Add(-1 * time.Hour),
-			},
-			{
-				Id:           uuid.New(),
-				Title:        "Another Chat Session",
-				MessageCount: intPtr(3),
-				CreatedAt:    time.Now().Add(-24 * time.Hour),
-				UpdatedAt:    time.Now().Add(-20 * time.Hour),
-			},
-		},
+		Conversations: summaries,
 	})
 }
 
 // Get conversation history
 // (GET /api/chat/conversations/{id})
 func (h *Handler) GetConversation(ctx echo.Context, id api.ConversationId) error {
+	requestCtx := ctx.Request().Context()
+
+	conversation, err := h.storage.GetConversation(requestCtx, id.String())
+	if err != nil {
+		if errors.Is(err, storage.ErrConversationNotFound) {
+			return ctx.JSON(http.StatusNotFound, api.Error{
+				Error:   "not_found",
+				Message: "Conversation not found",
+			})
+		}
+		ctx.Logger().Errorf("❌ Failed to get conversation %s: %v", id.String(), err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to retrieve conversation",
+		})
+	}
+
+	messages, err := h.storage.GetMessages(requestCtx, conversation.ID, 0)
+	if err != nil {
+		ctx.Logger().Errorf("❌ Failed to get messages for conversation %s: %v", id.String(), err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to retrieve conversation messages",
+		})
+	}
+
+	apiMessages := make([]api.Message, 0, len(messages))
+	for _, message := range messages {
+		messageUUID, err := uuid.Parse(message.ID)
+		if err != nil {
+			ctx.Logger().Errorf("Invalid message UUID: %s", message.ID)
+			continue
+		}
+
+		apiMessage := api.Message{
+			Id:        messageUUID,
+			Role:      api.MessageRole(message.Role),
+			Content:   message.Content,
+			CreatedAt: message.CreatedAt,
+		}
+
+		if len(message.Citations) > 0 {
+			citations := toAPICitations(message.Citations)
+			apiMessage.Citations = &citations
+		}
+
+		if len(message.BookmarkRefs) > 0 {
+			refs := make([]uuid.UUID, 0, len(message.BookmarkRefs))
+			for _, ref := range message.BookmarkRefs {
+				if refUUID, err := uuid.Parse(ref); err == nil {
+					refs = append(refs, refUUID)
+				}
+			}
+			apiMessage.BookmarkRefs = &refs
+		}
+
+		apiMessages = append(apiMessages, apiMessage)
+	}
+
 	return ctx.JSON(http.StatusOK, api.ConversationDetail{
-		Id:    id,
-		Title: "Example Conversation",
-		Messages: []api.Message{
-			{
-				Id:        uuid.New(),
-				Role:      api.User,
-				Content:   "Tell me about my golang bookmarks",
-				CreatedAt: time.Now().Add(-2 * time.Hour),
-			},
-			{
-				Id:           uuid.New(),
-				Role:         api.Assistant,
-				Content:      "Based on your bookmarks, you have several Go-related resources saved...",
-				BookmarkRefs: &[]uuid.UUID{uuid.New()},
-				CreatedAt:    time.Now().Add(-1*time.Hour - 50*time.Minute),
-			},
-		},
-		CreatedAt: time.Now().Add(-2 * time.Hour),
-		UpdatedAt: time.Now().Add(-1 * time.Hour),
+		Id:        id,
+		Title:     conversation.Title,
+		Messages:  apiMessages,
+		CreatedAt: conversation.CreatedAt,
+		UpdatedAt: conversation.UpdatedAt,
 	})
 }
 
@@ -567,6 +1271,28 @@ func (h *Handler) HealthCheck(ctx echo.Context) error {
 	})
 }
 
+// Rebuild full-text search index
+// (POST /api/admin/search-index/rebuild)
+func (h *Handler) RebuildSearchIndex(ctx echo.Context) error {
+	stats, err := h.storage.RebuildSearchIndex(ctx.Request().Context())
+	if err != nil {
+		ctx.Logger().Errorf("❌ Search index rebuild failed: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "index_rebuild_failed",
+			Message: "Failed to rebuild search index",
+		})
+	}
+
+	ctx.Logger().Infof("🔎 Search index rebuilt: %d bookmarks, %d content rows in %v",
+		stats.BookmarksIndexed, stats.ContentIndexed, stats.Duration)
+
+	return ctx.JSON(http.StatusOK, api.SearchIndexRebuildResponse{
+		BookmarksIndexed: stats.BookmarksIndexed,
+		ContentIndexed:   stats.ContentIndexed,
+		DurationMs:       int(stats.Duration.Milliseconds()),
+	})
+}
+
 // System statistics
 // (GET /api/stats)
 func (h *Handler) GetSystemStats(ctx echo.Context) error {
@@ -611,12 +1337,19 @@ func (h *Handler) GetSystemStats(ctx echo.Context) error {
 		contentCount = 0
 	}
 
-	ctx.Logger().Infof("📊 Stats: %d bookmarks (%d pending, %d completed), %d content, %d embeddings",
-		bookmarkCount, pendingCount, completedCount, contentCount, embeddingCount)
+	// Get conversation count
+	conversationCount, err := h.storage.CountConversations(ctx.Request().Context())
+	if err != nil {
+		ctx.Logger().Errorf("❌ Failed to count conversations: %v", err)
+		conversationCount = 0
+	}
+
+	ctx.Logger().Infof("📊 Stats: %d bookmarks (%d pending, %d completed), %d content, %d embeddings, %d conversations",
+		bookmarkCount, pendingCount, completedCount, contentCount, embeddingCount, conversationCount)
 
 	return ctx.JSON(http.StatusOK, api.StatsResponse{
 		BookmarkCount:     bookmarkCount,
-		ConversationCount: 0, // Not implemented yet
+		ConversationCount: conversationCount,
 		IndexStatus: struct {
 			EmbeddingsGenerated *int       `json:"embeddings_generated,omitempty"`
 			EmbeddingsPending   *int       `json:"embeddings_pending,omitempty"`
@@ -631,6 +1364,36 @@ func (h *Handler) GetSystemStats(ctx echo.Context) error {
 }
 
 // Helper functions
+// conversationTitle derives a conversation title from its first message
+func conversationTitle(message string) string {
+	title := []rune(strings.Join(strings.Fields(message), " "))
+	if len(title) > 60 {
+		return string(title[:57]) + "..."
+	}
+	return string(title)
+}
+
+// toAPIBookmark converts a storage bookmark to its API representation
+func toAPIBookmark(bookmark *storage.Bookmark) (api.Bookmark, error) {
+	bookmarkUUID, err := uuid.Parse(bookmark.ID)
+	if err != nil {
+		return api.Bookmark{}, err
+	}
+
+	return api.Bookmark{
+		Id:          bookmarkUUID,
+		Url:         bookmark.URL,
+		Title:       &bookmark.Title,
+		Description: &bookmark.Description,
+		FolderPath:  &bookmark.FolderPath,
+		FaviconUrl:  &bookmark.FaviconURL,
+		Tags:        &bookmark.Tags,
+		CreatedAt:   bookmark.CreatedAt,
+		UpdatedAt:   bookmark.UpdatedAt,
+		ScrapedAt:   bookmark.ScrapedAt,
+	}, nil
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -674,7 +1437,7 @@ func (h *Handler) StartScraping(ctx echo.Context) error {
 		})
 	}
 
-	err := h.bulkScraper.Start(context.Background(), req.BookmarkIds)
+	err := h.bulkScraper.Start(req.BookmarkIds)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, api.Error{
 			Error:   "scraping_failed",
@@ -743,10 +1506,302 @@ func (h *Handler) StopScraping(ctx echo.Context) error {
 // Get scraping status
 // (GET /api/scraping/status)
 func (h *Handler) GetScrapingStatus(ctx echo.Context) error {
-	status := h.bulkScraper.GetStatus()
+	status, err := h.bulkScraper.GetStatus()
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "status_failed",
+			Message: err.Error(),
+		})
+	}
 	return ctx.JSON(http.StatusOK, status)
 }
 
+// Event Handlers
+
+// eventTypes are the event types clients can subscribe to
+var eventTypes = []string{
+	services.EventBookmarkImported, services.EventScrapeStarted, services.EventScrapeFinished, services.EventScrapeFailed,
+	services.EventEmbeddingsStored, services.EventCategorizationSuggested, services.EventJobProgress, services.EventScrapingProgress,
+}
+
+// eventKeepAlive is how often an idle event stream sends a comment so proxies keep it open
+const eventKeepAlive = 30 * time.Second
+
+// Stream progress events
+// (GET /api/events)
+func (h *Handler) StreamEvents(ctx echo.Context, params api.StreamEventsParams) error {
+	wanted := make(map[string]bool)
+	if params.Types != nil {
+		for _, eventType := range strings.Split(*params.Types, ",") {
+			eventType = strings.TrimSpace(eventType)
+			if eventType == "" {
+				continue
+			}
+			if !containsString(eventTypes, eventType) {
+				return ctx.JSON(http.StatusBadRequest, api.Error{
+					Error:   "invalid_event_type",
+					Message: fmt.Sprintf("types must be a comma-separated list of %s", strings.Join(eventTypes, ", ")),
+				})
+			}
+			wanted[eventType] = true
+		}
+	}
+
+	events, unsubscribe := h.events.Subscribe()
+	defer unsubscribe()
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// The server is shutting down
+				return nil
+			}
+			// Resync events are sent whatever the filter, so a client that fell behind reloads its state
+			if len(wanted) > 0 && !wanted[event.Type] && event.Type != services.EventResync {
+				continue
+			}
+			if err := writeSSE(res, event.Type, event); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// Job Handlers
+
+// jobTypes and jobStates are the values accepted by the job filters
+var (
+	jobTypes  = []string{storage.JobTypeScrape, storage.JobTypeEmbed, storage.JobTypeCategorize, storage.JobTypeLinkCheck}
+	jobStates = []string{storage.JobQueued, storage.JobPaused, storage.JobRunning, storage.JobSucceeded, storage.JobDead, storage.JobCancelled}
+)
+
+// List background jobs
+// (GET /api/jobs)
+func (h *Handler) ListJobs(ctx echo.Context, params api.ListJobsParams) error {
+	query := storage.JobQuery{Page: 1, Limit: 20}
+	if params.Page != nil {
+		query.Page = *params.Page
+	}
+	if params.Limit != nil {
+		query.Limit = *params.Limit
+	}
+	if params.Type != nil {
+		query.Type = *params.Type
+	}
+	if params.State != nil {
+		query.State = *params.State
+	}
+
+	if query.Page < 1 || query.Limit < 1 || query.Limit > 100 {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_pagination",
+			Message: "page must be at least 1 and limit must be between 1 and 100",
+		})
+	}
+	if query.Type != "" && !containsString(jobTypes, query.Type) {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_job_type",
+			Message: fmt.Sprintf("type must be one of %s", strings.Join(jobTypes, ", ")),
+		})
+	}
+	if query.State != "" && !containsString(jobStates, query.State) {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_job_state",
+			Message: fmt.Sprintf("state must be one of %s", strings.Join(jobStates, ", ")),
+		})
+	}
+
+	result, err := h.storage.QueryJobs(query)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to retrieve jobs from database",
+		})
+	}
+
+	apiJobs := make([]api.Job, 0, len(result.Jobs))
+	for _, job := range result.Jobs {
+		apiJob, err := toAPIJob(job)
+		if err != nil {
+			ctx.Logger().Errorf("Invalid job UUID: %s", job.ID)
+			continue
+		}
+		apiJobs = append(apiJobs, apiJob)
+	}
+
+	return ctx.JSON(http.StatusOK, api.JobListResponse{
+		Jobs: apiJobs,
+		Pagination: api.Pagination{
+			Page:       query.Page,
+			Limit:      query.Limit,
+			TotalPages: (result.TotalItems + query.Limit - 1) / query.Limit,
+			TotalItems: result.TotalItems,
+		},
+	})
+}
+
+// Purge finished jobs
+// (DELETE /api/jobs)
+func (h *Handler) PurgeJobs(ctx echo.Context, params api.PurgeJobsParams) error {
+	state := storage.JobSucceeded
+	if params.State != nil {
+		state = *params.State
+	}
+	if state != storage.JobSucceeded && state != storage.JobDead && state != storage.JobCancelled {
+		return ctx.JSON(http.StatusBadRequest, api.Error{
+			Error:   "invalid_job_state",
+			Message: "state must be one of succeeded, dead, cancelled",
+		})
+	}
+	var before time.Time
+	if params.Before != nil {
+		before = *params.Before
+	}
+
+	deleted, err := h.storage.PurgeJobs(state, before)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: "Failed to purge jobs",
+		})
+	}
+
+	ctx.Logger().Infof("🧹 Purged %d %s jobs", deleted, state)
+	return ctx.JSON(http.StatusOK, api.PurgeJobsResponse{Deleted: int(deleted)})
+}
+
+// Get job details
+// (GET /api/jobs/{id})
+func (h *Handler) GetJob(ctx echo.Context, id api.JobId) error {
+	job, err := h.storage.GetJob(id.String())
+	if err != nil {
+		return h.jobError(ctx, err)
+	}
+	return h.jobResponse(ctx, job)
+}
+
+// Cancel job
+// (POST /api/jobs/{id}/cancel)
+func (h *Handler) CancelJob(ctx echo.Context, id api.JobId) error {
+	job, err := h.jobs.Cancel(id.String())
+	if err != nil {
+		return h.jobError(ctx, err)
+	}
+	ctx.Logger().Infof("🛑 Cancelled %s job %s", job.Type, job.ID)
+	return h.jobResponse(ctx, job)
+}
+
+// Retry job
+// (POST /api/jobs/{id}/retry)
+func (h *Handler) RetryJob(ctx echo.Context, id api.JobId) error {
+	job, err := h.jobs.Retry(id.String())
+	if err != nil {
+		return h.jobError(ctx, err)
+	}
+	ctx.Logger().Infof("🔁 Requeued %s job %s", job.Type, job.ID)
+	return h.jobResponse(ctx, job)
+}
+
+// jobResponse writes a job as JSON
+func (h *Handler) jobResponse(ctx echo.Context, job *storage.Job) error {
+	apiJob, err := toAPIJob(job)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "invalid_job",
+			Message: err.Error(),
+		})
+	}
+	return ctx.JSON(http.StatusOK, apiJob)
+}
+
+// jobError maps a job storage error to its API response
+func (h *Handler) jobError(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrJobNotFound):
+		return ctx.JSON(http.StatusNotFound, api.Error{
+			Error:   "job_not_found",
+			Message: "Job not found",
+		})
+	case errors.Is(err, storage.ErrJobStateConflict):
+		return ctx.JSON(http.StatusConflict, api.Error{
+			Error:   "job_state_conflict",
+			Message: err.Error(),
+		})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, api.Error{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+	}
+}
+
+// toAPIJob converts a storage job to its API representation
+func toAPIJob(job *storage.Job) (api.Job, error) {
+	jobUUID, err := uuid.Parse(job.ID)
+	if err != nil {
+		return api.Job{}, err
+	}
+
+	apiJob := api.Job{
+		Id:             jobUUID,
+		Type:           job.Type,
+		State:          job.State,
+		Priority:       job.Priority,
+		Attempts:       job.Attempts,
+		MaxAttempts:    job.MaxAttempts,
+		RunAt:          job.RunAt,
+		LeaseExpiresAt: job.LeaseExpiresAt,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+		FinishedAt:     job.FinishedAt,
+	}
+	if bookmarkUUID, err := uuid.Parse(job.BookmarkID); err == nil {
+		apiJob.BookmarkId = &bookmarkUUID
+	}
+	if job.BatchID != "" {
+		apiJob.BatchId = strPtr(job.BatchID)
+	}
+	if job.LastError != "" {
+		apiJob.LastError = strPtr(job.LastError)
+	}
+	if job.Result != "" {
+		var result map[string]interface{}
+		if err := json.Unmarshal([]byte(job.Result), &result); err == nil {
+			apiJob.Result = &result
+		}
+	}
+	return apiJob, nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // Categorization Handlers
 
 // Categorize a single bookmark using AI
@@ -755,7 +1810,7 @@ func (h *Handler) CategorizeBookmark(ctx echo.Context, id api.BookmarkId) error
 	if h.categorizationService == nil {
 		return ctx.JSON(http.StatusServiceUnavailable, api.Error{
 			Error:   "service_unavailable",
-			Message: "Categorization service is not available (OPENAI_API_KEY not configured)",
+			Message: "Categorization service is not available (LLM provider not configured)",
 		})
 	}
 
@@ -791,7 +1846,7 @@ func (h *Handler) CategorizeBulk(ctx echo.Context) error {
 	if h.categorizationService == nil {
 		return ctx.JSON(http.StatusServiceUnavailable, api.Error{
 			Error:   "service_unavailable",
-			Message: "Categorization service is not available (OPENAI_API_KEY not configured)",
+			Message: "Categorization service is not available (LLM provider not configured)",
 		})
 	}
 
@@ -1,12 +1,13 @@
 package services
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
-	"time"
 
 	"bookmark-chat/internal/storage"
+	"github.com/google/uuid"
 )
 
 // ScrapingStatus represents the current state of bulk scraping
@@ -46,267 +47,262 @@ type BookmarkScrapingProgress struct {
 	Error  string                 `json:"error,omitempty"`
 }
 
-// BulkScraper manages bulk scraping operations
+// BulkScraper runs a bulk scrape as a batch of scrape jobs on the job queue and reports its
+// progress, publishing it on the event bus as the run's jobs change state. The run and the status
+// of each of its bookmarks are stored, so a restart does not lose them.
 type BulkScraper struct {
-	scraper  Scraper
-	storage  *storage.Storage
-	mu       sync.RWMutex
-	
-	// Current operation state
-	status           ScrapingStatus
-	bookmarkIDs      []string
-	current          int
-	total            int
-	currentURL       string
-	bookmarkStatuses map[string]BookmarkScrapingProgress
-	
-	// Control channels
-	pauseChan   chan struct{}
-	resumeChan  chan struct{}
-	stopChan    chan struct{}
-	ctx         context.Context
-	cancel      context.CancelFunc
+	storage *storage.Storage
+	jobs    *JobQueue
+	events  *EventBus
+	mu      sync.Mutex
+
+	// Current operation state, mirroring the stored run
+	status  ScrapingStatus
+	batchID string // ID of the current run and batch of its jobs
 }
 
-// NewBulkScraper creates a new bulk scraper
-func NewBulkScraper(scraper Scraper, storage *storage.Storage) *BulkScraper {
-	return &BulkScraper{
-		scraper:          scraper,
-		storage:          storage,
-		status:           StatusIdle,
-		bookmarkStatuses: make(map[string]BookmarkScrapingProgress),
-		pauseChan:        make(chan struct{}, 1),
-		resumeChan:       make(chan struct{}, 1),
-		stopChan:         make(chan struct{}, 1),
+// NewBulkScraper creates a new bulk scraper; events may be nil
+func NewBulkScraper(storage *storage.Storage, jobs *JobQueue, events *EventBus) *BulkScraper {
+	bs := &BulkScraper{
+		storage: storage,
+		jobs:    jobs,
+		events:  events,
+		status:  StatusIdle,
+	}
+	if events != nil {
+		jobEvents, _ := events.Subscribe()
+		go bs.watch(jobEvents)
 	}
+	return bs
 }
 
-// Start begins the bulk scraping process
-func (bs *BulkScraper) Start(ctx context.Context, bookmarkIDs []string) error {
+// Restore loads the latest run. A run that was running or paused when the server stopped comes back
+// paused, along with its jobs, so the user can resume it. Call it before the job queue starts.
+// It reports whether an unfinished run was restored.
+func (bs *BulkScraper) Restore() (bool, error) {
 	bs.mu.Lock()
 	defer bs.mu.Unlock()
-	
-	if bs.status == StatusRunning || bs.status == StatusPaused {
-		return fmt.Errorf("scraping already in progress")
+
+	run, err := bs.storage.LatestScrapeRun()
+	if errors.Is(err, storage.ErrScrapeRunNotFound) {
+		return false, nil
 	}
-	
-	bs.bookmarkIDs = bookmarkIDs
-	bs.current = 0
-	bs.total = len(bookmarkIDs)
-	bs.status = StatusRunning
-	bs.bookmarkStatuses = make(map[string]BookmarkScrapingProgress)
-	bs.ctx, bs.cancel = context.WithCancel(ctx)
-	
-	// Initialize all bookmarks as not-scraped
-	for _, id := range bookmarkIDs {
-		bs.bookmarkStatuses[id] = BookmarkScrapingProgress{
-			Status: BookmarkNotScraped,
+	if err != nil {
+		return false, err
+	}
+
+	bs.batchID = run.ID
+	bs.status = ScrapingStatus(run.Status)
+	if bs.status != StatusRunning && bs.status != StatusPaused {
+		return false, nil
+	}
+
+	if _, err := bs.storage.SuspendBatchJobs(run.ID); err != nil {
+		return false, err
+	}
+	if err := bs.setStatus(StatusPaused); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Start queues a scrape job for each bookmark. Bookmarks that already have a scrape job waiting
+// or running join this run with that job.
+func (bs *BulkScraper) Start(bookmarkIDs []string) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if bs.status == StatusRunning || bs.status == StatusPaused {
+		if _, err := bs.refresh(); err != nil {
+			return err
+		}
+		if bs.status == StatusRunning || bs.status == StatusPaused {
+			return fmt.Errorf("scraping already in progress")
 		}
 	}
-	
-	// Start scraping in background
-	go bs.scrapeAll()
-	
+
+	batchID := uuid.New().String()
+	if err := bs.jobs.EnqueueBookmarks(storage.JobTypeScrape, bookmarkIDs, JobPriorityBackground, batchID); err != nil {
+		return fmt.Errorf("failed to queue scrape jobs: %w", err)
+	}
+	if _, err := bs.storage.CreateScrapeRun(batchID); err != nil {
+		return err
+	}
+
+	bs.batchID = batchID
+	bs.status = StatusRunning
+	bs.publishStatus()
 	return nil
 }
 
-// Pause pauses the current scraping operation
+// Pause holds back the run's queued jobs; jobs already running finish
 func (bs *BulkScraper) Pause() error {
 	bs.mu.Lock()
 	defer bs.mu.Unlock()
-	
+
 	if bs.status != StatusRunning {
 		return fmt.Errorf("no running scraping process to pause")
 	}
-	
-	bs.status = StatusPaused
-	select {
-	case bs.pauseChan <- struct{}{}:
-	default:
+
+	if _, err := bs.storage.TransitionBatchJobs(bs.batchID, storage.JobPaused, storage.JobQueued); err != nil {
+		return err
+	}
+	if err := bs.setStatus(StatusPaused); err != nil {
+		return err
 	}
-	
+	bs.publishStatus()
 	return nil
 }
 
-// Resume resumes a paused scraping operation
+// Resume queues the run's paused jobs again
 func (bs *BulkScraper) Resume() error {
 	bs.mu.Lock()
 	defer bs.mu.Unlock()
-	
+
 	if bs.status != StatusPaused {
 		return fmt.Errorf("no paused scraping process to resume")
 	}
-	
-	bs.status = StatusRunning
-	select {
-	case bs.resumeChan <- struct{}{}:
-	default:
+
+	if _, err := bs.storage.TransitionBatchJobs(bs.batchID, storage.JobQueued, storage.JobPaused); err != nil {
+		return err
+	}
+	if err := bs.setStatus(StatusRunning); err != nil {
+		return err
 	}
-	
+	bs.publishStatus()
+	bs.jobs.notify()
 	return nil
 }
 
-// Stop stops the current scraping operation
+// Stop cancels the run's jobs that have not started; jobs already running finish
 func (bs *BulkScraper) Stop() error {
 	bs.mu.Lock()
 	defer bs.mu.Unlock()
-	
+
 	if bs.status != StatusRunning && bs.status != StatusPaused {
 		return fmt.Errorf("no scraping process to stop")
 	}
-	
-	bs.status = StatusStopped
-	if bs.cancel != nil {
-		bs.cancel()
+
+	if _, err := bs.storage.TransitionBatchJobs(bs.batchID, storage.JobCancelled, storage.JobQueued, storage.JobPaused); err != nil {
+		return err
 	}
-	
-	select {
-	case bs.stopChan <- struct{}{}:
-	default:
+	if err := bs.setStatus(StatusStopped); err != nil {
+		return err
 	}
-	
+	bs.publishStatus()
 	return nil
 }
 
-// GetStatus returns the current scraping status
-func (bs *BulkScraper) GetStatus() BulkScrapingStatus {
-	bs.mu.RLock()
-	defer bs.mu.RUnlock()
-	
-	progress := 0.0
-	if bs.total > 0 {
-		progress = float64(bs.current) / float64(bs.total) * 100
+// GetStatus returns the current scraping status, read from the stored run, with the status of
+// each of its bookmarks
+func (bs *BulkScraper) GetStatus() (BulkScrapingStatus, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	status, err := bs.refresh()
+	if err != nil {
+		return BulkScrapingStatus{}, err
+	}
+
+	status.BookmarkStatuses = make(map[string]BookmarkScrapingProgress)
+	if bs.batchID == "" {
+		return *status, nil
+	}
+	bookmarks, err := bs.storage.ScrapeRunBookmarks(bs.batchID)
+	if err != nil {
+		return BulkScrapingStatus{}, fmt.Errorf("failed to read scraping progress: %w", err)
 	}
-	
-	return BulkScrapingStatus{
-		Status:           bs.status,
-		Current:          bs.current,
-		Total:            bs.total,
-		Progress:         progress,
-		CurrentURL:       bs.currentURL,
-		BookmarkStatuses: bs.bookmarkStatuses,
+	for _, bookmark := range bookmarks {
+		status.BookmarkStatuses[bookmark.BookmarkID] = BookmarkScrapingProgress{
+			Status: BookmarkScrapingStatus(bookmark.Status),
+			Error:  bookmark.Error,
+		}
 	}
+	return *status, nil
 }
 
-// scrapeAll performs the actual bulk scraping
-func (bs *BulkScraper) scrapeAll() {
-	defer func() {
-		bs.mu.Lock()
-		if bs.status == StatusRunning {
-			bs.status = StatusCompleted
-		}
-		bs.mu.Unlock()
-	}()
-	
-	for i, bookmarkID := range bs.bookmarkIDs {
-		// Check for stop signal
-		select {
-		case <-bs.ctx.Done():
-			return
-		case <-bs.stopChan:
-			return
-		default:
+// setStatus stores the run's new status. The caller must hold the lock.
+func (bs *BulkScraper) setStatus(status ScrapingStatus) error {
+	if err := bs.storage.UpdateScrapeRunStatus(bs.batchID, string(status)); err != nil {
+		return err
+	}
+	bs.status = status
+	return nil
+}
+
+// refresh counts the run's progress from the stored status of its bookmarks and marks a running
+// run completed once none of them are left to scrape. The caller must hold the lock.
+func (bs *BulkScraper) refresh() (*BulkScrapingStatus, error) {
+	status := &BulkScrapingStatus{Status: bs.status}
+	if bs.batchID == "" {
+		return status, nil
+	}
+
+	progress, err := bs.storage.ScrapeRunProgress(bs.batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scraping progress: %w", err)
+	}
+	status.Total = progress.Total
+	status.Current = progress.Finished
+	status.CurrentURL = progress.CurrentURL
+
+	if bs.status == StatusRunning && progress.Finished == progress.Total {
+		if err := bs.setStatus(StatusCompleted); err != nil {
+			return nil, err
 		}
-		
-		// Check for pause signal
-		select {
-		case <-bs.pauseChan:
-			// Wait for resume or stop
-			select {
-			case <-bs.resumeChan:
-				// Continue
-			case <-bs.stopChan:
-				return
-			case <-bs.ctx.Done():
-				return
+		status.Status = StatusCompleted
+	}
+	if status.Total > 0 {
+		status.Progress = float64(status.Current) / float64(status.Total) * 100
+	}
+
+	return status, nil
+}
+
+// watch publishes the run's progress whenever one of its jobs changes state, until events closes.
+// Events already waiting are handled together, so a burst of job updates is published once. A resync,
+// sent after missed events, also publishes the progress, so a run still completes when the events of
+// its last jobs were missed.
+func (bs *BulkScraper) watch(events <-chan Event) {
+	for event := range events {
+		resync := false
+		batches := make(map[string]bool)
+		for waiting := len(events); ; waiting-- {
+			if event.Type == EventResync {
+				resync = true
+			} else if progress, ok := event.Data.(JobProgressEvent); ok && progress.BatchID != "" {
+				batches[progress.BatchID] = true
 			}
-		default:
-		}
-		
-		// Update current position
-		bs.mu.Lock()
-		bs.current = i + 1
-		bs.mu.Unlock()
-		
-		// Get bookmark info
-		bookmark, err := bs.storage.GetBookmark(bookmarkID)
-		if err != nil {
-			bs.updateBookmarkStatus(bookmarkID, BookmarkError, fmt.Sprintf("Failed to get bookmark: %v", err))
-			continue
-		}
-		
-		// Update current URL
-		bs.mu.Lock()
-		bs.currentURL = bookmark.URL
-		bs.mu.Unlock()
-		
-		// Update status to in-progress
-		bs.updateBookmarkStatus(bookmarkID, BookmarkInProgress, "")
-		
-		// Create scraper if needed (fallback if nil)
-		scraper := bs.scraper
-		if scraper == nil {
-			scraperConfig := DefaultScraperConfig()
-			var scraperErr error
-			scraper, scraperErr = NewScraper(scraperConfig)
-			if scraperErr != nil {
-				bs.updateBookmarkStatus(bookmarkID, BookmarkError, fmt.Sprintf("Failed to create scraper: %v", scraperErr))
-				continue
+
+			if waiting == 0 {
+				break
 			}
-		}
-		
-		// Scrape the bookmark
-		scrapedContent, err := scraper.Scrape(bs.ctx, bookmark.URL, DefaultScrapeOptions())
-		if err != nil || !scrapedContent.Success {
-			errorMsg := "Failed to scrape content"
-			if scrapedContent != nil && scrapedContent.Error != "" {
-				errorMsg = scrapedContent.Error
-			} else if err != nil {
-				errorMsg = err.Error()
+			next, ok := <-events
+			if !ok {
+				break
 			}
-			bs.updateBookmarkStatus(bookmarkID, BookmarkError, errorMsg)
-			continue
-		}
-		
-		// Update bookmark with scraped data
-		bookmark.Title = scrapedContent.Title
-		bookmark.Description = scrapedContent.Description
-		bookmark.FaviconURL = scrapedContent.FaviconURL
-		bookmark.UpdatedAt = time.Now()
-		now := time.Now()
-		bookmark.ScrapedAt = &now
-		
-		err = bs.storage.UpdateBookmark(bookmark)
-		if err != nil {
-			bs.updateBookmarkStatus(bookmarkID, BookmarkError, fmt.Sprintf("Failed to update bookmark: %v", err))
-			continue
+			event = next
 		}
-		
-		// Store the scraped content
-		err = bs.storage.StoreContent(bookmark.ID, scrapedContent.Content, scrapedContent.CleanText)
-		if err != nil {
-			// Log error but don't fail the scraping
-			fmt.Printf("Failed to store content for bookmark %s: %v\n", bookmark.ID, err)
+
+		bs.mu.Lock()
+		if bs.batchID != "" && (resync || batches[bs.batchID]) {
+			bs.publishStatus()
 		}
-		
-		// Mark as successfully scraped
-		bs.updateBookmarkStatus(bookmarkID, BookmarkScraped, "")
+		bs.mu.Unlock()
 	}
-	
-	// Update final position
-	bs.mu.Lock()
-	bs.current = bs.total
-	bs.currentURL = ""
-	bs.mu.Unlock()
 }
 
-// updateBookmarkStatus updates the status of a specific bookmark
-func (bs *BulkScraper) updateBookmarkStatus(bookmarkID string, status BookmarkScrapingStatus, errorMsg string) {
-	bs.mu.Lock()
-	defer bs.mu.Unlock()
-	
-	bs.bookmarkStatuses[bookmarkID] = BookmarkScrapingProgress{
-		Status: status,
-		Error:  errorMsg,
+// publishStatus publishes the run's progress. Per-bookmark statuses are left out since scrape
+// events already carry them. The caller must hold the lock.
+func (bs *BulkScraper) publishStatus() {
+	if bs.events == nil {
+		return
+	}
+
+	status, err := bs.refresh()
+	if err != nil {
+		log.Printf("❌ Failed to publish scraping progress: %v", err)
+		return
 	}
-}
\ No newline at end of file
+	bs.events.Publish(EventScrapingProgress, *status)
+}
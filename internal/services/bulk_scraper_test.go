@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bookmark-chat/internal/storage"
+)
+
+// addBookmarks creates a bookmark for each URL and returns their IDs
+func addBookmarks(t *testing.T, store *storage.Storage, urls ...string) []string {
+	t.Helper()
+	var ids []string
+	for _, url := range urls {
+		bookmark := &storage.Bookmark{URL: url, Title: url}
+		if err := store.CreateBookmark(bookmark); err != nil {
+			t.Fatalf("Failed to create bookmark: %v", err)
+		}
+		ids = append(ids, bookmark.ID)
+	}
+	return ids
+}
+
+// waitForScrapingStatus reads events until the bulk scrape run reports status, failing the test
+// after a few seconds
+func waitForScrapingStatus(t *testing.T, events <-chan Event, status ScrapingStatus) BulkScrapingStatus {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if progress, ok := event.Data.(BulkScrapingStatus); ok && progress.Status == status {
+				return progress
+			}
+		case <-timeout:
+			t.Fatalf("Expected the scraping run to become %s", status)
+		}
+	}
+}
+
+func TestBulkScraperCompletesRun(t *testing.T) {
+	store := newTestStorage(t)
+	bus := NewEventBus()
+	defer bus.Close()
+
+	queue := NewJobQueue(store, testJobQueueConfig())
+	queue.SetEventBus(bus)
+	queue.Handle(storage.JobTypeScrape, func(ctx context.Context, job *storage.Job) (string, error) {
+		return "", nil
+	})
+	scraper := NewBulkScraper(store, queue, bus)
+	events, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	ids := addBookmarks(t, store, "https://example.com/a", "https://example.com/b", "https://example.com/c")
+	if err := scraper.Start(ids); err != nil {
+		t.Fatalf("Failed to start scraping: %v", err)
+	}
+	queue.Start()
+	defer queue.Stop()
+
+	completed := waitForScrapingStatus(t, events, StatusCompleted)
+	if completed.Current != 3 || completed.Total != 3 || completed.Progress != 100 {
+		t.Errorf("Expected 3 of 3 bookmarks scraped, got %+v", completed)
+	}
+
+	status, err := scraper.GetStatus()
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	if status.Status != StatusCompleted || len(status.BookmarkStatuses) != 3 {
+		t.Fatalf("Expected a completed run with 3 bookmark statuses, got %+v", status)
+	}
+	for id, progress := range status.BookmarkStatuses {
+		if progress.Status != BookmarkScraped {
+			t.Errorf("Expected bookmark %s to be scraped, got %s", id, progress.Status)
+		}
+	}
+}
+
+func TestBulkScraperCompletesAfterMissedEvents(t *testing.T) {
+	store := newTestStorage(t)
+	bus := NewEventBus()
+	defer bus.Close()
+
+	queue := NewJobQueue(store, testJobQueueConfig())
+	scraper := NewBulkScraper(store, queue, bus)
+	events, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	ids := addBookmarks(t, store, "https://example.com/a", "https://example.com/b")
+	if err := scraper.Start(ids); err != nil {
+		t.Fatalf("Failed to start scraping: %v", err)
+	}
+
+	// The run's jobs finish without any job event reaching the scraper
+	for range ids {
+		job, err := store.ClaimJob("worker", []string{storage.JobTypeScrape}, time.Minute, 0)
+		if err != nil || job == nil {
+			t.Fatalf("Failed to claim job: %v", err)
+		}
+		if err := store.CompleteJob(job.ID, "worker", ""); err != nil {
+			t.Fatalf("Failed to complete job: %v", err)
+		}
+	}
+
+	bus.Publish(EventResync, nil)
+	if completed := waitForScrapingStatus(t, events, StatusCompleted); completed.Current != 2 {
+		t.Errorf("Expected both bookmarks scraped, got %+v", completed)
+	}
+}
@@ -9,42 +9,43 @@ import (
 	"time"
 
 	"bookmark-chat/internal/storage"
-	"github.com/sashabaranov/go-openai"
 )
 
 // CategorizationService handles AI-powered bookmark categorization
 type CategorizationService struct {
-	storage      *storage.Storage
-	openaiClient *openai.Client
-	model        string
+	storage  *storage.Storage
+	provider ChatProvider
+	model    string
+	events   *EventBus
 }
 
-// Message represents a chat message for OpenAI API
+// Message represents a chat message sent to a chat provider
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
 }
 
 // NewCategorizationService creates a new categorization service
-func NewCategorizationService(storage *storage.Storage) (*CategorizationService, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is required for categorization")
+func NewCategorizationService(storage *storage.Storage, provider ChatProvider) (*CategorizationService, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("chat provider is required for categorization")
 	}
 
-	client := openai.NewClient(apiKey)
+	// Optional override; the provider's chat model is used when empty
 	model := os.Getenv("CATEGORIZATION_MODEL")
-	if model == "" {
-		model = "gpt-4o-mini" // Cost-effective model for categorization
-	}
 
 	return &CategorizationService{
-		storage:      storage,
-		openaiClient: client,
-		model:        model,
+		storage:  storage,
+		provider: provider,
+		model:    model,
 	}, nil
 }
 
+// SetEventBus publishes an event whenever categories are suggested for a bookmark
+func (cs *CategorizationService) SetEventBus(events *EventBus) {
+	cs.events = events
+}
+
 // CategorizeBookmark uses GPT to categorize a bookmark based on its content
 func (cs *CategorizationService) CategorizeBookmark(ctx context.Context, bookmarkID string) (*storage.CategorizationResult, error) {
 	// Get bookmark with content
@@ -63,13 +64,13 @@ func (cs *CategorizationService) CategorizeBookmark(ctx context.Context, bookmar
 	// Build categorization prompt
 	prompt := cs.buildCategorizationPrompt(bookmark, categories)
 
-	// Call OpenAI API
+	// Call the chat provider
 	response, err := cs.createChatCompletion(ctx, []Message{
 		{Role: "system", Content: "You are an expert bookmark categorization assistant. Analyze the provided bookmark and respond only with valid JSON in the exact format requested. Do not include any explanatory text outside the JSON."},
 		{Role: "user", Content: prompt},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("openai completion: %w", err)
+		return nil, fmt.Errorf("chat completion: %w", err)
 	}
 
 	// Parse response
@@ -91,6 +92,7 @@ func (cs *CategorizationService) CategorizeBookmark(ctx context.Context, bookmar
 		return nil, fmt.Errorf("save categorization: %w", err)
 	}
 
+	cs.events.Publish(EventCategorizationSuggested, CategorizationSuggestedEvent{BookmarkID: bookmarkID, CategorizationResult: result})
 	return &result, nil
 }
 
@@ -99,7 +101,7 @@ func (cs *CategorizationService) BulkCategorize(ctx context.Context, bookmarkIDs
 	results := make([]storage.CategorizationResult, 0, len(bookmarkIDs))
 	appliedCount := 0
 	
-	// Rate limiting: 30 requests per minute for OpenAI API
+	// Rate limiting: 30 requests per minute for the chat provider
 	rateLimiter := time.NewTicker(2 * time.Second) // ~30 per minute
 	defer rateLimiter.Stop()
 
@@ -138,35 +140,16 @@ func (cs *CategorizationService) BulkCategorize(ctx context.Context, bookmarkIDs
 	return results, nil
 }
 
-// createChatCompletion creates a chat completion using OpenAI API
+// createChatCompletion creates a chat completion using the chat provider
 func (cs *CategorizationService) createChatCompletion(ctx context.Context, messages []Message) (string, error) {
-	// Convert our Message type to OpenAI's ChatCompletionMessage
-	openaiMessages := make([]openai.ChatCompletionMessage, len(messages))
-	for i, msg := range messages {
-		openaiMessages[i] = openai.ChatCompletionMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-	}
-
-	req := openai.ChatCompletionRequest{
+	return cs.provider.ChatCompletion(ctx, ChatCompletionRequest{
 		Model:       cs.model,
-		Messages:    openaiMessages,
+		Purpose:     ChatPurposeCategorize,
+		Messages:    messages,
 		MaxTokens:   500,
 		Temperature: 0.3, // Lower temperature for more consistent categorization
 		TopP:        0.9,
-	}
-
-	resp, err := cs.openaiClient.CreateChatCompletion(ctx, req)
-	if err != nil {
-		return "", fmt.Errorf("chat completion request failed: %w", err)
-	}
-
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response choices returned")
-	}
-
-	return resp.Choices[0].Message.Content, nil
+	})
 }
 
 // buildCategorizationPrompt creates a detailed prompt for categorization
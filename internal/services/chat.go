@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"bookmark-chat/internal/storage"
+)
+
+const (
+	// Number of bookmarks considered when building the chat context
+	chatMaxSourceBookmarks = 8
+	// Number of content chunks placed in the prompt
+	chatMaxContextChunks = 12
+	// Maximum characters of a single chunk placed in the prompt
+	chatMaxChunkChars = 1500
+	// Number of earlier messages sent to the model as conversation history
+	chatHistoryMessages = 6
+	// Maximum characters of a history message used when rewriting follow-up questions
+	chatRewriteMessageChars = 500
+)
+
+// noContextReply is returned when no bookmark content matches the question
+const noContextReply = "I couldn't find anything in your bookmarks that answers this question. Try rephrasing it, or import and scrape more bookmarks on the topic."
+
+// ChatService answers questions grounded in the user's bookmarked content
+type ChatService struct {
+	storage          *storage.Storage
+	contentProcessor *ContentProcessor
+	provider         ChatProvider
+}
+
+// ChatAnswer is the reply generated for a chat message
+type ChatAnswer struct {
+	Reply     string
+	Sources   []*storage.Bookmark
+	Citations []storage.Citation
+	// RewrittenQuery is the standalone query used for retrieval
+	RewrittenQuery string
+}
+
+// contextSource is a numbered chunk placed in the chat prompt
+type contextSource struct {
+	Marker   int
+	Bookmark *storage.Bookmark
+	Chunk    *storage.ContentChunk
+	Quote    string
+}
+
+// NewChatService creates a new chat service
+func NewChatService(storage *storage.Storage, contentProcessor *ContentProcessor, provider ChatProvider) (*ChatService, error) {
+	if contentProcessor == nil {
+		return nil, fmt.Errorf("content processor is required for chat")
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("chat provider is required for chat")
+	}
+
+	return &ChatService{
+		storage:          storage,
+		contentProcessor: contentProcessor,
+		provider:         provider,
+	}, nil
+}
+
+// Answer retrieves the bookmark content relevant to the message and asks the model to answer from it.
+// Bookmark IDs in pinned are always included in the context. When conversationID is set, the latest
+// messages of that conversation are sent along as history.
+func (cs *ChatService) Answer(ctx context.Context, conversationID string, message string, pinned []string) (*ChatAnswer, error) {
+	return cs.answer(ctx, conversationID, message, pinned, nil)
+}
+
+// AnswerStream works like Answer but calls onDelta with each chunk of the reply as the model produces it
+func (cs *ChatService) AnswerStream(ctx context.Context, conversationID string, message string, pinned []string, onDelta func(string) error) (*ChatAnswer, error) {
+	return cs.answer(ctx, conversationID, message, pinned, onDelta)
+}
+
+// answer builds the grounded prompt and runs the completion, streaming it when onDelta is set
+func (cs *ChatService) answer(ctx context.Context, conversationID string, message string, pinned []string, onDelta func(string) error) (*ChatAnswer, error) {
+	history, err := cs.loadHistory(ctx, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("load history: %w", err)
+	}
+
+	// Follow-up questions only make sense with the conversation, so condense them into a standalone query
+	query := cs.rewriteQuery(ctx, history, message)
+
+	retrieved, err := cs.contentProcessor.RetrieveContext(query, pinned, chatMaxSourceBookmarks, chatMaxContextChunks)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve context: %w", err)
+	}
+
+	if len(retrieved.Chunks) == 0 {
+		if onDelta != nil {
+			if err := onDelta(noContextReply); err != nil {
+				return nil, err
+			}
+		}
+		return &ChatAnswer{
+			Reply:          noContextReply,
+			Sources:        []*storage.Bookmark{},
+			Citations:      []storage.Citation{},
+			RewrittenQuery: query,
+		}, nil
+	}
+
+	contextBlock, contextSources := cs.buildContext(retrieved)
+
+	messages := []Message{{Role: "system", Content: chatSystemPrompt}}
+	messages = append(messages, history...)
+	messages = append(messages, Message{
+		Role:    "user",
+		Content: fmt.Sprintf("Bookmarked content:\n\n%s\n%s%s", contextBlock, chatQuestionLabel, message),
+	})
+
+	var reply string
+	if onDelta != nil {
+		reply, err = cs.createChatCompletionStream(ctx, messages, onDelta)
+	} else {
+		reply, err = cs.createChatCompletion(ctx, ChatPurposeAnswer, messages)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("chat completion: %w", err)
+	}
+
+	reply = strings.TrimSpace(reply)
+	citations, sources := resolveCitations(reply, contextSources)
+
+	return &ChatAnswer{
+		Reply:          reply,
+		Sources:        sources,
+		Citations:      citations,
+		RewrittenQuery: query,
+	}, nil
+}
+
+// loadHistory returns the most recent messages of a conversation in chat completion format
+func (cs *ChatService) loadHistory(ctx context.Context, conversationID string) ([]Message, error) {
+	if conversationID == "" {
+		return nil, nil
+	}
+
+	stored, err := cs.storage.GetMessages(ctx, conversationID, chatHistoryMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]Message, len(stored))
+	for i, msg := range stored {
+		history[i] = Message{Role: msg.Role, Content: msg.Content}
+	}
+	return history, nil
+}
+
+// rewriteQuery condenses the conversation history and a new message into a standalone search query.
+// Without history, or if rewriting fails, the message is used as is.
+func (cs *ChatService) rewriteQuery(ctx context.Context, history []Message, message string) string {
+	if len(history) == 0 {
+		return message
+	}
+
+	var transcript strings.Builder
+	for _, msg := range history {
+		content := msg.Content
+		if truncated := truncateRunes(content, chatRewriteMessageChars); truncated != content {
+			content = truncated + "..."
+		}
+		fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, content)
+	}
+
+	rewritten, err := cs.createChatCompletion(ctx, ChatPurposeRewrite, []Message{
+		{Role: "system", Content: rewriteSystemPrompt},
+		{Role: "user", Content: fmt.Sprintf("Conversation:\n%s\n%s%s\n\nStandalone search query:", transcript.String(), rewriteFollowUpLabel, message)},
+	})
+	if err != nil {
+		log.Printf("Failed to rewrite chat query, using the message as is: %v", err)
+		return message
+	}
+
+	rewritten = strings.Trim(strings.TrimSpace(rewritten), `"`)
+	if rewritten == "" {
+		return message
+	}
+
+	return rewritten
+}
+
+// Labels that introduce the user's message in the answer and rewrite prompts
+const (
+	chatQuestionLabel    = "Question: "
+	rewriteFollowUpLabel = "Follow-up message: "
+)
+
+const rewriteSystemPrompt = `You rewrite follow-up messages into standalone search queries over a collection of bookmarked web pages.
+Use the conversation to resolve references such as "it", "that article" or "the second one" to the things they refer to.
+Respond only with the rewritten query, without quotes or explanations. If the message is already standalone, return it unchanged.`
+
+const chatSystemPrompt = `You are a helpful assistant that answers questions using only the user's bookmarked web pages.
+Each source below is numbered and shows the page title and URL followed by an excerpt of its content.
+
+Guidelines:
+- Answer only from the provided sources; do not use outside knowledge
+- Tag every claim with the number of the source that supports it in square brackets, e.g. [1] or [2][3]
+- Only cite numbers that appear in the sources list
+- If the sources do not contain the answer, say so plainly
+- Keep answers concise and well structured`
+
+// buildContext formats retrieved chunks as numbered sources for the prompt. Every chunk gets its own
+// marker so citations in the reply can be traced back to the exact chunk.
+func (cs *ChatService) buildContext(retrieved *RetrievedContext) (string, []contextSource) {
+	var sb strings.Builder
+	sources := []contextSource{}
+
+	for _, chunk := range retrieved.Chunks {
+		bookmark, ok := retrieved.Bookmarks[chunk.BookmarkID]
+		if !ok {
+			continue
+		}
+
+		text := strings.TrimSpace(chunk.Text)
+		if truncated := truncateRunes(text, chatMaxChunkChars); truncated != text {
+			text = truncated + "..."
+		}
+
+		source := contextSource{
+			Marker:   len(sources) + 1,
+			Bookmark: bookmark,
+			Chunk:    chunk,
+			Quote:    text,
+		}
+		sources = append(sources, source)
+
+		fmt.Fprintf(&sb, "[%d] %s (%s)\n%s\n\n", source.Marker, bookmark.Title, bookmark.URL, text)
+	}
+
+	return sb.String(), sources
+}
+
+// truncateRunes shortens text to at most max characters without splitting a multi-byte character
+func truncateRunes(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max])
+}
+
+// citationPattern matches citation markers such as [1] or [1, 2]
+var citationPattern = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// resolveCitations maps the citation markers used in a reply to the chunks they refer to and returns
+// the cited bookmarks in order of first citation. When the reply cites nothing, every bookmark placed
+// in the context is returned as a source.
+func resolveCitations(reply string, sources []contextSource) ([]storage.Citation, []*storage.Bookmark) {
+	byMarker := make(map[int]contextSource, len(sources))
+	for _, source := range sources {
+		byMarker[source.Marker] = source
+	}
+
+	citations := []storage.Citation{}
+	bookmarks := []*storage.Bookmark{}
+	citedMarkers := make(map[int]bool)
+	citedBookmarks := make(map[string]bool)
+
+	for _, match := range citationPattern.FindAllStringSubmatch(reply, -1) {
+		for _, part := range strings.Split(match[1], ",") {
+			marker, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || citedMarkers[marker] {
+				continue
+			}
+			source, ok := byMarker[marker]
+			if !ok {
+				continue
+			}
+			citedMarkers[marker] = true
+
+			citation := storage.Citation{
+				Marker:     marker,
+				BookmarkID: source.Bookmark.ID,
+				Quote:      source.Quote,
+			}
+			if !source.Chunk.Fallback {
+				chunkIndex := source.Chunk.ChunkIndex
+				citation.ChunkIndex = &chunkIndex
+			}
+			citations = append(citations, citation)
+			if !citedBookmarks[source.Bookmark.ID] {
+				citedBookmarks[source.Bookmark.ID] = true
+				bookmarks = append(bookmarks, source.Bookmark)
+			}
+		}
+	}
+
+	if len(citations) == 0 {
+		for _, source := range sources {
+			if !citedBookmarks[source.Bookmark.ID] {
+				citedBookmarks[source.Bookmark.ID] = true
+				bookmarks = append(bookmarks, source.Bookmark)
+			}
+		}
+	}
+
+	return citations, bookmarks
+}
+
+// chatCompletionRequest builds the completion request for the given messages
+func (cs *ChatService) chatCompletionRequest(purpose ChatPurpose, messages []Message) ChatCompletionRequest {
+	return ChatCompletionRequest{
+		Purpose:     purpose,
+		Messages:    messages,
+		MaxTokens:   800,
+		Temperature: 0.2, // Low temperature keeps answers close to the sources
+	}
+}
+
+// createChatCompletion creates a chat completion using the chat provider
+func (cs *ChatService) createChatCompletion(ctx context.Context, purpose ChatPurpose, messages []Message) (string, error) {
+	return cs.provider.ChatCompletion(ctx, cs.chatCompletionRequest(purpose, messages))
+}
+
+// createChatCompletionStream streams a chat completion, passing each content delta to onDelta,
+// and returns the full reply
+func (cs *ChatService) createChatCompletionStream(ctx context.Context, messages []Message, onDelta func(string) error) (string, error) {
+	return cs.provider.ChatCompletionStream(ctx, cs.chatCompletionRequest(ChatPurposeAnswer, messages), onDelta)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"bookmark-chat/internal/storage"
+)
+
+func TestResolveCitations(t *testing.T) {
+	goDocs := &storage.Bookmark{ID: "b1", Title: "Go Docs"}
+	blog := &storage.Bookmark{ID: "b2", Title: "Blog"}
+	notes := &storage.Bookmark{ID: "b3", Title: "Notes"}
+
+	sources := []contextSource{
+		{Marker: 1, Bookmark: goDocs, Chunk: &storage.ContentChunk{BookmarkID: "b1", ChunkIndex: 0}, Quote: "first chunk"},
+		{Marker: 2, Bookmark: blog, Chunk: &storage.ContentChunk{BookmarkID: "b2", ChunkIndex: 3}, Quote: "blog chunk"},
+		{Marker: 3, Bookmark: goDocs, Chunk: &storage.ContentChunk{BookmarkID: "b1", ChunkIndex: 2}, Quote: "third chunk"},
+		{Marker: 4, Bookmark: notes, Chunk: &storage.ContentChunk{BookmarkID: "b3", Fallback: true}, Quote: "scraped text"},
+	}
+
+	reply := "Goroutines are cheap [3]. Channels synchronize them [2, 3]. See also [7], [4] and [3] again."
+	citations, bookmarks := resolveCitations(reply, sources)
+
+	if len(citations) != 3 {
+		t.Fatalf("Expected 3 citations, got %d", len(citations))
+	}
+
+	if citations[0].Marker != 3 || citations[0].BookmarkID != "b1" || citations[0].ChunkIndex == nil || *citations[0].ChunkIndex != 2 || citations[0].Quote != "third chunk" {
+		t.Errorf("Unexpected first citation: %+v", citations[0])
+	}
+
+	if citations[1].Marker != 2 || citations[1].BookmarkID != "b2" || citations[1].ChunkIndex == nil || *citations[1].ChunkIndex != 3 {
+		t.Errorf("Unexpected second citation: %+v", citations[1])
+	}
+
+	// Text of a bookmark without embeddings has no chunk to point at
+	if citations[2].Marker != 4 || citations[2].BookmarkID != "b3" || citations[2].ChunkIndex != nil {
+		t.Errorf("Expected the fallback citation without a chunk index, got %+v", citations[2])
+	}
+
+	if len(bookmarks) != 3 || bookmarks[0].ID != "b1" || bookmarks[1].ID != "b2" || bookmarks[2].ID != "b3" {
+		t.Errorf("Expected cited bookmarks [b1 b2 b3] in citation order, got %d bookmarks", len(bookmarks))
+	}
+}
+
+func TestResolveCitationsWithoutMarkers(t *testing.T) {
+	goDocs := &storage.Bookmark{ID: "b1"}
+	blog := &storage.Bookmark{ID: "b2"}
+
+	sources := []contextSource{
+		{Marker: 1, Bookmark: goDocs, Chunk: &storage.ContentChunk{BookmarkID: "b1"}},
+		{Marker: 2, Bookmark: blog, Chunk: &storage.ContentChunk{BookmarkID: "b2"}},
+		{Marker: 3, Bookmark: goDocs, Chunk: &storage.ContentChunk{BookmarkID: "b1", ChunkIndex: 1}},
+	}
+
+	citations, bookmarks := resolveCitations("No markers in this answer.", sources)
+
+	if len(citations) != 0 {
+		t.Errorf("Expected no citations, got %d", len(citations))
+	}
+
+	if len(bookmarks) != 2 {
+		t.Errorf("Expected all context bookmarks as sources, got %d", len(bookmarks))
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := truncateRunes("héllo wörld", 7); got != "héllo w" {
+		t.Errorf("Expected truncation by characters, got %q", got)
+	}
+	if got := truncateRunes("日本語", 2); !utf8.ValidString(got) || got != "日本" {
+		t.Errorf("Expected valid UTF-8 cut on a character boundary, got %q", got)
+	}
+	if got := truncateRunes("short", 10); got != "short" {
+		t.Errorf("Expected short text unchanged, got %q", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,11 +17,18 @@ type ContentProcessor struct {
 	storage          *storage.Storage
 	embeddingService *EmbeddingService
 	scraperService   Scraper
+	rankingProfile   storage.RankingProfile
+	mmrLambda        float64 // MMR trade-off for chat context; 0 disables diversification
+	events           *EventBus
 }
 
 // NewContentProcessor creates a new content processor
-func NewContentProcessor(store *storage.Storage) (*ContentProcessor, error) {
-	embeddingService, err := NewEmbeddingService()
+func NewContentProcessor(store *storage.Storage, provider EmbeddingProvider) (*ContentProcessor, error) {
+	if dimensions := provider.EmbeddingDimensions(); dimensions > storage.VectorDimensions {
+		return nil, fmt.Errorf("embedding model produces %d dimensions but the vector index holds at most %d", dimensions, storage.VectorDimensions)
+	}
+
+	embeddingService, err := NewEmbeddingService(provider)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create embedding service: %w", err)
 	}
@@ -29,13 +38,35 @@ func NewContentProcessor(store *storage.Storage) (*ContentProcessor, error) {
 		return nil, fmt.Errorf("failed to create scraper service: %w", err)
 	}
 
+	// RANKING_PROFILE selects the default hybrid search ranking profile
+	rankingProfile, err := storage.LookupRankingProfile(os.Getenv("RANKING_PROFILE"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RANKING_PROFILE: %w", err)
+	}
+
+	// MMR_LAMBDA tunes how strongly chat context is diversified across sources (0 disables it)
+	mmrLambda := DefaultMMRLambda
+	if value := os.Getenv("MMR_LAMBDA"); value != "" {
+		mmrLambda, err = strconv.ParseFloat(value, 64)
+		if err != nil || mmrLambda < 0 || mmrLambda > 1 {
+			return nil, fmt.Errorf("invalid MMR_LAMBDA %q: must be a number between 0 and 1", value)
+		}
+	}
+
 	return &ContentProcessor{
 		storage:          store,
 		embeddingService: embeddingService,
 		scraperService:   scraperService,
+		rankingProfile:   rankingProfile,
+		mmrLambda:        mmrLambda,
 	}, nil
 }
 
+// SetEventBus publishes an event whenever a bookmark's embeddings are stored
+func (cp *ContentProcessor) SetEventBus(events *EventBus) {
+	cp.events = events
+}
+
 // ProcessBookmarkContent scrapes content for a bookmark and generates embeddings
 func (cp *ContentProcessor) ProcessBookmarkContent(bookmarkID string) error {
 	// Get the bookmark with retry logic for database lock issues
@@ -80,35 +111,53 @@ func (cp *ContentProcessor) ProcessBookmarkContent(bookmarkID string) error {
 		return fmt.Errorf("failed to store content: %w", err)
 	}
 
+	if _, err := cp.EmbedBookmark(bookmarkID); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully processed content for bookmark %s: %s", bookmarkID, bookmark.URL)
+	return nil
+}
+
+// EmbedBookmark chunks and embeds the stored content of a bookmark, replacing any earlier
+// embeddings, and marks the bookmark completed. It returns the number of chunks embedded.
+func (cp *ContentProcessor) EmbedBookmark(bookmarkID string) (int, error) {
 	// Get the content to get the content ID
 	content, err := cp.storage.GetContent(bookmarkID)
 	if err != nil {
-		return fmt.Errorf("failed to get stored content: %w", err)
+		return 0, fmt.Errorf("failed to get stored content: %w", err)
+	}
+
+	bookmark, err := cp.storage.GetBookmark(bookmarkID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get bookmark: %w", err)
 	}
 
-	// Generate embeddings with chunking for the clean text
-	embeddings, chunks, err := cp.embeddingService.GenerateEmbeddingWithChunking(content.CleanText)
+	// Generate embeddings with chunking for the clean text, with the title and description embedded
+	// alongside each chunk so edits to them change the vectors
+	header := strings.TrimSpace(bookmark.Title + "\n" + bookmark.Description)
+	embeddings, chunks, err := cp.embeddingService.GenerateEmbeddingWithChunking(header, content.CleanText)
 	if err != nil {
-		log.Printf("Failed to generate embeddings for %s: %v", bookmark.URL, err)
-		return fmt.Errorf("failed to generate embedding: %w", err)
+		log.Printf("Failed to generate embeddings for bookmark %s: %v", bookmarkID, err)
+		return 0, fmt.Errorf("failed to generate embedding: %w", err)
 	}
 
-	log.Printf("Generated %d chunks for %s", len(chunks), bookmark.URL)
+	log.Printf("Generated %d chunks for bookmark %s", len(chunks), bookmarkID)
 
 	// Store the embeddings for all chunks
 	err = cp.storage.StoreMultipleChunkEmbeddings(content.ID, embeddings, chunks)
 	if err != nil {
-		return fmt.Errorf("failed to store embeddings: %w", err)
+		return 0, fmt.Errorf("failed to store embeddings: %w", err)
 	}
 
 	// Update bookmark status to completed
 	err = cp.storage.UpdateBookmarkStatus(bookmarkID, "completed")
 	if err != nil {
-		return fmt.Errorf("failed to update bookmark status: %w", err)
+		return 0, fmt.Errorf("failed to update bookmark status: %w", err)
 	}
 
-	log.Printf("Successfully processed content for bookmark %s: %s", bookmarkID, bookmark.URL)
-	return nil
+	cp.events.Publish(EventEmbeddingsStored, EmbeddingsStoredEvent{BookmarkID: bookmarkID, Chunks: len(chunks)})
+	return len(chunks), nil
 }
 
 // ProcessAllPendingBookmarks processes all bookmarks with pending status
@@ -144,18 +193,226 @@ func (cp *ContentProcessor) GenerateQueryEmbedding(query string) ([]float32, err
 	return cp.embeddingService.GenerateEmbedding(query)
 }
 
-// HybridSearch performs semantic + keyword search
+// HybridSearch performs semantic + keyword search with the configured ranking profile
 func (cp *ContentProcessor) HybridSearch(query string) ([]*storage.SearchResult, error) {
+	results, _, err := cp.hybridSearch(query, cp.rankingProfile, storage.SearchOptions{})
+	return results, err
+}
+
+// SearchType selects which retrieval methods a search uses
+type SearchType string
+
+const (
+	SearchTypeSemantic SearchType = "semantic"
+	SearchTypeKeyword  SearchType = "keyword"
+	SearchTypeHybrid   SearchType = "hybrid"
+)
+
+// SearchQuery describes a bookmark search
+type SearchQuery struct {
+	Text           string
+	Type           SearchType // Defaults to hybrid
+	RankingProfile string     // Hybrid only; empty selects the configured profile
+	// MMRLambda, between 0 and 1, re-ranks a wider candidate set by maximal marginal relevance
+	// so near-duplicate results are pushed down; 0 keeps the plain relevance order
+	MMRLambda float64
+	Options   storage.SearchOptions
+}
+
+// Search runs a semantic, keyword or hybrid search with the query's filters and limit
+func (cp *ContentProcessor) Search(query SearchQuery) ([]*storage.SearchResult, error) {
+	if query.MMRLambda <= 0 {
+		return cp.search(query)
+	}
+
+	limit := query.Options.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	query.Options.Limit = min(limit*mmrCandidateFactor, maxMMRCandidates)
+
+	candidates, err := cp.search(query)
+	if err != nil {
+		return nil, err
+	}
+	return cp.diversify(candidates, query.MMRLambda, limit)
+}
+
+// search runs the query without diversification
+func (cp *ContentProcessor) search(query SearchQuery) ([]*storage.SearchResult, error) {
+	switch query.Type {
+	case SearchTypeKeyword:
+		return cp.storage.KeywordSearchWithOptions(query.Text, query.Options)
+	case SearchTypeSemantic:
+		queryEmbedding, err := cp.embeddingService.GenerateEmbedding(query.Text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+		}
+		return cp.storage.SemanticSearch(queryEmbedding, query.Text, query.Options)
+	case SearchTypeHybrid, "":
+		profile := cp.rankingProfile
+		if query.RankingProfile != "" {
+			var err error
+			if profile, err = storage.LookupRankingProfile(query.RankingProfile); err != nil {
+				return nil, err
+			}
+		}
+		results, _, err := cp.hybridSearch(query.Text, profile, query.Options)
+		return results, err
+	default:
+		return nil, fmt.Errorf("unsupported search type: %s", query.Type)
+	}
+}
+
+// hybridSearch performs semantic + keyword search and also returns the query embedding
+// (nil when embedding generation failed and only keyword search was used)
+func (cp *ContentProcessor) hybridSearch(query string, profile storage.RankingProfile, opts storage.SearchOptions) ([]*storage.SearchResult, []float32, error) {
 	// Generate embedding for the query
 	queryEmbedding, err := cp.embeddingService.GenerateEmbedding(query)
 	if err != nil {
 		// If embedding generation fails, fall back to keyword search only
 		log.Printf("Failed to generate query embedding, using keyword search only: %v", err)
-		return cp.storage.KeywordSearch(query, 20)
+		if opts.Limit <= 0 {
+			opts.Limit = profile.Limit
+		}
+		results, err := cp.storage.KeywordSearchWithOptions(query, opts)
+		return results, nil, err
 	}
 
 	// Perform hybrid search
-	return cp.storage.HybridSearch(queryEmbedding, query)
+	results, err := cp.storage.HybridSearchWithProfile(queryEmbedding, query, profile, opts)
+	return results, queryEmbedding, err
+}
+
+// RetrievedContext holds the content chunks selected to ground a chat answer
+type RetrievedContext struct {
+	Chunks    []*storage.ContentChunk
+	Bookmarks map[string]*storage.Bookmark
+}
+
+// RetrieveContext runs the query through hybrid search and returns the most relevant content
+// chunks of the top matching bookmarks. Pinned bookmarks are always included: each gets its best
+// chunk, or its text when it has no embeddings yet, before any search result is considered.
+func (cp *ContentProcessor) RetrieveContext(query string, pinnedBookmarkIDs []string, maxBookmarks int, maxChunks int) (*RetrievedContext, error) {
+	results, queryEmbedding, err := cp.hybridSearch(query, cp.rankingProfile, storage.SearchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("hybrid search failed: %w", err)
+	}
+
+	// Spread the context over distinct sources rather than near-duplicate pages
+	if cp.mmrLambda > 0 {
+		if diversified, err := cp.diversify(results, cp.mmrLambda, 0); err != nil {
+			log.Printf("Failed to diversify context, using relevance order: %v", err)
+		} else {
+			results = diversified
+		}
+	}
+
+	retrieved := &RetrievedContext{
+		Chunks:    []*storage.ContentChunk{},
+		Bookmarks: make(map[string]*storage.Bookmark),
+	}
+
+	// Keep the best chunks while limiting how many come from a single bookmark
+	const maxChunksPerBookmark = 3
+	perBookmark := make(map[string]int)
+	addChunk := func(chunk *storage.ContentChunk) {
+		if len(retrieved.Chunks) < maxChunks && perBookmark[chunk.BookmarkID] < maxChunksPerBookmark {
+			perBookmark[chunk.BookmarkID]++
+			retrieved.Chunks = append(retrieved.Chunks, chunk)
+		}
+	}
+
+	var pinnedIDs []string
+	for _, id := range pinnedBookmarkIDs {
+		if _, exists := retrieved.Bookmarks[id]; exists {
+			continue
+		}
+		bookmark, err := cp.storage.GetBookmark(id)
+		if err != nil {
+			log.Printf("Skipping pinned context bookmark %s: %v", id, err)
+			continue
+		}
+		retrieved.Bookmarks[id] = bookmark
+		pinnedIDs = append(pinnedIDs, id)
+	}
+
+	if len(pinnedIDs) > 0 {
+		// Pinned chunks are ranked on their own, so better search hits cannot crowd them out
+		pinnedChunks, err := cp.storage.GetRelevantChunks(queryEmbedding, pinnedIDs, maxChunks*2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pinned chunks: %w", err)
+		}
+
+		var rest []*storage.ContentChunk
+		for _, chunk := range pinnedChunks {
+			if perBookmark[chunk.BookmarkID] == 0 {
+				addChunk(chunk)
+			} else {
+				rest = append(rest, chunk)
+			}
+		}
+		for _, id := range pinnedIDs {
+			if perBookmark[id] > 0 {
+				continue
+			}
+			content, err := cp.storage.GetContent(id)
+			if err != nil || content.CleanText == "" {
+				continue
+			}
+			addChunk(fallbackChunk(id, content.CleanText))
+		}
+		for _, chunk := range rest {
+			addChunk(chunk)
+		}
+	}
+
+	// Then search results by relevance
+	var bookmarkIDs []string
+	fallbackText := make(map[string]string)
+	for _, result := range results {
+		if len(bookmarkIDs) >= maxBookmarks {
+			break
+		}
+		if _, exists := retrieved.Bookmarks[result.Bookmark.ID]; exists {
+			continue
+		}
+		retrieved.Bookmarks[result.Bookmark.ID] = result.Bookmark
+		bookmarkIDs = append(bookmarkIDs, result.Bookmark.ID)
+		if result.Content != nil && result.Content.CleanText != "" {
+			fallbackText[result.Bookmark.ID] = result.Content.CleanText
+		}
+	}
+
+	if len(bookmarkIDs) == 0 || len(retrieved.Chunks) >= maxChunks {
+		return retrieved, nil
+	}
+
+	chunks, err := cp.storage.GetRelevantChunks(queryEmbedding, bookmarkIDs, maxChunks*2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relevant chunks: %w", err)
+	}
+	for _, chunk := range chunks {
+		addChunk(chunk)
+	}
+
+	// Bookmarks with scraped content but no embeddings yet still contribute their text
+	for _, id := range bookmarkIDs {
+		if text, ok := fallbackText[id]; ok && perBookmark[id] == 0 {
+			addChunk(fallbackChunk(id, text))
+		}
+	}
+
+	return retrieved, nil
+}
+
+// fallbackChunk stands in for the chunks of a bookmark without embeddings with the start of its text
+func fallbackChunk(bookmarkID, text string) *storage.ContentChunk {
+	return &storage.ContentChunk{
+		BookmarkID: bookmarkID,
+		Text:       truncateRunes(text, 2000),
+		Fallback:   true,
+	}
 }
 
 // KeywordSearch performs only keyword-based search (fallback)
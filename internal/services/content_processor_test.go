@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"bookmark-chat/internal/storage"
+)
+
+// addScrapedBookmark creates a bookmark with stored content, embedding it when embed is set
+func addScrapedBookmark(t *testing.T, store *storage.Storage, processor *ContentProcessor, url, title, text string, embed bool) string {
+	t.Helper()
+	bookmark := &storage.Bookmark{URL: url, Title: title}
+	if err := store.CreateBookmark(bookmark); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	if err := store.StoreContent(bookmark.ID, text, text); err != nil {
+		t.Fatalf("Failed to store content: %v", err)
+	}
+	if embed {
+		if _, err := processor.EmbedBookmark(bookmark.ID); err != nil {
+			t.Fatalf("Failed to embed bookmark: %v", err)
+		}
+	}
+	return bookmark.ID
+}
+
+func TestRetrieveContextIncludesPinnedBookmarks(t *testing.T) {
+	store := newTestStorage(t)
+	processor, err := NewContentProcessor(store, NewLocalProvider(0))
+	if err != nil {
+		t.Fatalf("Failed to create content processor: %v", err)
+	}
+
+	// More pages matching the query closely than there is room for in the context
+	for i := 0; i < 6; i++ {
+		addScrapedBookmark(t, store, processor, fmt.Sprintf("https://k8s.example.com/%d", i),
+			"Kubernetes deployments", "Kubernetes deployments roll out pods and replica sets across the cluster.", true)
+	}
+	// Pinned pages that have nothing to do with the query, one not embedded yet
+	garden := addScrapedBookmark(t, store, processor, "https://garden.example.com/",
+		"Tomato growing", "Water tomato plants deeply and stake them early in the season.", true)
+	recipe := addScrapedBookmark(t, store, processor, "https://food.example.com/",
+		"Bread recipe", "Knead the dough for ten minutes before proving.", false)
+
+	retrieved, err := processor.RetrieveContext("kubernetes deployments", []string{garden, recipe}, 5, 4)
+	if err != nil {
+		t.Fatalf("Failed to retrieve context: %v", err)
+	}
+
+	if len(retrieved.Chunks) != 4 {
+		t.Fatalf("Expected the context to be filled to 4 chunks, got %d", len(retrieved.Chunks))
+	}
+	chunks := make(map[string]*storage.ContentChunk)
+	for _, chunk := range retrieved.Chunks {
+		if _, seen := chunks[chunk.BookmarkID]; !seen {
+			chunks[chunk.BookmarkID] = chunk
+		}
+	}
+	if chunk := chunks[garden]; chunk == nil || chunk.Fallback {
+		t.Errorf("Expected a chunk of the pinned low-similarity bookmark, got %+v", chunk)
+	}
+	if chunk := chunks[recipe]; chunk == nil || !chunk.Fallback || !strings.Contains(chunk.Text, "Knead") {
+		t.Errorf("Expected the text of the pinned bookmark without embeddings, got %+v", chunk)
+	}
+	if len(chunks) < 3 {
+		t.Errorf("Expected search results to fill the remaining slots, got chunks from %d bookmarks", len(chunks))
+	}
+}
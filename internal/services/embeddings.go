@@ -3,31 +3,23 @@ package services
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"unicode/utf8"
-
-	"github.com/sashabaranov/go-openai"
 )
 
-// EmbeddingService handles generating embeddings via OpenAI API
+// EmbeddingService handles generating embeddings via the configured embedding provider
 type EmbeddingService struct {
-	client *openai.Client
-	model  string
+	provider EmbeddingProvider
 }
 
 // NewEmbeddingService creates a new embedding service
-func NewEmbeddingService() (*EmbeddingService, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is required")
+func NewEmbeddingService(provider EmbeddingProvider) (*EmbeddingService, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("embedding provider is required")
 	}
 
-	client := openai.NewClient(apiKey)
-
 	return &EmbeddingService{
-		client: client,
-		model:  "text-embedding-3-small", // 1536 dimensions, optimized for retrieval
+		provider: provider,
 	}, nil
 }
 
@@ -37,20 +29,17 @@ func (es *EmbeddingService) GenerateEmbedding(text string) ([]float32, error) {
 		return nil, fmt.Errorf("text cannot be empty")
 	}
 
-	resp, err := es.client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
-		Model: openai.EmbeddingModel(es.model),
-		Input: []string{text},
-	})
+	embeddings, err := es.provider.CreateEmbeddings(context.Background(), []string{text})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create embedding: %w", err)
 	}
 
-	if len(resp.Data) == 0 {
+	if len(embeddings) == 0 {
 		return nil, fmt.Errorf("no embedding data returned")
 	}
 
-	return resp.Data[0].Embedding, nil
+	return embeddings[0], nil
 }
 
 // GenerateBatchEmbeddings creates embeddings for multiple texts in a single API call
@@ -59,28 +48,20 @@ func (es *EmbeddingService) GenerateBatchEmbeddings(texts []string) ([][]float32
 		return nil, fmt.Errorf("texts cannot be empty")
 	}
 
-	// OpenAI has limits on batch size, so split if needed
+	// Providers limit the batch size, so split if needed
 	const maxBatchSize = 2048
 	if len(texts) > maxBatchSize {
 		return nil, fmt.Errorf("batch size %d exceeds maximum %d", len(texts), maxBatchSize)
 	}
 
-	resp, err := es.client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
-		Model: openai.EmbeddingModel(es.model),
-		Input: texts,
-	})
+	embeddings, err := es.provider.CreateEmbeddings(context.Background(), texts)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create batch embeddings: %w", err)
 	}
 
-	if len(resp.Data) != len(texts) {
-		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Data))
-	}
-
-	embeddings := make([][]float32, len(resp.Data))
-	for i, data := range resp.Data {
-		embeddings[i] = data.Embedding
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
 	}
 
 	return embeddings, nil
@@ -88,12 +69,11 @@ func (es *EmbeddingService) GenerateBatchEmbeddings(texts []string) ([][]float32
 
 // GetModelInfo returns information about the embedding model being used
 func (es *EmbeddingService) GetModelInfo() (string, int) {
-	// text-embedding-3-small has 1536 dimensions
-	return es.model, 1536
+	return es.provider.EmbeddingModel(), es.provider.EmbeddingDimensions()
 }
 
 // estimateTokenCount provides a rough estimate of token count for text
-// OpenAI's rule of thumb: ~4 characters per token for English text
+// Rule of thumb for OpenAI-style tokenizers: ~4 characters per token for English text
 func (es *EmbeddingService) estimateTokenCount(text string) int {
 	return utf8.RuneCountInString(text) / 4
 }
@@ -214,17 +194,28 @@ func (es *EmbeddingService) splitByCharacterCount(text string, maxTokens int) []
 	return chunks
 }
 
-// GenerateEmbeddingWithChunking generates embeddings for text, chunking if necessary
-func (es *EmbeddingService) GenerateEmbeddingWithChunking(text string) ([][]float32, []string, error) {
-	// Split text into chunks
-	chunks := es.ChunkText(text, 6000) // Conservative limit under 8192
+// GenerateEmbeddingWithChunking generates embeddings for text, chunking if necessary. The header,
+// e.g. a bookmark's title and description, is embedded with every chunk but not returned with them.
+func (es *EmbeddingService) GenerateEmbeddingWithChunking(header, text string) ([][]float32, []string, error) {
+	header = truncateRunes(strings.TrimSpace(header), 2000)
+
+	// Split text into chunks, leaving room for the header
+	chunks := es.ChunkText(text, 6000-es.estimateTokenCount(header)) // Conservative limit under 8192
 
 	if len(chunks) == 0 {
 		return nil, nil, fmt.Errorf("no chunks generated from text")
 	}
 
+	inputs := chunks
+	if header != "" {
+		inputs = make([]string, len(chunks))
+		for i, chunk := range chunks {
+			inputs[i] = header + "\n\n" + chunk
+		}
+	}
+
 	// Generate embeddings for all chunks
-	embeddings, err := es.GenerateBatchEmbeddings(chunks)
+	embeddings, err := es.GenerateBatchEmbeddings(inputs)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate chunk embeddings: %w", err)
 	}
@@ -0,0 +1,28 @@
+package services
+
+import "testing"
+
+func TestGenerateEmbeddingWithChunkingHeader(t *testing.T) {
+	service, err := NewEmbeddingService(NewLocalProvider(0))
+	if err != nil {
+		t.Fatalf("Failed to create embedding service: %v", err)
+	}
+
+	text := "Goroutines are lightweight threads managed by the Go runtime."
+	plain, chunks, err := service.GenerateEmbeddingWithChunking("", text)
+	if err != nil {
+		t.Fatalf("Failed to embed text: %v", err)
+	}
+	titled, titledChunks, err := service.GenerateEmbeddingWithChunking("Concurrency in Go\nA tour of channels", text)
+	if err != nil {
+		t.Fatalf("Failed to embed text with a header: %v", err)
+	}
+
+	// The header shapes the vectors but is not part of the stored chunk text
+	if len(chunks) != 1 || len(titledChunks) != 1 || titledChunks[0] != chunks[0] {
+		t.Errorf("Expected the same chunk text with and without a header, got %q and %q", chunks, titledChunks)
+	}
+	if cosineSimilarity(plain[0], titled[0]) > 0.9999 {
+		t.Error("Expected the header to change the embedding")
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"bookmark-chat/internal/storage"
+)
+
+// Event types published on the event bus
+const (
+	EventBookmarkImported        = "bookmark.imported"
+	EventScrapeStarted           = "scrape.started"
+	EventScrapeFinished          = "scrape.finished"
+	EventScrapeFailed            = "scrape.failed"
+	EventEmbeddingsStored        = "embeddings.stored"
+	EventCategorizationSuggested = "categorization.suggested"
+	EventJobProgress             = "job.progress"
+	EventScrapingProgress        = "scraping.progress" // Progress of the bulk scrape run, as BulkScrapingStatus
+	EventResync                  = "events.resync"     // The subscriber missed events from this ID on and should reload its state
+)
+
+// eventBufferSize is how many events a subscriber can fall behind before it misses events
+const eventBufferSize = 64
+
+// Event is a typed notification delivered to event bus subscribers
+type Event struct {
+	ID   uint64      `json:"id"`
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// BookmarkImportedEvent reports a finished bookmark file import
+type BookmarkImportedEvent struct {
+	Source     string `json:"source"`
+	TotalFound int    `json:"total_found"`
+	Imported   int    `json:"imported"`
+	Failed     int    `json:"failed"`
+	Duplicates int    `json:"duplicates"`
+}
+
+// ScrapeEvent reports a bookmark scrape starting, finishing or failing
+type ScrapeEvent struct {
+	BookmarkID string `json:"bookmark_id"`
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Attempt    int    `json:"attempt"`
+	Final      bool   `json:"final,omitempty"` // A failure on the last attempt; the scrape is not retried
+}
+
+// EmbeddingsStoredEvent reports a bookmark's content being embedded
+type EmbeddingsStoredEvent struct {
+	BookmarkID string `json:"bookmark_id"`
+	Chunks     int    `json:"chunks"`
+}
+
+// CategorizationSuggestedEvent reports categories suggested for a bookmark, awaiting approval
+type CategorizationSuggestedEvent struct {
+	BookmarkID string `json:"bookmark_id"`
+	storage.CategorizationResult
+}
+
+// JobProgressEvent reports a background job changing state
+type JobProgressEvent struct {
+	JobID       string     `json:"job_id"`
+	Type        string     `json:"type"`
+	BookmarkID  string     `json:"bookmark_id,omitempty"`
+	BatchID     string     `json:"batch_id,omitempty"`
+	State       string     `json:"state"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	Error       string     `json:"error,omitempty"`
+	RetryAt     *time.Time `json:"retry_at,omitempty"`
+}
+
+// EventBus fans events out to subscribers in-process. Publishing never blocks: a subscriber that
+// falls too far behind misses events, and receives an EventResync once it has read the events
+// before the gap. A nil bus discards everything published to it, so services work without one.
+type EventBus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]*subscriber
+	nextID      uint64
+	closed      bool
+}
+
+// subscriber tracks whether a subscription is dropping events until it reads its resync event
+type subscriber struct {
+	missing bool
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[chan Event]*subscriber)}
+}
+
+// Publish sends an event to every subscriber
+func (b *EventBus) Publish(eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event := Event{ID: b.nextID, Type: eventType, Time: time.Now(), Data: data}
+	for ch, sub := range b.subscribers {
+		b.deliver(ch, sub, event)
+	}
+}
+
+// deliver sends an event to a subscriber without blocking. Channels hold one slot more than
+// eventBufferSize, so when the buffer fills there is always room for the resync event. Events are
+// then dropped until the subscriber has read it. The caller must hold the lock.
+func (b *EventBus) deliver(ch chan Event, sub *subscriber, event Event) {
+	if sub.missing {
+		if len(ch) > 0 {
+			return
+		}
+		sub.missing = false
+	}
+
+	if len(ch) < eventBufferSize {
+		ch <- event
+		return
+	}
+
+	sub.missing = true
+	ch <- Event{ID: event.ID, Type: EventResync, Time: event.Time}
+}
+
+// Subscribe returns a channel receiving the events published from now on, and a function that
+// unsubscribes. The channel is closed on unsubscribe or when the bus closes.
+func (b *EventBus) Subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, eventBufferSize+1)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = &subscriber{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close closes every subscriber's channel, ending open event streams so the server can shut down
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
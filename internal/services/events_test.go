@@ -0,0 +1,66 @@
+package services
+
+import "testing"
+
+func TestEventBus(t *testing.T) {
+	var nilBus *EventBus
+	nilBus.Publish(EventScrapeStarted, nil) // Must not panic
+
+	bus := NewEventBus()
+	events, unsubscribe := bus.Subscribe()
+
+	bus.Publish(EventScrapeStarted, ScrapeEvent{BookmarkID: "a"})
+	bus.Publish(EventScrapeFinished, ScrapeEvent{BookmarkID: "a"})
+
+	first, second := <-events, <-events
+	if first.Type != EventScrapeStarted || second.Type != EventScrapeFinished {
+		t.Errorf("Expected events in publish order, got %s then %s", first.Type, second.Type)
+	}
+	if second.ID <= first.ID {
+		t.Errorf("Expected increasing event IDs, got %d then %d", first.ID, second.ID)
+	}
+
+	// A subscriber that falls behind misses events instead of blocking the publisher, and is told so
+	for i := 0; i < eventBufferSize+10; i++ {
+		bus.Publish(EventJobProgress, nil)
+	}
+	if len(events) != eventBufferSize+1 {
+		t.Fatalf("Expected %d buffered events and a resync, got %d", eventBufferSize, len(events))
+	}
+	var last Event
+	for i := 0; i <= eventBufferSize; i++ {
+		last = <-events
+		if i < eventBufferSize && last.Type != EventJobProgress {
+			t.Fatalf("Expected buffered event %d to be delivered, got %s", i, last.Type)
+		}
+		if i == eventBufferSize/2 {
+			// Still catching up to the resync, so this is missed too
+			bus.Publish(EventJobProgress, nil)
+		}
+	}
+	if last.Type != EventResync || last.ID != second.ID+eventBufferSize+1 {
+		t.Errorf("Expected a resync from the first missed event, got %s %d", last.Type, last.ID)
+	}
+
+	// Once the resync is read, delivery resumes
+	bus.Publish(EventScrapeStarted, nil)
+	if event := <-events; event.Type != EventScrapeStarted {
+		t.Errorf("Expected delivery to resume after the resync, got %s", event.Type)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	for range events {
+	}
+
+	// Closing the bus ends every open subscription
+	other, _ := bus.Subscribe()
+	bus.Close()
+	if _, ok := <-other; ok {
+		t.Error("Expected the subscription to be closed with the bus")
+	}
+	late, _ := bus.Subscribe()
+	if _, ok := <-late; ok {
+		t.Error("Expected subscribing to a closed bus to return a closed channel")
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// HostLimiter paces requests per host: each host gets its own rate limiter and a cap on requests in
+// flight, so many bookmarks on one site are fetched politely while other sites proceed in parallel
+type HostLimiter struct {
+	requestsPerSecond float64
+	maxConcurrent     int // 0 means no cap
+	mu                sync.Mutex
+	hosts             map[string]*hostLimit
+}
+
+// hostLimit is the pacing state of one host
+type hostLimit struct {
+	limiter *rate.Limiter
+	slots   chan struct{} // Holds a token per request in flight; nil without a cap
+}
+
+// NewHostLimiter creates a limiter allowing requestsPerSecond and maxConcurrent requests in flight
+// per host. Zero or negative values disable the respective limit.
+func NewHostLimiter(requestsPerSecond float64, maxConcurrent int) *HostLimiter {
+	if maxConcurrent < 0 {
+		maxConcurrent = 0
+	}
+	return &HostLimiter{
+		requestsPerSecond: requestsPerSecond,
+		maxConcurrent:     maxConcurrent,
+		hosts:             make(map[string]*hostLimit),
+	}
+}
+
+// Acquire waits until the URL's host has a free slot and its rate limit allows another request.
+// The returned function frees the slot and must be called once the request is done.
+func (l *HostLimiter) Acquire(ctx context.Context, rawURL string) (func(), error) {
+	host := l.host(hostKey(rawURL))
+
+	if host.slots != nil {
+		select {
+		case host.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if host.slots != nil {
+			<-host.slots
+		}
+	}
+
+	if err := host.limiter.Wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// host returns the pacing state of a host, creating it on first use
+func (l *HostLimiter) host(key string) *hostLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	host, ok := l.hosts[key]
+	if !ok {
+		limit := rate.Inf
+		if l.requestsPerSecond > 0 {
+			limit = rate.Limit(l.requestsPerSecond)
+		}
+		host = &hostLimit{limiter: rate.NewLimiter(limit, 1)}
+		if l.maxConcurrent > 0 {
+			host.slots = make(chan struct{}, l.maxConcurrent)
+		}
+		l.hosts[key] = host
+	}
+	return host
+}
+
+// hostKey identifies the host a URL's requests are paced under: its lowercase hostname without
+// "www.", matching bookmarks.domain
+func hostKey(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHostLimiter(t *testing.T) {
+	limiter := NewHostLimiter(0, 1)
+	ctx := context.Background()
+
+	release, err := limiter.Acquire(ctx, "https://www.Example.com/a")
+	if err != nil {
+		t.Fatalf("Failed to acquire first slot: %v", err)
+	}
+
+	// Another site is not held up by the busy one
+	other, err := limiter.Acquire(ctx, "https://other.org/")
+	if err != nil {
+		t.Fatalf("Expected a different host to get a slot, got %v", err)
+	}
+	other()
+
+	// The same host, written differently, waits for the slot to free up
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(timeout, "http://example.com/b"); err == nil {
+		t.Fatal("Expected a second request to the same host to wait for the first")
+	}
+
+	release()
+	second, err := limiter.Acquire(ctx, "http://example.com/b")
+	if err != nil {
+		t.Fatalf("Expected the freed slot to be acquired, got %v", err)
+	}
+	second()
+}
+
+func TestHostLimiterRate(t *testing.T) {
+	limiter := NewHostLimiter(20, 0)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := limiter.Acquire(ctx, "https://example.com/")
+		if err != nil {
+			t.Fatalf("Failed to acquire: %v", err)
+		}
+		release()
+	}
+	// The first request goes right away, the next two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected requests to one host to be spaced out, took %v", elapsed)
+	}
+}
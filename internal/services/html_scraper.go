@@ -11,13 +11,12 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"golang.org/x/time/rate"
 )
 
 type HTMLScraper struct {
-	client      *http.Client
-	rateLimiter *rate.Limiter
-	mu          sync.RWMutex
+	client *http.Client
+	hosts  *HostLimiter
+	mu     sync.RWMutex
 }
 
 func NewHTMLScraper() *HTMLScraper {
@@ -25,20 +24,28 @@ func NewHTMLScraper() *HTMLScraper {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		rateLimiter: rate.NewLimiter(rate.Limit(2.0), 1),
+		hosts: NewHostLimiter(defaultHostRateLimit, defaultHostConcurrency),
 	}
 }
 
+// SetRateLimit sets how many requests per second each host receives
 func (s *HTMLScraper) SetRateLimit(requestsPerSecond float64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.rateLimiter = rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+	s.hosts = NewHostLimiter(requestsPerSecond, s.hosts.maxConcurrent)
+}
+
+// SetHostConcurrency caps how many requests to one host are in flight at once
+func (s *HTMLScraper) SetHostConcurrency(maxConcurrent int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hosts = NewHostLimiter(s.hosts.requestsPerSecond, maxConcurrent)
 }
 
 func (s *HTMLScraper) Scrape(ctx context.Context, url string, options ScrapeOptions) (*ScrapedContent, error) {
-	if err := s.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
-	}
+	s.mu.RLock()
+	hosts := s.hosts
+	s.mu.RUnlock()
 
 	var lastErr error
 	for attempt := 0; attempt <= options.MaxRetries; attempt++ {
@@ -50,7 +57,12 @@ func (s *HTMLScraper) Scrape(ctx context.Context, url string, options ScrapeOpti
 			}
 		}
 
+		release, err := hosts.Acquire(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("rate limiter error: %w", err)
+		}
 		content, err := s.scrapeOnce(ctx, url, options)
+		release()
 		if err == nil {
 			return content, nil
 		}
@@ -13,6 +13,7 @@ import (
 type ImportService struct {
 	parserService *BookmarkParserService
 	storage       *storage.Storage
+	events        *EventBus
 }
 
 // NewImportService creates a new import service
@@ -23,6 +24,11 @@ func NewImportService(storage *storage.Storage) *ImportService {
 	}
 }
 
+// SetEventBus publishes an event for every completed import
+func (s *ImportService) SetEventBus(events *EventBus) {
+	s.events = events
+}
+
 // ImportBookmarksFromFile handles the complete import process from an uploaded file
 func (s *ImportService) ImportBookmarksFromFile(fileHeader *multipart.FileHeader) (*parsers.ImportResult, *parsers.ParseResult, error) {
 	// Open the uploaded file
@@ -71,9 +77,13 @@ func (s *ImportService) ImportBookmarksFromFile(fileHeader *multipart.FileHeader
 		importResult.Status = "partial"
 	}
 
-	// TODO: In the future, this is where we would:
-	// 1. Queue URLs for scraping
-	// 2. Generate embeddings
+	s.events.Publish(EventBookmarkImported, BookmarkImportedEvent{
+		Source:     parseResult.Source,
+		TotalFound: storageResult.TotalFound,
+		Imported:   storageResult.SuccessfullyImported,
+		Failed:     storageResult.Failed,
+		Duplicates: storageResult.Duplicates,
+	})
 
 	return importResult, parseResult, nil
 }
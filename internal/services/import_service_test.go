@@ -9,7 +9,7 @@ import (
 )
 
 func TestImportService_ImportBookmarksFromReader_Firefox(t *testing.T) {
-	service := NewImportService()
+	service := NewImportService(nil)
 	
 	file, err := os.Open("../../test_firefox_bookmarks.html")
 	if err != nil {
@@ -36,7 +36,7 @@ func TestImportService_ImportBookmarksFromReader_Firefox(t *testing.T) {
 }
 
 func TestImportService_ImportBookmarksFromReader_Chrome(t *testing.T) {
-	service := NewImportService()
+	service := NewImportService(nil)
 	
 	file, err := os.Open("../../test_chrome_bookmarks.html")
 	if err != nil {
@@ -63,7 +63,7 @@ func TestImportService_ImportBookmarksFromReader_Chrome(t *testing.T) {
 }
 
 func TestImportService_InvalidFile(t *testing.T) {
-	service := NewImportService()
+	service := NewImportService(nil)
 	
 	// Test with non-existent file
 	_, _, err := service.ImportBookmarksFromReader(strings.NewReader("invalid content"))
@@ -73,7 +73,7 @@ func TestImportService_InvalidFile(t *testing.T) {
 }
 
 func TestImportService_GetSupportedFormats(t *testing.T) {
-	service := NewImportService()
+	service := NewImportService(nil)
 	formats := service.GetSupportedFormats()
 	
 	if len(formats) == 0 {
@@ -380,7 +380,7 @@ func validateChromeBookmarkDetails(t *testing.T, bookmarks []parsers.Bookmark) {
 
 // Benchmark tests to ensure performance
 func BenchmarkImportService_Firefox(b *testing.B) {
-	service := NewImportService()
+	service := NewImportService(nil)
 	
 	for i := 0; i < b.N; i++ {
 		file, err := os.Open("../../test_firefox_bookmarks.html")
@@ -398,7 +398,7 @@ func BenchmarkImportService_Firefox(b *testing.B) {
 }
 
 func BenchmarkImportService_Chrome(b *testing.B) {
-	service := NewImportService()
+	service := NewImportService(nil)
 	
 	for i := 0; i < b.N; i++ {
 		file, err := os.Open("../../test_chrome_bookmarks.html")
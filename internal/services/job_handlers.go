@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"bookmark-chat/internal/storage"
+)
+
+// BackgroundJobsConfig selects which follow-up work is queued automatically
+type BackgroundJobsConfig struct {
+	AutoCategorize      bool          // Queue categorization once a bookmark is embedded
+	CategorizeThreshold float64       // Minimum confidence for an automatic categorization to be applied
+	LinkCheckInterval   time.Duration // How often every bookmark's URL is checked; 0 disables link checks
+}
+
+// DefaultBackgroundJobsConfig reads AUTO_CATEGORIZE and LINK_CHECK_INTERVAL from the environment
+func DefaultBackgroundJobsConfig() BackgroundJobsConfig {
+	config := BackgroundJobsConfig{CategorizeThreshold: 0.8}
+	if autoCategorize, err := strconv.ParseBool(os.Getenv("AUTO_CATEGORIZE")); err == nil {
+		config.AutoCategorize = autoCategorize
+	}
+	if interval, err := time.ParseDuration(os.Getenv("LINK_CHECK_INTERVAL")); err == nil && interval > 0 {
+		config.LinkCheckInterval = interval
+	}
+	return config
+}
+
+// JobServices are the services background jobs run with. Job types whose service is nil get no
+// handler, so their jobs wait in the queue.
+type JobServices struct {
+	Storage          *storage.Storage
+	Scraper          Scraper
+	ContentProcessor *ContentProcessor
+	Categorization   *CategorizationService
+	Events           *EventBus // Receives scrape events; may be nil
+}
+
+// jobRunner implements the job handlers
+type jobRunner struct {
+	JobServices
+	queue      *JobQueue
+	config     BackgroundJobsConfig
+	httpClient *http.Client
+}
+
+// RegisterJobHandlers registers handlers for every job type the given services can run
+func RegisterJobHandlers(queue *JobQueue, services JobServices, config BackgroundJobsConfig) {
+	runner := &jobRunner{
+		JobServices: services,
+		queue:       queue,
+		config:      config,
+		httpClient:  &http.Client{Timeout: 15 * time.Second},
+	}
+
+	if runner.Scraper == nil {
+		scraper, err := NewScraper(DefaultScraperConfig())
+		if err != nil {
+			log.Printf("⚠️  Failed to create scraper, scrape jobs disabled: %v", err)
+		}
+		runner.Scraper = scraper
+	}
+
+	if runner.Scraper != nil {
+		queue.Handle(storage.JobTypeScrape, runner.scrape)
+	}
+	if runner.ContentProcessor != nil {
+		queue.Handle(storage.JobTypeEmbed, runner.embed)
+	}
+	if runner.Categorization != nil {
+		queue.Handle(storage.JobTypeCategorize, runner.categorize)
+	}
+	queue.Handle(storage.JobTypeLinkCheck, runner.checkLink)
+}
+
+// EnqueuePendingBookmarks queues pending bookmarks that are not already being processed for
+// scraping; scraped bookmarks are embedded next when an embedding provider is configured. Without
+// one, bookmarks stay pending once scraped, so only those never scraped are queued.
+func EnqueuePendingBookmarks(queue *JobQueue, store *storage.Storage) (int, error) {
+	pendingIDs := store.BookmarkIDsWithoutActiveJobs
+	if !queue.Handles(storage.JobTypeEmbed) {
+		pendingIDs = store.UnscrapedBookmarkIDsWithoutActiveJobs
+	}
+
+	ids, err := pendingIDs("pending", storage.JobTypeScrape, storage.JobTypeEmbed)
+	if err != nil {
+		return 0, err
+	}
+	if err := queue.EnqueueBookmarks(storage.JobTypeScrape, ids, JobPriorityBackground, ""); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// ScheduleLinkChecks queues a link check for every bookmark not checked within interval, then
+// repeats on the same interval
+func ScheduleLinkChecks(queue *JobQueue, store *storage.Storage, interval time.Duration) {
+	go func() {
+		for {
+			ids, err := store.BookmarkIDsDueForJob(storage.JobTypeLinkCheck, time.Now().Add(-interval))
+			if err != nil {
+				log.Printf("❌ Failed to find bookmarks due for a link check: %v", err)
+			} else if len(ids) > 0 {
+				if err := queue.EnqueueBookmarks(storage.JobTypeLinkCheck, ids, JobPriorityBackground-1, ""); err != nil {
+					log.Printf("❌ Failed to queue link checks: %v", err)
+				} else {
+					log.Printf("🔗 Queued link checks for %d bookmarks", len(ids))
+				}
+			}
+			// Check hourly at most, so bookmarks added meanwhile are not left waiting a whole interval
+			wait := interval
+			if wait > time.Hour {
+				wait = time.Hour
+			}
+			time.Sleep(wait)
+		}
+	}()
+}
+
+// bookmark loads the job's bookmark; a deleted bookmark fails the job permanently
+func (r *jobRunner) bookmark(job *storage.Job) (*storage.Bookmark, error) {
+	bookmark, err := r.Storage.GetBookmark(job.BookmarkID)
+	if errors.Is(err, storage.ErrBookmarkNotFound) {
+		return nil, fmt.Errorf("%w: %v", ErrJobNotRetryable, err)
+	}
+	return bookmark, err
+}
+
+// jobResult encodes a job handler's result
+func jobResult(result interface{}) (string, error) {
+	data, err := json.Marshal(result)
+	return string(data), err
+}
+
+// scrape fetches the bookmark's page, fills in missing metadata, stores the content and queues
+// embedding. A bookmark whose last attempt fails is marked failed.
+func (r *jobRunner) scrape(ctx context.Context, job *storage.Job) (string, error) {
+	bookmark, err := r.bookmark(job)
+	if err != nil {
+		return "", err
+	}
+
+	event := ScrapeEvent{BookmarkID: bookmark.ID, URL: bookmark.URL, Attempt: job.Attempts}
+	r.Events.Publish(EventScrapeStarted, event)
+
+	scraped, err := r.Scraper.Scrape(ctx, bookmark.URL, DefaultScrapeOptions())
+	if err == nil && !scraped.Success {
+		err = errors.New(scraped.Error)
+		if scraped.Error == "" {
+			err = errors.New("failed to scrape content")
+		}
+	}
+	if err != nil {
+		event.Error = err.Error()
+		event.Final = job.Attempts >= job.MaxAttempts
+		if event.Final {
+			r.Storage.UpdateBookmarkStatus(bookmark.ID, "failed")
+		}
+		r.Events.Publish(EventScrapeFailed, event)
+		return "", fmt.Errorf("failed to scrape %s: %w", bookmark.URL, err)
+	}
+
+	// Titles and descriptions from the import or the user take precedence over the page's
+	if bookmark.Title == "" {
+		bookmark.Title = scraped.Title
+	}
+	if bookmark.Description == "" {
+		bookmark.Description = scraped.Description
+	}
+	if scraped.FaviconURL != "" {
+		bookmark.FaviconURL = scraped.FaviconURL
+	}
+	now := time.Now()
+	bookmark.UpdatedAt = now
+	bookmark.ScrapedAt = &now
+
+	if err := r.Storage.UpdateBookmark(bookmark); err != nil {
+		return "", fmt.Errorf("failed to update bookmark: %w", err)
+	}
+	if err := r.Storage.StoreContent(bookmark.ID, scraped.Content, scraped.CleanText); err != nil {
+		return "", fmt.Errorf("failed to store content: %w", err)
+	}
+	event.Title = bookmark.Title
+	r.Events.Publish(EventScrapeFinished, event)
+
+	if r.queue.Handles(storage.JobTypeEmbed) {
+		embedJob := &storage.Job{Type: storage.JobTypeEmbed, BookmarkID: bookmark.ID, Priority: job.Priority}
+		if err := r.queue.Enqueue(embedJob); err != nil {
+			return "", fmt.Errorf("failed to queue embedding: %w", err)
+		}
+	}
+
+	return jobResult(map[string]interface{}{
+		"title":          scraped.Title,
+		"content_length": len(scraped.CleanText),
+	})
+}
+
+// embed chunks and embeds the bookmark's stored content and optionally queues categorization
+func (r *jobRunner) embed(ctx context.Context, job *storage.Job) (string, error) {
+	if _, err := r.bookmark(job); err != nil {
+		return "", err
+	}
+
+	chunks, err := r.ContentProcessor.EmbedBookmark(job.BookmarkID)
+	if err != nil {
+		return "", err
+	}
+
+	if r.config.AutoCategorize && r.queue.Handles(storage.JobTypeCategorize) {
+		categorizeJob := &storage.Job{Type: storage.JobTypeCategorize, BookmarkID: job.BookmarkID}
+		if err := r.queue.Enqueue(categorizeJob); err != nil {
+			return "", fmt.Errorf("failed to queue categorization: %w", err)
+		}
+	}
+
+	return jobResult(map[string]interface{}{"chunks": chunks})
+}
+
+// categorize asks the LLM for categories and applies them when confident enough
+func (r *jobRunner) categorize(ctx context.Context, job *storage.Job) (string, error) {
+	if _, err := r.bookmark(job); err != nil {
+		return "", err
+	}
+
+	result, err := r.Categorization.CategorizeBookmark(ctx, job.BookmarkID)
+	if err != nil {
+		return "", err
+	}
+
+	applied := result.ConfidenceScore >= r.config.CategorizeThreshold
+	if applied {
+		if err := r.Storage.ApproveCategorizationResult(ctx, job.BookmarkID); err != nil {
+			return "", fmt.Errorf("failed to apply categorization: %w", err)
+		}
+	}
+
+	return jobResult(map[string]interface{}{
+		"primary_category": result.PrimaryCategory,
+		"confidence_score": result.ConfidenceScore,
+		"applied":          applied,
+	})
+}
+
+// checkLink requests the bookmark's URL and records the status it answers with. Server errors
+// and rate limiting are retried; other statuses, including 404, are a successful check.
+func (r *jobRunner) checkLink(ctx context.Context, job *storage.Job) (string, error) {
+	bookmark, err := r.bookmark(job)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.request(ctx, http.MethodHead, bookmark.URL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		// Some servers only answer GET
+		resp, err = r.request(ctx, http.MethodGet, bookmark.URL)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to reach %s: %w", bookmark.URL, err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return "", fmt.Errorf("%s answered %s", bookmark.URL, resp.Status)
+	}
+
+	return jobResult(map[string]interface{}{
+		"status_code": resp.StatusCode,
+		"final_url":   resp.Request.URL.String(),
+		"ok":          resp.StatusCode < 400,
+	})
+}
+
+// request sends a request for a link check and discards the body
+func (r *jobRunner) request(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJobNotRetryable, err)
+	}
+	req.Header.Set("User-Agent", DefaultScrapeOptions().UserAgent)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"bookmark-chat/internal/storage"
+	"github.com/google/uuid"
+)
+
+// Job priorities; higher runs first
+const (
+	JobPriorityBackground  = 0
+	JobPriorityInteractive = 10 // Work a user is waiting on, such as a bookmark they just added
+)
+
+// ErrJobNotRetryable marks a job failure that retrying cannot fix, such as a deleted bookmark.
+// Such jobs are dead-lettered right away.
+var ErrJobNotRetryable = errors.New("job cannot be retried")
+
+// JobHandler runs one job and returns an optional JSON result. The context is cancelled when the
+// queue stops.
+type JobHandler func(ctx context.Context, job *storage.Job) (string, error)
+
+// JobQueueConfig holds configuration for the background job workers
+type JobQueueConfig struct {
+	Workers         int
+	ScrapeWorkers   int           // Workers dedicated to scrape jobs, so slow sites do not hold up other jobs; 0 lets Workers scrape
+	HostConcurrency int           // Most scrape and link check jobs running against one host at once; 0 means no cap
+	LeaseDuration   time.Duration // How long a claimed job stays invisible to other workers without a heartbeat
+	PollInterval    time.Duration // How often idle workers look for due jobs
+	RetryBaseDelay  time.Duration // Delay before the first retry, doubled for each further attempt
+	RetryMaxDelay   time.Duration
+}
+
+// DefaultJobQueueConfig returns the default job queue configuration, reading JOB_WORKERS,
+// SCRAPE_WORKERS and SCRAPE_HOST_CONCURRENCY from the environment
+func DefaultJobQueueConfig() JobQueueConfig {
+	config := JobQueueConfig{
+		Workers:         4,
+		ScrapeWorkers:   8,
+		HostConcurrency: scrapeHostConcurrency(),
+		LeaseDuration:   2 * time.Minute,
+		PollInterval:    2 * time.Second,
+		RetryBaseDelay:  30 * time.Second,
+		RetryMaxDelay:   time.Hour,
+	}
+	if workers, err := strconv.Atoi(os.Getenv("JOB_WORKERS")); err == nil && workers > 0 {
+		config.Workers = workers
+	}
+	if workers, err := strconv.Atoi(os.Getenv("SCRAPE_WORKERS")); err == nil && workers >= 0 {
+		config.ScrapeWorkers = workers
+	}
+	return config
+}
+
+// JobQueue runs jobs persisted in storage on a pool of workers. Jobs are leased while they run and
+// the lease is renewed by a heartbeat, so jobs held by a crashed server are picked up again once
+// their lease expires. Failed jobs are retried with exponential backoff and dead-lettered after
+// their last attempt.
+type JobQueue struct {
+	storage  *storage.Storage
+	config   JobQueueConfig
+	owner    string // Identifies this process's leases
+	mu       sync.RWMutex
+	handlers map[string]JobHandler
+	running  map[string]context.CancelFunc // Jobs this process is running, by ID
+	events   *EventBus
+	pools    []*workerPool
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// workerPool is a group of workers that claim only the job types it accepts
+type workerPool struct {
+	name    string
+	workers int
+	accepts func(jobType string) bool
+	wake    chan struct{}
+}
+
+// NewJobQueue creates a job queue; register handlers and call Start to begin running jobs
+func NewJobQueue(store *storage.Storage, config JobQueueConfig) *JobQueue {
+	q := &JobQueue{
+		storage:  store,
+		config:   config,
+		owner:    uuid.New().String(),
+		handlers: make(map[string]JobHandler),
+		running:  make(map[string]context.CancelFunc),
+	}
+
+	scrapePool := config.ScrapeWorkers > 0
+	q.pools = append(q.pools, &workerPool{
+		name:    "general",
+		workers: config.Workers,
+		accepts: func(jobType string) bool { return !scrapePool || jobType != storage.JobTypeScrape },
+		wake:    make(chan struct{}, 1),
+	})
+	if scrapePool {
+		q.pools = append(q.pools, &workerPool{
+			name:    "scrape",
+			workers: config.ScrapeWorkers,
+			accepts: func(jobType string) bool { return jobType == storage.JobTypeScrape },
+			wake:    make(chan struct{}, 1),
+		})
+	}
+	return q
+}
+
+// SetEventBus publishes job state changes on the bus; call it before Start
+func (q *JobQueue) SetEventBus(events *EventBus) {
+	q.events = events
+}
+
+// Handle registers the handler for a job type. Jobs of types without a handler stay queued.
+func (q *JobQueue) Handle(jobType string, handler JobHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Handles reports whether a handler is registered for the job type
+func (q *JobQueue) Handles(jobType string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	_, ok := q.handlers[jobType]
+	return ok
+}
+
+// Enqueue adds jobs to the queue and wakes idle workers
+func (q *JobQueue) Enqueue(jobs ...*storage.Job) error {
+	if err := q.storage.EnqueueJobs(jobs); err != nil {
+		return err
+	}
+	q.notify()
+	return nil
+}
+
+// EnqueueBookmarks queues one job of the given type for each bookmark
+func (q *JobQueue) EnqueueBookmarks(jobType string, bookmarkIDs []string, priority int, batchID string) error {
+	jobs := make([]*storage.Job, len(bookmarkIDs))
+	for i, id := range bookmarkIDs {
+		jobs[i] = &storage.Job{Type: jobType, BookmarkID: id, Priority: priority, BatchID: batchID}
+	}
+	return q.Enqueue(jobs...)
+}
+
+// Cancel cancels an unfinished job, stopping it right away if this process is running it
+func (q *JobQueue) Cancel(id string) (*storage.Job, error) {
+	job, err := q.storage.CancelJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mu.RLock()
+	cancel, ok := q.running[id]
+	q.mu.RUnlock()
+	if ok {
+		cancel()
+	}
+	q.publish(job, job.State, "", nil)
+	return job, nil
+}
+
+// Retry queues a dead or cancelled job again and wakes idle workers
+func (q *JobQueue) Retry(id string) (*storage.Job, error) {
+	job, err := q.storage.RequeueJob(id)
+	if err != nil {
+		return nil, err
+	}
+	q.publish(job, job.State, "", nil)
+	q.notify()
+	return job, nil
+}
+
+// Start launches the workers; they run until Stop is called
+func (q *JobQueue) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+
+	for _, pool := range q.pools {
+		for i := 0; i < pool.workers; i++ {
+			q.wg.Add(1)
+			go q.work(ctx, pool)
+		}
+		log.Printf("⚙️  Job queue started %d %s workers", pool.workers, pool.name)
+	}
+}
+
+// Stop cancels running jobs, returns them to the queue and waits for the workers to exit
+func (q *JobQueue) Stop() {
+	if q.cancel != nil {
+		q.cancel()
+	}
+	q.wg.Wait()
+}
+
+// notify wakes one idle worker of each pool
+func (q *JobQueue) notify() {
+	for _, pool := range q.pools {
+		select {
+		case pool.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// types lists the job types the pool accepts that have a registered handler
+func (q *JobQueue) types(pool *workerPool) []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	types := make([]string, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		if pool.accepts(jobType) {
+			types = append(types, jobType)
+		}
+	}
+	return types
+}
+
+// work claims and runs the pool's jobs until ctx is cancelled, waiting for a wake-up or the poll
+// interval when idle
+func (q *JobQueue) work(ctx context.Context, pool *workerPool) {
+	defer q.wg.Done()
+
+	for ctx.Err() == nil {
+		job, err := q.storage.ClaimJob(q.owner, q.types(pool), q.config.LeaseDuration, q.config.HostConcurrency)
+		if err != nil {
+			log.Printf("❌ Failed to claim job: %v", err)
+		}
+		if job != nil {
+			q.run(ctx, job)
+			// Another job may be waiting, so hand the wake-up on
+			q.notify()
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-pool.wake:
+		case <-time.After(q.config.PollInterval):
+		}
+	}
+}
+
+// run executes a claimed job, renewing its lease until the handler returns, and records the outcome
+func (q *JobQueue) run(ctx context.Context, job *storage.Job) {
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+	if !ok {
+		q.storage.ReleaseJob(job.ID, q.owner)
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go q.heartbeat(jobCtx, cancel, job.ID)
+
+	q.mu.Lock()
+	q.running[job.ID] = cancel
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.running, job.ID)
+		q.mu.Unlock()
+	}()
+	q.publish(job, storage.JobRunning, "", nil)
+
+	var result string
+	var err error
+	if job.Attempts > job.MaxAttempts {
+		// The job kept being reclaimed after expired leases, most likely because it crashes the worker
+		err = fmt.Errorf("%w: gave up after %d attempts", ErrJobNotRetryable, job.MaxAttempts)
+	} else {
+		result, err = handler(jobCtx, job)
+	}
+
+	switch {
+	case jobCtx.Err() != nil && ctx.Err() == nil:
+		// Cancelled through the API or the lease was lost; the job's state is no longer ours to set
+		return
+	case ctx.Err() != nil:
+		// Shutting down: let the next start run the job again without spending an attempt
+		err = q.storage.ReleaseJob(job.ID, q.owner)
+	case err == nil:
+		if err = q.storage.CompleteJob(job.ID, q.owner, result); err == nil {
+			q.publish(job, storage.JobSucceeded, "", nil)
+		}
+	case errors.Is(err, ErrJobNotRetryable) || job.Attempts >= job.MaxAttempts:
+		log.Printf("💀 %s job %s failed permanently: %v", job.Type, job.ID, err)
+		message := err.Error()
+		if err = q.storage.DeadLetterJob(job.ID, q.owner, message); err == nil {
+			q.publish(job, storage.JobDead, message, nil)
+		}
+	default:
+		delay := q.retryDelay(job.Attempts)
+		log.Printf("🔁 %s job %s failed (attempt %d/%d), retrying in %v: %v", job.Type, job.ID, job.Attempts, job.MaxAttempts, delay, err)
+		message, retryAt := err.Error(), time.Now().Add(delay)
+		if err = q.storage.RetryJob(job.ID, q.owner, message, retryAt); err == nil {
+			q.publish(job, storage.JobQueued, message, &retryAt)
+		}
+	}
+	if err != nil {
+		log.Printf("❌ Failed to record outcome of %s job %s: %v", job.Type, job.ID, err)
+	}
+}
+
+// publish announces that a job moved to state
+func (q *JobQueue) publish(job *storage.Job, state, message string, retryAt *time.Time) {
+	q.events.Publish(EventJobProgress, JobProgressEvent{
+		JobID:       job.ID,
+		Type:        job.Type,
+		BookmarkID:  job.BookmarkID,
+		BatchID:     job.BatchID,
+		State:       state,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		Error:       message,
+		RetryAt:     retryAt,
+	})
+}
+
+// heartbeat renews the job's lease until ctx is done, and cancels the job if the lease is lost
+func (q *JobQueue) heartbeat(ctx context.Context, cancel context.CancelFunc, jobID string) {
+	ticker := time.NewTicker(q.config.LeaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := q.storage.ExtendJobLease(jobID, q.owner, q.config.LeaseDuration); errors.Is(err, storage.ErrJobLeaseLost) {
+				log.Printf("⚠️  Lost lease on job %s, cancelling it", jobID)
+				cancel()
+				return
+			} else if err != nil {
+				log.Printf("❌ Failed to renew lease on job %s: %v", jobID, err)
+			}
+		}
+	}
+}
+
+// retryDelay is the exponential backoff before retrying a job that failed on the given attempt
+func (q *JobQueue) retryDelay(attempt int) time.Duration {
+	delay := q.config.RetryBaseDelay
+	for i := 1; i < attempt && delay < q.config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > q.config.RetryMaxDelay {
+		delay = q.config.RetryMaxDelay
+	}
+	return delay
+}
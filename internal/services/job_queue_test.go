@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bookmark-chat/internal/storage"
+)
+
+func TestJobQueueRetryDelay(t *testing.T) {
+	queue := NewJobQueue(nil, JobQueueConfig{RetryBaseDelay: 30 * time.Second, RetryMaxDelay: 5 * time.Minute})
+
+	expected := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, want := range expected {
+		if got := queue.retryDelay(i + 1); got != want {
+			t.Errorf("Expected attempt %d to wait %v, got %v", i+1, want, got)
+		}
+	}
+}
+
+// newTestStorage opens a migrated database in the test's temporary directory
+func newTestStorage(t *testing.T) *storage.Storage {
+	t.Helper()
+	store, err := storage.New("file:" + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// testJobQueueConfig runs jobs on a single worker that polls often and retries without delay
+func testJobQueueConfig() JobQueueConfig {
+	return JobQueueConfig{Workers: 1, LeaseDuration: time.Minute, PollInterval: 10 * time.Millisecond}
+}
+
+// waitForJobState polls until the job reaches state, failing the test after a few seconds
+func waitForJobState(t *testing.T, store *storage.Storage, id, state string) *storage.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := store.GetJob(id)
+		if err != nil {
+			t.Fatalf("Failed to get job: %v", err)
+		}
+		if job.State == state {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected job to become %s, still %s after %d attempts", state, job.State, job.Attempts)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// enqueueScrapeJob queues a scrape job for a new bookmark
+func enqueueScrapeJob(t *testing.T, store *storage.Storage, queue *JobQueue, maxAttempts int) *storage.Job {
+	t.Helper()
+	bookmark := &storage.Bookmark{URL: "https://example.com/article", Title: "Article"}
+	if err := store.CreateBookmark(bookmark); err != nil {
+		t.Fatalf("Failed to create bookmark: %v", err)
+	}
+	job := &storage.Job{Type: storage.JobTypeScrape, BookmarkID: bookmark.ID, MaxAttempts: maxAttempts}
+	if err := queue.Enqueue(job); err != nil {
+		t.Fatalf("Failed to enqueue job: %v", err)
+	}
+	return job
+}
+
+func TestJobQueueRetriesThenDeadLetters(t *testing.T) {
+	store := newTestStorage(t)
+	queue := NewJobQueue(store, testJobQueueConfig())
+
+	var calls int32
+	queue.Handle(storage.JobTypeScrape, func(ctx context.Context, job *storage.Job) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "", errors.New("site unavailable")
+	})
+	job := enqueueScrapeJob(t, store, queue, 3)
+
+	queue.Start()
+	defer queue.Stop()
+
+	dead := waitForJobState(t, store, job.ID, storage.JobDead)
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected the handler to run once per attempt (3), ran %d times", got)
+	}
+	if dead.Attempts != 3 || dead.LastError != "site unavailable" {
+		t.Errorf("Expected a dead job after 3 attempts with the last error, got %+v", dead)
+	}
+}
+
+func TestJobQueueNotRetryableFailsImmediately(t *testing.T) {
+	store := newTestStorage(t)
+	queue := NewJobQueue(store, testJobQueueConfig())
+	queue.Handle(storage.JobTypeScrape, func(ctx context.Context, job *storage.Job) (string, error) {
+		return "", ErrJobNotRetryable
+	})
+	job := enqueueScrapeJob(t, store, queue, 5)
+
+	queue.Start()
+	defer queue.Stop()
+
+	if dead := waitForJobState(t, store, job.ID, storage.JobDead); dead.Attempts != 1 {
+		t.Errorf("Expected a job that cannot be retried to die on its first attempt, got %d", dead.Attempts)
+	}
+}
+
+func TestJobQueueResumesAfterRestart(t *testing.T) {
+	store := newTestStorage(t)
+
+	// The first server is stopped while the job runs
+	started := make(chan struct{})
+	first := NewJobQueue(store, testJobQueueConfig())
+	first.Handle(storage.JobTypeScrape, func(ctx context.Context, job *storage.Job) (string, error) {
+		close(started)
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	job := enqueueScrapeJob(t, store, first, 3)
+	first.Start()
+	<-started
+	first.Stop()
+
+	released, err := store.GetJob(job.ID)
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if released.State != storage.JobQueued || released.Attempts != 0 {
+		t.Fatalf("Expected the interrupted job back in the queue without spending an attempt, got %+v", released)
+	}
+
+	// The next server picks it up from storage
+	second := NewJobQueue(store, testJobQueueConfig())
+	second.Handle(storage.JobTypeScrape, func(ctx context.Context, job *storage.Job) (string, error) {
+		return `{"resumed":true}`, nil
+	})
+	second.Start()
+	defer second.Stop()
+
+	done := waitForJobState(t, store, job.ID, storage.JobSucceeded)
+	if done.Attempts != 1 || done.Result != `{"resumed":true}` {
+		t.Errorf("Expected the job to succeed on its first counted attempt, got %+v", done)
+	}
+}
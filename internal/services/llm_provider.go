@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// EmbeddingProvider generates vector embeddings for text
+type EmbeddingProvider interface {
+	CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error)
+	EmbeddingModel() string
+	EmbeddingDimensions() int
+}
+
+// ChatProvider generates chat completions
+type ChatProvider interface {
+	ChatCompletion(ctx context.Context, req ChatCompletionRequest) (string, error)
+	// ChatCompletionStream calls onDelta with each chunk of the reply and returns the full reply
+	ChatCompletionStream(ctx context.Context, req ChatCompletionRequest, onDelta func(string) error) (string, error)
+}
+
+// LLMProvider is a backend that serves both embeddings and chat completions
+type LLMProvider interface {
+	EmbeddingProvider
+	ChatProvider
+}
+
+// ChatPurpose tells a provider what a completion is used for. Remote models ignore it;
+// the local provider uses it to pick a responder.
+type ChatPurpose string
+
+const (
+	ChatPurposeAnswer     ChatPurpose = "answer"
+	ChatPurposeRewrite    ChatPurpose = "rewrite"
+	ChatPurposeCategorize ChatPurpose = "categorize"
+)
+
+// ChatCompletionRequest is a provider-independent chat completion request
+type ChatCompletionRequest struct {
+	Model       string // Optional, overrides the provider's default chat model
+	Purpose     ChatPurpose
+	Messages    []Message
+	MaxTokens   int
+	Temperature float32
+	TopP        float32
+}
+
+type LLMProviderType string
+
+const (
+	// LLMProviderOpenAI talks to the OpenAI API or any OpenAI-compatible server (Ollama, vLLM, LM Studio)
+	LLMProviderOpenAI LLMProviderType = "openai"
+	// LLMProviderAzure talks to an Azure OpenAI resource; models are deployment names
+	LLMProviderAzure LLMProviderType = "azure"
+	// LLMProviderLocal runs fully offline with hashed n-gram embeddings and a rule-based responder
+	LLMProviderLocal LLMProviderType = "local"
+)
+
+const defaultEmbeddingDimensions = 1536
+
+// LLMConfig configures the LLM provider
+type LLMConfig struct {
+	Provider            LLMProviderType `json:"provider"`
+	BaseURL             string          `json:"base_url,omitempty"`
+	APIKey              string          `json:"-"`
+	EmbeddingModel      string          `json:"embedding_model"`
+	EmbeddingDimensions int             `json:"embedding_dimensions,omitempty"`
+	ChatModel           string          `json:"chat_model"`
+	AzureAPIVersion     string          `json:"azure_api_version,omitempty"`
+}
+
+// DefaultLLMConfig returns the LLM configuration from environment variables
+func DefaultLLMConfig() LLMConfig {
+	config := LLMConfig{
+		Provider:        LLMProviderType(os.Getenv("LLM_PROVIDER")),
+		BaseURL:         os.Getenv("LLM_BASE_URL"),
+		APIKey:          os.Getenv("LLM_API_KEY"),
+		EmbeddingModel:  os.Getenv("EMBEDDING_MODEL"),
+		ChatModel:       os.Getenv("CHAT_MODEL"),
+		AzureAPIVersion: os.Getenv("AZURE_OPENAI_API_VERSION"),
+	}
+
+	if config.Provider == "" {
+		config.Provider = LLMProviderOpenAI
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if config.EmbeddingModel == "" {
+		config.EmbeddingModel = "text-embedding-3-small" // 1536 dimensions, optimized for retrieval
+	}
+	if config.ChatModel == "" {
+		config.ChatModel = "gpt-4o-mini"
+	}
+	if dimensions, err := strconv.Atoi(os.Getenv("EMBEDDING_DIMENSIONS")); err == nil && dimensions > 0 {
+		config.EmbeddingDimensions = dimensions
+	}
+
+	return config
+}
+
+// NewLLMProvider creates the LLM provider described by config
+func NewLLMProvider(config LLMConfig) (LLMProvider, error) {
+	switch config.Provider {
+	case LLMProviderOpenAI:
+		// A key is only mandatory for the public API; local servers usually accept any key
+		if config.APIKey == "" && config.BaseURL == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY or LLM_API_KEY environment variable is required (or set LLM_BASE_URL for an OpenAI-compatible server)")
+		}
+		clientConfig := openai.DefaultConfig(config.APIKey)
+		if config.BaseURL != "" {
+			clientConfig.BaseURL = config.BaseURL
+		}
+		return newOpenAIProvider(clientConfig, config), nil
+	case LLMProviderAzure:
+		if config.BaseURL == "" || config.APIKey == "" {
+			return nil, fmt.Errorf("LLM_BASE_URL and LLM_API_KEY are required for the azure provider")
+		}
+		clientConfig := openai.DefaultAzureConfig(config.APIKey, config.BaseURL)
+		if config.AzureAPIVersion != "" {
+			clientConfig.APIVersion = config.AzureAPIVersion
+		}
+		// Use the configured model names as deployment names without rewriting them
+		clientConfig.AzureModelMapperFunc = func(model string) string {
+			return model
+		}
+		return newOpenAIProvider(clientConfig, config), nil
+	case LLMProviderLocal:
+		return NewLocalProvider(config.EmbeddingDimensions), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
+	}
+}
+
+// openAIProvider implements LLMProvider on top of the OpenAI API
+type openAIProvider struct {
+	client              *openai.Client
+	embeddingModel      string
+	embeddingDimensions int
+	chatModel           string
+}
+
+func newOpenAIProvider(clientConfig openai.ClientConfig, config LLMConfig) *openAIProvider {
+	return &openAIProvider{
+		client:              openai.NewClientWithConfig(clientConfig),
+		embeddingModel:      config.EmbeddingModel,
+		embeddingDimensions: config.EmbeddingDimensions,
+		chatModel:           config.ChatModel,
+	}
+}
+
+func (p *openAIProvider) EmbeddingModel() string {
+	return p.embeddingModel
+}
+
+func (p *openAIProvider) EmbeddingDimensions() int {
+	if p.embeddingDimensions > 0 {
+		return p.embeddingDimensions
+	}
+	return defaultEmbeddingDimensions
+}
+
+func (p *openAIProvider) CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := p.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+		Model:      openai.EmbeddingModel(p.embeddingModel),
+		Input:      texts,
+		Dimensions: p.embeddingDimensions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	embeddings := make([][]float32, len(resp.Data))
+	for i, data := range resp.Data {
+		embeddings[i] = data.Embedding
+	}
+	return embeddings, nil
+}
+
+func (p *openAIProvider) ChatCompletion(ctx context.Context, req ChatCompletionRequest) (string, error) {
+	resp, err := p.client.CreateChatCompletion(ctx, p.chatRequest(req))
+	if err != nil {
+		return "", fmt.Errorf("chat completion request failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response choices returned")
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+func (p *openAIProvider) ChatCompletionStream(ctx context.Context, req ChatCompletionRequest, onDelta func(string) error) (string, error) {
+	chatReq := p.chatRequest(req)
+	chatReq.Stream = true
+
+	stream, err := p.client.CreateChatCompletionStream(ctx, chatReq)
+	if err != nil {
+		return "", fmt.Errorf("chat completion stream request failed: %w", err)
+	}
+	defer stream.Close()
+
+	var reply []byte
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("chat completion stream failed: %w", err)
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := resp.Choices[0].Delta.Content
+		reply = append(reply, delta...)
+		if err := onDelta(delta); err != nil {
+			return "", err
+		}
+	}
+
+	return string(reply), nil
+}
+
+// chatRequest converts a provider-independent request to an OpenAI request
+func (p *openAIProvider) chatRequest(req ChatCompletionRequest) openai.ChatCompletionRequest {
+	model := req.Model
+	if model == "" {
+		model = p.chatModel
+	}
+
+	messages := make([]openai.ChatCompletionMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		messages[i] = openai.ChatCompletionMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+
+	return openai.ChatCompletionRequest{
+		Model:       model,
+		Messages:    messages,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+}
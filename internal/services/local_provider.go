@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"bookmark-chat/internal/storage"
+)
+
+// LocalProvider is an offline LLMProvider. Embeddings are hashed word and character n-grams, and chat
+// completions come from simple rule-based responders. Results are deterministic, which makes the
+// provider suitable for tests and air-gapped installs.
+type LocalProvider struct {
+	dimensions int
+}
+
+// NewLocalProvider creates a local provider producing embeddings with the given number of dimensions
+// (1536 when zero, matching the default remote embedding model)
+func NewLocalProvider(dimensions int) *LocalProvider {
+	if dimensions <= 0 {
+		dimensions = defaultEmbeddingDimensions
+	}
+	return &LocalProvider{dimensions: dimensions}
+}
+
+func (p *LocalProvider) EmbeddingModel() string {
+	return "local-ngram-hash"
+}
+
+func (p *LocalProvider) EmbeddingDimensions() int {
+	return p.dimensions
+}
+
+func (p *LocalProvider) CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i] = p.embed(text)
+	}
+	return embeddings, nil
+}
+
+// embed hashes word unigrams, word bigrams and character trigrams into a signed, L2-normalized vector
+func (p *LocalProvider) embed(text string) []float32 {
+	vector := make([]float64, p.dimensions)
+
+	add := func(feature string, weight float64) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		index := int(sum % uint64(p.dimensions))
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		vector[index] += weight
+	}
+
+	tokens := localTokenize(text)
+	for i, token := range tokens {
+		add("w:"+token, 1.0)
+		if i > 0 {
+			add("b:"+tokens[i-1]+" "+token, 0.75)
+		}
+		padded := []rune("^" + token + "$")
+		for j := 0; j+3 <= len(padded); j++ {
+			add("c:"+string(padded[j:j+3]), 0.5)
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+
+	embedding := make([]float32, p.dimensions)
+	if norm == 0 {
+		// Keep the vector non-zero so cosine distance stays defined
+		embedding[0] = 1
+		return embedding
+	}
+
+	norm = math.Sqrt(norm)
+	for i, v := range vector {
+		embedding[i] = float32(v / norm)
+	}
+	return embedding
+}
+
+func (p *LocalProvider) ChatCompletion(ctx context.Context, req ChatCompletionRequest) (string, error) {
+	message := lastUserMessage(req.Messages)
+
+	switch req.Purpose {
+	case ChatPurposeRewrite:
+		return localRewrite(message), nil
+	case ChatPurposeCategorize:
+		return localCategorize(message)
+	default:
+		return localAnswer(message), nil
+	}
+}
+
+func (p *LocalProvider) ChatCompletionStream(ctx context.Context, req ChatCompletionRequest, onDelta func(string) error) (string, error) {
+	reply, err := p.ChatCompletion(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	// Emit the reply word by word so streaming clients behave as with a remote model
+	for _, word := range strings.SplitAfter(reply, " ") {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := onDelta(word); err != nil {
+			return "", err
+		}
+	}
+
+	return reply, nil
+}
+
+// lastUserMessage returns the content of the last user message
+func lastUserMessage(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+var localStopwords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "can": true, "do": true, "does": true, "for": true, "from": true, "how": true, "i": true,
+	"in": true, "is": true, "it": true, "its": true, "me": true, "my": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "their": true, "them": true, "these": true, "they": true,
+	"this": true, "those": true, "to": true, "was": true, "what": true, "when": true, "where": true,
+	"which": true, "who": true, "why": true, "will": true, "with": true, "you": true, "your": true,
+}
+
+// localReferenceWords mark follow-up messages that depend on the previous turn
+var localReferenceWords = map[string]bool{
+	"it": true, "its": true, "that": true, "this": true, "these": true, "those": true, "they": true,
+	"them": true, "one": true, "ones": true, "first": true, "second": true, "third": true, "last": true,
+	"more": true, "else": true, "other": true, "same": true,
+}
+
+// localTokenize lowercases text and splits it into letter and digit runs
+func localTokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// localKeywords returns the tokens of text that are not stopwords, in order and without duplicates
+func localKeywords(text string) []string {
+	seen := make(map[string]bool)
+	keywords := []string{}
+	for _, token := range localTokenize(text) {
+		if localStopwords[token] || seen[token] {
+			continue
+		}
+		seen[token] = true
+		keywords = append(keywords, token)
+	}
+	return keywords
+}
+
+// localRewrite turns a follow-up message into a standalone query by borrowing the keywords of the
+// previous user message when the follow-up refers back to it
+func localRewrite(prompt string) string {
+	followUp := prompt
+	if idx := strings.LastIndex(prompt, rewriteFollowUpLabel); idx >= 0 {
+		followUp = prompt[idx+len(rewriteFollowUpLabel):]
+		if end := strings.Index(followUp, "\n"); end >= 0 {
+			followUp = followUp[:end]
+		}
+	}
+	followUp = strings.TrimSpace(followUp)
+
+	referential := false
+	for _, token := range localTokenize(followUp) {
+		if localReferenceWords[token] {
+			referential = true
+			break
+		}
+	}
+	if !referential {
+		return followUp
+	}
+
+	// Find the most recent user turn in the transcript
+	var previous string
+	for _, line := range strings.Split(prompt, "\n") {
+		if strings.HasPrefix(line, "user: ") {
+			previous = strings.TrimPrefix(line, "user: ")
+		}
+	}
+	if previous == "" {
+		return followUp
+	}
+
+	terms := localKeywords(followUp)
+	seen := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		seen[term] = true
+	}
+	for _, term := range localKeywords(previous) {
+		if !seen[term] {
+			terms = append(terms, term)
+		}
+	}
+
+	return strings.Join(terms, " ")
+}
+
+var localCategoryKeywords = []struct {
+	Category string
+	Keywords []string
+}{
+	{"Programming", []string{"code", "programming", "golang", "go", "python", "rust", "java", "library", "function", "compiler", "github", "developer"}},
+	{"Web Development", []string{"html", "css", "javascript", "react", "frontend", "browser", "web", "http", "typescript"}},
+	{"Data Science", []string{"data", "machine", "learning", "model", "dataset", "statistics", "neural", "ai", "analytics"}},
+	{"DevOps", []string{"docker", "kubernetes", "deploy", "deployment", "cloud", "aws", "server", "linux", "infrastructure"}},
+	{"Databases", []string{"sql", "database", "postgres", "sqlite", "query", "index", "mysql", "nosql"}},
+	{"Security", []string{"security", "vulnerability", "encryption", "authentication", "password", "privacy", "attack"}},
+	{"Business", []string{"business", "startup", "marketing", "sales", "company", "management", "product"}},
+	{"Finance", []string{"finance", "money", "investing", "stock", "bank", "budget", "tax"}},
+	{"Education", []string{"course", "tutorial", "learn", "university", "lesson", "guide", "education"}},
+	{"Health", []string{"health", "fitness", "medical", "nutrition", "exercise", "sleep"}},
+	{"News", []string{"news", "breaking", "report", "politics", "election"}},
+	{"Entertainment", []string{"movie", "music", "game", "games", "film", "tv", "series"}},
+	{"Travel", []string{"travel", "flight", "hotel", "trip", "destination"}},
+	{"Food", []string{"recipe", "food", "cooking", "restaurant", "baking"}},
+	{"Science", []string{"science", "physics", "chemistry", "biology", "research", "space"}},
+}
+
+// localCategorize assigns categories by keyword matching on the bookmark fields of the categorization prompt
+func localCategorize(prompt string) (string, error) {
+	var title, body strings.Builder
+	for _, line := range strings.Split(prompt, "\n") {
+		switch {
+		case strings.HasPrefix(line, "Title: "):
+			title.WriteString(strings.TrimPrefix(line, "Title: "))
+		case strings.HasPrefix(line, "URL: "), strings.HasPrefix(line, "Folder Path: "), strings.HasPrefix(line, "Content: "):
+			body.WriteString(line[strings.Index(line, ": ")+2:])
+			body.WriteString(" ")
+		}
+	}
+
+	// Title words count double
+	counts := make(map[string]int)
+	for _, token := range localTokenize(title.String()) {
+		counts[token] += 2
+	}
+	for _, token := range localTokenize(body.String()) {
+		counts[token]++
+	}
+
+	type scored struct {
+		category string
+		score    int
+	}
+	var scores []scored
+	for _, rule := range localCategoryKeywords {
+		score := 0
+		for _, keyword := range rule.Keywords {
+			score += counts[keyword]
+		}
+		if score > 0 {
+			scores = append(scores, scored{rule.Category, score})
+		}
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].score > scores[j].score })
+
+	result := storage.CategorizationResult{
+		PrimaryCategory:     "General",
+		SecondaryCategories: []string{},
+		Tags:                localTopTerms(counts, 5),
+		ConfidenceScore:     0.3,
+		Reasoning:           "No topic keywords matched; assigned a general category.",
+	}
+	if len(scores) > 0 {
+		result.PrimaryCategory = scores[0].category
+		result.ConfidenceScore = math.Min(0.95, 0.4+0.1*float64(scores[0].score))
+		result.Reasoning = fmt.Sprintf("Title and content match %s keywords.", scores[0].category)
+		for _, s := range scores[1:] {
+			if len(result.SecondaryCategories) == 2 {
+				break
+			}
+			result.SecondaryCategories = append(result.SecondaryCategories, s.category)
+		}
+	}
+
+	response, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("marshal categorization: %w", err)
+	}
+	return string(response), nil
+}
+
+// localTopTerms returns the most frequent meaningful terms, ties broken alphabetically
+func localTopTerms(counts map[string]int, limit int) []string {
+	terms := []string{}
+	for term := range counts {
+		if len(term) < 3 || localStopwords[term] || strings.Trim(term, "0123456789") == "" {
+			continue
+		}
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if counts[terms[i]] != counts[terms[j]] {
+			return counts[terms[i]] > counts[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > limit {
+		terms = terms[:limit]
+	}
+	return terms
+}
+
+// localSourceHeader matches the numbered source headers of the chat prompt, e.g. "[2] Title (https://...)"
+var localSourceHeader = regexp.MustCompile(`(?m)^\[(\d+)\] (.*)$`)
+
+// localSentenceSplitter splits excerpts into sentences
+var localSentenceSplitter = regexp.MustCompile(`[.!?]\s+|\n+`)
+
+// localAnswer builds an extractive answer from the sentences of the numbered sources that best match
+// the question, citing each sentence with its source marker
+func localAnswer(prompt string) string {
+	question := prompt
+	contextPart := ""
+	if idx := strings.LastIndex(prompt, chatQuestionLabel); idx >= 0 {
+		question = prompt[idx+len(chatQuestionLabel):]
+		contextPart = prompt[:idx]
+	}
+
+	questionTerms := make(map[string]bool)
+	for _, term := range localKeywords(question) {
+		questionTerms[term] = true
+	}
+
+	type sentence struct {
+		text   string
+		marker string
+		score  int
+		order  int
+	}
+	var sentences []sentence
+	firstTitle := ""
+
+	headers := localSourceHeader.FindAllStringSubmatchIndex(contextPart, -1)
+	for i, header := range headers {
+		marker := contextPart[header[2]:header[3]]
+		if i == 0 {
+			firstTitle = contextPart[header[4]:header[5]]
+		}
+		end := len(contextPart)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+
+		for _, text := range localSentenceSplitter.Split(contextPart[header[1]:end], -1) {
+			text = strings.TrimSpace(text)
+			if text == "" {
+				continue
+			}
+			score := 0
+			for _, term := range localKeywords(text) {
+				if questionTerms[term] {
+					score++
+				}
+			}
+			if score > 0 {
+				sentences = append(sentences, sentence{text: text, marker: marker, score: score, order: len(sentences)})
+			}
+		}
+	}
+
+	if len(sentences) == 0 {
+		if firstTitle == "" {
+			return "I couldn't find a direct answer to this in your bookmarks."
+		}
+		return fmt.Sprintf("I couldn't find a direct answer to this in your bookmarks. The closest match is %s [1].", firstTitle)
+	}
+
+	sort.SliceStable(sentences, func(i, j int) bool { return sentences[i].score > sentences[j].score })
+	if len(sentences) > 3 {
+		sentences = sentences[:3]
+	}
+	sort.SliceStable(sentences, func(i, j int) bool { return sentences[i].order < sentences[j].order })
+
+	var sb strings.Builder
+	sb.WriteString("Based on your bookmarks:\n")
+	for _, s := range sentences {
+		fmt.Fprintf(&sb, "\n- %s [%s]", strings.TrimRight(s.text, "."), s.marker)
+	}
+	return sb.String()
+}
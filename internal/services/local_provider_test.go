@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"bookmark-chat/internal/storage"
+)
+
+func TestLocalProvider_Embeddings(t *testing.T) {
+	provider := NewLocalProvider(0)
+	ctx := context.Background()
+
+	embeddings, err := provider.CreateEmbeddings(ctx, []string{
+		"Concurrency in Go with goroutines and channels",
+		"Go concurrency patterns: goroutines and channels",
+		"Sourdough bread baking recipe",
+	})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings failed: %v", err)
+	}
+
+	for i, embedding := range embeddings {
+		if len(embedding) != 1536 {
+			t.Errorf("Embedding %d has %d dimensions, expected 1536", i, len(embedding))
+		}
+	}
+
+	again, _ := provider.CreateEmbeddings(ctx, []string{"Concurrency in Go with goroutines and channels"})
+	for i := range again[0] {
+		if again[0][i] != embeddings[0][i] {
+			t.Fatal("Expected embeddings to be deterministic")
+		}
+	}
+
+	related := cosineSimilarity(embeddings[0], embeddings[1])
+	unrelated := cosineSimilarity(embeddings[0], embeddings[2])
+	if related <= unrelated {
+		t.Errorf("Expected related texts to be closer (%.3f) than unrelated ones (%.3f)", related, unrelated)
+	}
+}
+
+func TestLocalProvider_Categorize(t *testing.T) {
+	provider := NewLocalProvider(0)
+	service := &CategorizationService{provider: provider}
+
+	prompt := service.buildCategorizationPrompt(&storage.Bookmark{
+		URL:         "https://go.dev/doc/effective_go",
+		Title:       "Effective Go programming",
+		Description: "Tips for writing clear, idiomatic Go code and using the standard library.",
+	}, nil)
+
+	response, err := provider.ChatCompletion(context.Background(), ChatCompletionRequest{
+		Purpose:  ChatPurposeCategorize,
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion failed: %v", err)
+	}
+
+	var result storage.CategorizationResult
+	if err := json.Unmarshal([]byte(response), &result); err != nil {
+		t.Fatalf("Expected valid categorization JSON, got %q: %v", response, err)
+	}
+
+	if result.PrimaryCategory != "Programming" {
+		t.Errorf("Expected primary category Programming, got %s", result.PrimaryCategory)
+	}
+	if len(result.Tags) == 0 {
+		t.Error("Expected tags to be extracted")
+	}
+}
+
+func TestLocalProvider_AnswerCitesSources(t *testing.T) {
+	provider := NewLocalProvider(0)
+	prompt := "Bookmarked content:\n\n" +
+		"[1] Go Blog (https://go.dev/blog)\nGoroutines are lightweight threads managed by the Go runtime. The blog has many posts.\n\n" +
+		"[2] Baking (https://example.com/bread)\nSourdough needs a starter.\n\n" +
+		chatQuestionLabel + "What are goroutines?"
+
+	reply, err := provider.ChatCompletion(context.Background(), ChatCompletionRequest{
+		Purpose:  ChatPurposeAnswer,
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion failed: %v", err)
+	}
+
+	if !strings.Contains(reply, "lightweight threads") || !strings.Contains(reply, "[1]") {
+		t.Errorf("Expected an answer citing source 1, got %q", reply)
+	}
+	if strings.Contains(reply, "[2]") {
+		t.Errorf("Did not expect the unrelated source to be cited, got %q", reply)
+	}
+}
+
+func TestLocalProvider_Rewrite(t *testing.T) {
+	provider := NewLocalProvider(0)
+	prompt := "Conversation:\nuser: postgres indexing strategies\nassistant: Here are some options [1]\n\n" +
+		rewriteFollowUpLabel + "tell me more about the second one\n\nStandalone search query:"
+
+	query, err := provider.ChatCompletion(context.Background(), ChatCompletionRequest{
+		Purpose:  ChatPurposeRewrite,
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion failed: %v", err)
+	}
+
+	if !strings.Contains(query, "postgres") || !strings.Contains(query, "indexing") {
+		t.Errorf("Expected rewritten query to carry terms from the previous turn, got %q", query)
+	}
+}
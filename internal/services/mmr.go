@@ -0,0 +1,91 @@
+package services
+
+import (
+	"math"
+
+	"bookmark-chat/internal/storage"
+)
+
+// DefaultMMRLambda weighs relevance against novelty when diversifying chat context
+const DefaultMMRLambda = 0.7
+
+const (
+	// mmrCandidateFactor is how many candidates per requested result MMR chooses from
+	mmrCandidateFactor = 3
+	// maxMMRCandidates caps the candidate set fetched for re-ranking
+	maxMMRCandidates = 100
+	// defaultSearchLimit is the result count used when a query sets no limit
+	defaultSearchLimit = 20
+)
+
+// RerankMMR picks up to limit results by maximal marginal relevance: each pick maximizes
+// lambda*relevance - (1-lambda)*similarity to the results already picked. A lambda of 1 keeps the
+// relevance order; lower values favour results unlike the ones before them. Results without an
+// embedding are never penalized as redundant.
+func RerankMMR(results []*storage.SearchResult, embeddings map[string][]float32, lambda float64, limit int) []*storage.SearchResult {
+	if limit <= 0 || limit > len(results) {
+		limit = len(results)
+	}
+	lambda = math.Max(0, math.Min(1, lambda))
+
+	remaining := append([]*storage.SearchResult(nil), results...)
+	selected := make([]*storage.SearchResult, 0, limit)
+	// maxSimilarity[i] is the highest similarity of remaining[i] to any selected result
+	maxSimilarity := make([]float64, len(remaining))
+
+	for len(selected) < limit && len(remaining) > 0 {
+		best := 0
+		bestScore := math.Inf(-1)
+		for i, result := range remaining {
+			score := lambda*result.RelevanceScore - (1-lambda)*maxSimilarity[i]
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		picked := remaining[best]
+		selected = append(selected, picked)
+		remaining = append(remaining[:best], remaining[best+1:]...)
+		maxSimilarity = append(maxSimilarity[:best], maxSimilarity[best+1:]...)
+
+		pickedEmbedding, ok := embeddings[picked.Bookmark.ID]
+		if !ok {
+			continue
+		}
+		for i, result := range remaining {
+			if embedding, ok := embeddings[result.Bookmark.ID]; ok {
+				maxSimilarity[i] = math.Max(maxSimilarity[i], cosineSimilarity(pickedEmbedding, embedding))
+			}
+		}
+	}
+
+	return selected
+}
+
+// cosineSimilarity returns the cosine of the angle between two vectors, or 0 if either is zero
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// diversify re-ranks results with MMR using their stored embeddings
+func (cp *ContentProcessor) diversify(results []*storage.SearchResult, lambda float64, limit int) ([]*storage.SearchResult, error) {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.Bookmark.ID
+	}
+
+	embeddings, err := cp.storage.BookmarkEmbeddings(ids)
+	if err != nil {
+		return nil, err
+	}
+	return RerankMMR(results, embeddings, lambda, limit), nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"bookmark-chat/internal/storage"
+)
+
+func mmrResult(id string, score float64) *storage.SearchResult {
+	return &storage.SearchResult{Bookmark: &storage.Bookmark{ID: id}, RelevanceScore: score}
+}
+
+func TestRerankMMR(t *testing.T) {
+	// Two near-duplicate chunks of one page outrank a distinct source
+	results := []*storage.SearchResult{
+		mmrResult("page-a", 0.95),
+		mmrResult("page-a-copy", 0.93),
+		mmrResult("other", 0.80),
+		mmrResult("no-embedding", 0.10),
+	}
+	embeddings := map[string][]float32{
+		"page-a":      {1, 0, 0},
+		"page-a-copy": {0.99, 0.05, 0},
+		"other":       {0, 1, 0},
+	}
+
+	diversified := RerankMMR(results, embeddings, 0.5, 3)
+	ids := []string{}
+	for _, result := range diversified {
+		ids = append(ids, result.Bookmark.ID)
+	}
+	if len(ids) != 3 || ids[0] != "page-a" || ids[1] != "other" {
+		t.Errorf("Expected the distinct source right after the best match, got %v", ids)
+	}
+
+	relevanceOnly := RerankMMR(results, embeddings, 1, 0)
+	for i, result := range relevanceOnly {
+		if result != results[i] {
+			t.Fatalf("Expected lambda 1 to keep the relevance order, got %s at %d", result.Bookmark.ID, i)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"bookmark-chat/internal/storage"
+)
+
+// ErrInvalidSearchQuery is returned when an inline search operator cannot be parsed
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// ParsedQuery is a search query with its inline operators moved into structured filters
+type ParsedQuery struct {
+	// Text is the free text, including the words of quoted phrases, sent to full-text search
+	// and the embedder
+	Text    string
+	Options storage.SearchOptions
+}
+
+// queryDateLayouts are the accepted before:/after: formats, most specific first
+var queryDateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+// queryToken is one whitespace-separated unit of a search query
+type queryToken struct {
+	negated  bool
+	operator string // Lowercased operator name, empty for plain words and phrases
+	value    string
+	quoted   bool
+}
+
+// ParseSearchQuery extracts inline operators from a search query and adds them to base:
+//
+//	site:github.com   domain:github.com  results from the host and its subdomains
+//	tag:golang                           results carrying the tag (repeatable)
+//	folder:dev/tools                     results in the folder or its subfolders
+//	after:2024-01     before:2024        created on or after / before a date (YYYY, YYYY-MM or YYYY-MM-DD)
+//	"connection pool"                    results containing the phrase
+//	-deprecated       -"old api"         results not containing the word or phrase
+//
+// Operator values may be quoted to include spaces. Unknown operators are treated as free text.
+func ParseSearchQuery(raw string, base storage.SearchOptions) (*ParsedQuery, error) {
+	parsed := &ParsedQuery{Options: base}
+	opts := &parsed.Options
+	var text []string
+
+	for _, token := range tokenizeQuery(raw) {
+		if token.operator == "" {
+			switch {
+			case token.negated:
+				opts.ExcludedTerms = append(opts.ExcludedTerms, token.value)
+			case token.quoted:
+				opts.Phrases = append(opts.Phrases, token.value)
+				text = append(text, token.value)
+			default:
+				text = append(text, token.value)
+			}
+			continue
+		}
+
+		if token.negated {
+			return nil, fmt.Errorf("%w: %s: cannot be negated", ErrInvalidSearchQuery, token.operator)
+		}
+		if token.value == "" {
+			return nil, fmt.Errorf("%w: %s: needs a value", ErrInvalidSearchQuery, token.operator)
+		}
+
+		switch token.operator {
+		case "site", "domain":
+			opts.Domain = token.value
+		case "tag":
+			opts.Tags = append(opts.Tags, token.value)
+		case "folder":
+			opts.FolderPath = token.value
+		case "after", "before":
+			date, err := parseQueryDate(token.value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s:%s is not a date (use YYYY, YYYY-MM or YYYY-MM-DD)", ErrInvalidSearchQuery, token.operator, token.value)
+			}
+			if token.operator == "after" {
+				opts.CreatedAfter = date
+			} else {
+				opts.CreatedBefore = date
+			}
+		}
+	}
+
+	parsed.Text = strings.Join(text, " ")
+	return parsed, nil
+}
+
+// isQueryOperator reports whether name is a supported inline operator
+func isQueryOperator(name string) bool {
+	switch name {
+	case "site", "domain", "tag", "folder", "after", "before":
+		return true
+	}
+	return false
+}
+
+// tokenizeQuery splits a query on whitespace, keeping quoted phrases and quoted operator values together
+func tokenizeQuery(raw string) []queryToken {
+	var tokens []queryToken
+	runes := []rune(raw)
+
+	// readQuoted reads from just after an opening quote to the closing quote or the end of the query
+	readQuoted := func(i int) (string, int) {
+		start := i
+		for i < len(runes) && runes[i] != '"' {
+			i++
+		}
+		value := string(runes[start:i])
+		if i < len(runes) {
+			i++ // Closing quote
+		}
+		return strings.TrimSpace(value), i
+	}
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		token := queryToken{}
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			token.negated = true
+			i++
+		}
+
+		if runes[i] == '"' {
+			token.value, i = readQuoted(i + 1)
+			token.quoted = true
+			if token.value != "" {
+				tokens = append(tokens, token)
+			}
+			continue
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			i++
+		}
+		word := string(runes[start:i])
+
+		token.value = word
+		if name, value, found := strings.Cut(word, ":"); found && isQueryOperator(strings.ToLower(name)) {
+			token.operator = strings.ToLower(name)
+			token.value = value
+			if strings.HasPrefix(value, `"`) {
+				// A quoted value may contain spaces, so read on from its opening quote
+				token.value, i = readQuoted(start + len([]rune(name)) + 2)
+			}
+		}
+
+		if token.operator != "" || token.value != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+// parseQueryDate parses a before:/after: value as the start of the day, month or year in UTC
+func parseQueryDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range queryDateLayouts {
+		var date time.Time
+		if date, err = time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"bookmark-chat/internal/storage"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	parsed, err := ParseSearchQuery(`site:github.com tag:golang "connection pool" -deprecated after:2024-01 retries`, storage.SearchOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("ParseSearchQuery failed: %v", err)
+	}
+
+	if parsed.Text != "connection pool retries" {
+		t.Errorf("Expected free text %q, got %q", "connection pool retries", parsed.Text)
+	}
+
+	expected := storage.SearchOptions{
+		Domain:        "github.com",
+		Tags:          []string{"golang"},
+		Phrases:       []string{"connection pool"},
+		ExcludedTerms: []string{"deprecated"},
+		CreatedAfter:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Limit:         10,
+	}
+	if !reflect.DeepEqual(parsed.Options, expected) {
+		t.Errorf("Expected options %+v, got %+v", expected, parsed.Options)
+	}
+}
+
+func TestParseSearchQuery_QuotedValuesAndFreeText(t *testing.T) {
+	parsed, err := ParseSearchQuery(`folder:"Dev Tools/CLI" TAG:go tag:cli -"old api" c++ http://example.com before:2023 "unterminated phrase`, storage.SearchOptions{Tags: []string{"base"}})
+	if err != nil {
+		t.Fatalf("ParseSearchQuery failed: %v", err)
+	}
+
+	if parsed.Options.FolderPath != "Dev Tools/CLI" {
+		t.Errorf("Expected quoted folder value, got %q", parsed.Options.FolderPath)
+	}
+	if !reflect.DeepEqual(parsed.Options.Tags, []string{"base", "go", "cli"}) {
+		t.Errorf("Expected tags to extend the base filters, got %v", parsed.Options.Tags)
+	}
+	if !reflect.DeepEqual(parsed.Options.ExcludedTerms, []string{"old api"}) {
+		t.Errorf("Expected excluded phrase, got %v", parsed.Options.ExcludedTerms)
+	}
+	if !reflect.DeepEqual(parsed.Options.Phrases, []string{"unterminated phrase"}) {
+		t.Errorf("Expected unterminated phrase to run to the end, got %v", parsed.Options.Phrases)
+	}
+	if !parsed.Options.CreatedBefore.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected before:2023 to be the start of 2023, got %v", parsed.Options.CreatedBefore)
+	}
+	if parsed.Text != "c++ http://example.com unterminated phrase" {
+		t.Errorf("Expected unknown operators to stay in the free text, got %q", parsed.Text)
+	}
+}
+
+func TestParseSearchQuery_Errors(t *testing.T) {
+	for _, query := range []string{"after:yesterday", "-site:example.com", "tag:"} {
+		if _, err := ParseSearchQuery(query, storage.SearchOptions{}); !errors.Is(err, ErrInvalidSearchQuery) {
+			t.Errorf("Expected ErrInvalidSearchQuery for %q, got %v", query, err)
+		}
+	}
+}
@@ -3,6 +3,14 @@ package services
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
+)
+
+// Default per-host pacing of scrape requests
+const (
+	defaultHostRateLimit   = 2.0 // Requests per second
+	defaultHostConcurrency = 2   // Requests in flight
 )
 
 type FirecrawlScraper struct {
@@ -31,7 +39,8 @@ func (f *FirecrawlScraper) SetRateLimit(requestsPerSecond float64) {
 type ScraperConfig struct {
 	Type            ScraperType `json:"type"`
 	FirecrawlAPIKey string      `json:"firecrawl_api_key,omitempty"`
-	RateLimitRPS    float64     `json:"rate_limit_rps"`
+	RateLimitRPS    float64     `json:"rate_limit_rps"`   // Requests per second to each host
+	HostConcurrency int         `json:"host_concurrency"` // Requests in flight to each host
 }
 
 func NewScraper(config ScraperConfig) (Scraper, error) {
@@ -41,6 +50,9 @@ func NewScraper(config ScraperConfig) (Scraper, error) {
 		if config.RateLimitRPS > 0 {
 			scraper.SetRateLimit(config.RateLimitRPS)
 		}
+		if config.HostConcurrency > 0 {
+			scraper.SetHostConcurrency(config.HostConcurrency)
+		}
 		return scraper, nil
 	case ScraperTypeFirecrawl:
 		if config.FirecrawlAPIKey == "" {
@@ -56,9 +68,24 @@ func NewScraper(config ScraperConfig) (Scraper, error) {
 	}
 }
 
+// DefaultScraperConfig returns the default scraper configuration, reading SCRAPE_HOST_RPS and
+// SCRAPE_HOST_CONCURRENCY from the environment
 func DefaultScraperConfig() ScraperConfig {
-	return ScraperConfig{
-		Type:         ScraperTypeHTML,
-		RateLimitRPS: 2.0,
+	config := ScraperConfig{
+		Type:            ScraperTypeHTML,
+		RateLimitRPS:    defaultHostRateLimit,
+		HostConcurrency: scrapeHostConcurrency(),
+	}
+	if rps, err := strconv.ParseFloat(os.Getenv("SCRAPE_HOST_RPS"), 64); err == nil && rps > 0 {
+		config.RateLimitRPS = rps
+	}
+	return config
+}
+
+// scrapeHostConcurrency reads SCRAPE_HOST_CONCURRENCY, the cap on concurrent scrapes of one host
+func scrapeHostConcurrency() int {
+	if concurrency, err := strconv.Atoi(os.Getenv("SCRAPE_HOST_CONCURRENCY")); err == nil && concurrency > 0 {
+		return concurrency
 	}
+	return defaultHostConcurrency
 }
@@ -2,9 +2,9 @@ package storage
 
 import (
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"time"
+
+	"github.com/google/uuid"
 )
 
 // BatchOperations provides batch processing capabilities for efficiency
@@ -28,14 +28,14 @@ func (bo *BatchOperations) BatchAddBookmarks(bookmarks []struct {
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`INSERT INTO bookmarks (url, title) VALUES (?, ?)`)
+	stmt, err := tx.Prepare(`INSERT INTO bookmarks (id, url, title, description, tags, domain) VALUES (?, ?, ?, '', '[]', ?)`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
 	for _, bookmark := range bookmarks {
-		_, err := stmt.Exec(bookmark.URL, bookmark.Title)
+		_, err := stmt.Exec(uuid.New().String(), bookmark.URL, bookmark.Title, bookmarkDomain(bookmark.URL))
 		if err != nil {
 			return fmt.Errorf("failed to insert bookmark %s: %w", bookmark.URL, err)
 		}
@@ -62,12 +62,12 @@ func (bo *BatchOperations) BatchStoreEmbeddings(embeddings []struct {
 	defer stmt.Close()
 
 	for _, emb := range embeddings {
-		embeddingJSON, err := json.Marshal(emb.Embedding)
+		embeddingJSON, err := vectorJSON(emb.Embedding)
 		if err != nil {
-			return fmt.Errorf("failed to marshal embedding for content %d: %w", emb.ContentID, err)
+			return fmt.Errorf("invalid embedding for content %d: %w", emb.ContentID, err)
 		}
 
-		_, err = stmt.Exec(emb.ContentID, string(embeddingJSON))
+		_, err = stmt.Exec(emb.ContentID, embeddingJSON)
 		if err != nil {
 			return fmt.Errorf("failed to insert embedding for content %d: %w", emb.ContentID, err)
 		}
@@ -122,6 +122,7 @@ func (s *Storage) GetStats() (map[string]int, error) {
 		"failed_bookmarks":       "SELECT COUNT(*) FROM bookmarks WHERE status = 'failed'",
 		"total_content_entries":  "SELECT COUNT(*) FROM content",
 		"total_embeddings":       "SELECT COUNT(*) FROM embeddings",
+		"total_conversations":    "SELECT COUNT(*) FROM conversations",
 		"bookmarks_with_content": "SELECT COUNT(DISTINCT bookmark_id) FROM content WHERE clean_text IS NOT NULL",
 		"bookmarks_with_embeddings": `
 			SELECT COUNT(DISTINCT c.bookmark_id) 
@@ -145,7 +146,7 @@ func (s *Storage) GetStats() (map[string]int, error) {
 // SearchBookmarksWithFilters provides advanced search with filtering options
 func (s *Storage) SearchBookmarksWithFilters(opts SearchOptions) ([]*SearchResult, error) {
 	baseQuery := `
-		SELECT b.id, b.url, b.title, b.status, b.imported_at, b.created_at, b.updated_at,
+		SELECT b.id, b.url, COALESCE(b.title, ''), b.status, b.imported_at, b.created_at, b.updated_at,
 		       COALESCE(b.folder_path, ''), COALESCE(b.description, ''),
 		       c.id, c.bookmark_id, COALESCE(c.raw_content, ''), COALESCE(c.clean_text, ''),
 		       c.scraped_at, c.content_type
@@ -154,38 +155,14 @@ func (s *Storage) SearchBookmarksWithFilters(opts SearchOptions) ([]*SearchResul
 		WHERE 1=1
 	`
 
-	args := []interface{}{}
-	argIndex := 1
-
 	// Add filters
-	if opts.Status != "" {
-		baseQuery += fmt.Sprintf(" AND b.status = ?%d", argIndex)
-		args = append(args, opts.Status)
-		argIndex++
-	}
-
-	if opts.FolderPath != "" {
-		baseQuery += fmt.Sprintf(" AND b.folder_path LIKE ?%d", argIndex)
-		args = append(args, "%"+opts.FolderPath+"%")
-		argIndex++
-	}
-
-	if !opts.CreatedAfter.IsZero() {
-		baseQuery += fmt.Sprintf(" AND b.created_at >= ?%d", argIndex)
-		args = append(args, opts.CreatedAfter)
-		argIndex++
-	}
-
-	if !opts.CreatedBefore.IsZero() {
-		baseQuery += fmt.Sprintf(" AND b.created_at <= ?%d", argIndex)
-		args = append(args, opts.CreatedBefore)
-		argIndex++
-	}
+	filterClause, args := opts.filterClause()
+	baseQuery += filterClause
 
 	// Add ordering and limits
 	baseQuery += " ORDER BY b.created_at DESC"
 	if opts.Limit > 0 {
-		baseQuery += fmt.Sprintf(" LIMIT ?%d", argIndex)
+		baseQuery += " LIMIT ?"
 		args = append(args, opts.Limit)
 	}
 
@@ -200,7 +177,7 @@ func (s *Storage) SearchBookmarksWithFilters(opts SearchOptions) ([]*SearchResul
 		bookmark := &Bookmark{}
 		content := &Content{}
 		var contentID sql.NullInt64
-		var bookmarkID sql.NullInt64
+		var bookmarkID sql.NullString
 		var rawContent, cleanText sql.NullString
 		var scrapedAt sql.NullTime
 		var contentType sql.NullString
@@ -241,15 +218,6 @@ func (s *Storage) SearchBookmarksWithFilters(opts SearchOptions) ([]*SearchResul
 	return results, nil
 }
 
-// SearchOptions defines filtering options for bookmark searches
-type SearchOptions struct {
-	Status        string
-	FolderPath    string
-	CreatedAfter  time.Time
-	CreatedBefore time.Time
-	Limit         int
-}
-
 // DeleteBookmark removes a bookmark and all associated data
 func (s *Storage) DeleteBookmark(bookmarkID string) error {
 	tx, err := s.db.Begin()
@@ -258,25 +226,38 @@ func (s *Storage) DeleteBookmark(bookmarkID string) error {
 	}
 	defer tx.Rollback()
 
-	// Delete from FTS table first
-	_, err = tx.Exec("DELETE FROM bookmarks_fts WHERE rowid = ?", bookmarkID)
-	if err != nil {
-		return fmt.Errorf("failed to delete from FTS table: %w", err)
+	// Foreign key cascades are not enabled on the connection, so dependent rows are removed explicitly.
+	// bookmarks_fts and content_fts are cleaned up by triggers.
+	if err := deleteBookmarkContent(tx, bookmarkID); err != nil {
+		return err
+	}
+
+	if err := releaseBookmarkTags(tx, bookmarkID); err != nil {
+		return err
 	}
 
-	// Delete embeddings (cascade will handle this, but explicit is better)
 	_, err = tx.Exec(`
-		DELETE FROM embeddings 
-		WHERE content_id IN (SELECT id FROM content WHERE bookmark_id = ?)
+		UPDATE categories SET usage_count = usage_count - 1
+		WHERE usage_count > 0 AND id IN (SELECT category_id FROM bookmark_categories WHERE bookmark_id = ?)
 	`, bookmarkID)
 	if err != nil {
-		return fmt.Errorf("failed to delete embeddings: %w", err)
+		return fmt.Errorf("failed to update category usage: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM bookmark_categories WHERE bookmark_id = ?", bookmarkID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bookmark categories: %w", err)
+	}
+
+	// Workers holding a lease on one of these jobs lose it and drop their result
+	_, err = tx.Exec("DELETE FROM jobs WHERE bookmark_id = ?", bookmarkID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bookmark jobs: %w", err)
 	}
 
-	// Delete content
-	_, err = tx.Exec("DELETE FROM content WHERE bookmark_id = ?", bookmarkID)
+	_, err = tx.Exec("DELETE FROM scrape_run_bookmarks WHERE bookmark_id = ?", bookmarkID)
 	if err != nil {
-		return fmt.Errorf("failed to delete content: %w", err)
+		return fmt.Errorf("failed to delete bookmark scrape status: %w", err)
 	}
 
 	// Delete bookmark
@@ -291,7 +272,7 @@ func (s *Storage) DeleteBookmark(bookmarkID string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("bookmark with ID %s not found", bookmarkID)
+		return fmt.Errorf("%w: %s", ErrBookmarkNotFound, bookmarkID)
 	}
 
 	return tx.Commit()
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// addDatedBookmarks creates one bookmark per title, with created_at increasing in the given order
+// and updated_at decreasing, and returns their IDs in that order
+func addDatedBookmarks(t *testing.T, store *Storage, titles ...string) []string {
+	t.Helper()
+	var ids []string
+	for i, title := range titles {
+		bookmark := addBookmark(t, store, fmt.Sprintf("https://example.com/%d", i), title)
+		_, err := store.db.Exec("UPDATE bookmarks SET created_at = ?, updated_at = ? WHERE id = ?",
+			fmt.Sprintf("2024-01-%02d 00:00:00", i+1), fmt.Sprintf("2024-02-%02d 00:00:00", len(titles)-i), bookmark.ID)
+		if err != nil {
+			t.Fatalf("Failed to date bookmark: %v", err)
+		}
+		ids = append(ids, bookmark.ID)
+	}
+	return ids
+}
+
+// pageIDs returns the IDs of a page of bookmarks in order
+func pageIDs(page *BookmarkPage) []string {
+	var ids []string
+	for _, bookmark := range page.Bookmarks {
+		ids = append(ids, bookmark.ID)
+	}
+	return ids
+}
+
+func TestQueryBookmarksPagination(t *testing.T) {
+	store := newTestStorage(t)
+	ids := addDatedBookmarks(t, store, "a", "b", "c", "d", "e")
+
+	tests := []struct {
+		page, limit int
+		want        []string
+	}{
+		{1, 2, []string{ids[0], ids[1]}},
+		{2, 2, []string{ids[2], ids[3]}},
+		{3, 2, []string{ids[4]}}, // Last, partial page
+		{4, 2, nil},              // Past the end
+		{1, 5, ids},
+		{1, 10, ids},
+		{0, 2, []string{ids[0], ids[1]}}, // Pages start at 1
+	}
+	for _, tt := range tests {
+		page, err := store.QueryBookmarks(BookmarkQuery{Page: tt.page, Limit: tt.limit, Sort: "created_at:asc"})
+		if err != nil {
+			t.Fatalf("Failed to query page %d of %d: %v", tt.page, tt.limit, err)
+		}
+		if fmt.Sprint(pageIDs(page)) != fmt.Sprint(tt.want) {
+			t.Errorf("Page %d of %d: expected %v, got %v", tt.page, tt.limit, tt.want, pageIDs(page))
+		}
+		if page.TotalItems != 5 {
+			t.Errorf("Page %d of %d: expected 5 total items, got %d", tt.page, tt.limit, page.TotalItems)
+		}
+	}
+
+	// Without a limit a page holds 20 bookmarks
+	page, err := store.QueryBookmarks(BookmarkQuery{})
+	if err != nil {
+		t.Fatalf("Failed to query bookmarks: %v", err)
+	}
+	if len(page.Bookmarks) != 5 {
+		t.Errorf("Expected the default page to hold all 5 bookmarks, got %d", len(page.Bookmarks))
+	}
+}
+
+func TestQueryBookmarksSort(t *testing.T) {
+	store := newTestStorage(t)
+	ids := addDatedBookmarks(t, store, "banana", "Apple", "cherry")
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"created_at:asc", []string{ids[0], ids[1], ids[2]}},
+		{"created_at:desc", []string{ids[2], ids[1], ids[0]}},
+		{"updated_at:asc", []string{ids[2], ids[1], ids[0]}},
+		{"updated_at:desc", []string{ids[0], ids[1], ids[2]}},
+		{"title:asc", []string{ids[1], ids[0], ids[2]}}, // Case-insensitive
+		{"title:desc", []string{ids[2], ids[0], ids[1]}},
+		{"", []string{ids[2], ids[1], ids[0]}}, // Newest first by default
+	}
+	for _, tt := range tests {
+		page, err := store.QueryBookmarks(BookmarkQuery{Sort: tt.sort})
+		if err != nil {
+			t.Fatalf("Failed to sort by %q: %v", tt.sort, err)
+		}
+		if fmt.Sprint(pageIDs(page)) != fmt.Sprint(tt.want) {
+			t.Errorf("Sort %q: expected %v, got %v", tt.sort, tt.want, pageIDs(page))
+		}
+	}
+
+	for _, sort := range []string{"url:asc", "title:sideways", "title; DROP TABLE bookmarks"} {
+		if _, err := store.QueryBookmarks(BookmarkQuery{Sort: sort}); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("Expected ErrInvalidSort for %q, got %v", sort, err)
+		}
+	}
+}
+
+func TestQueryBookmarksFilter(t *testing.T) {
+	store := newTestStorage(t)
+	percent := addBookmark(t, store, "https://example.com/sale", "100% off")
+	underscore := addBookmark(t, store, "https://example.com/snake_case", "Naming")
+	addBookmark(t, store, "https://example.com/100-off", "1000 offers")
+	addBookmark(t, store, "https://example.com/snakeXcase", "Snakes")
+	for i := 0; i < 3; i++ {
+		addBookmark(t, store, fmt.Sprintf("https://golang.example.com/%d", i), fmt.Sprintf("Go %d", i))
+	}
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"100%", []string{percent.ID}},          // % matches literally, not "1000 offers"
+		{"snake_case", []string{underscore.ID}}, // _ matches literally in the URL, not "snakeXcase"
+		{"NAMING", []string{underscore.ID}},     // Case-insensitive
+		{"nothing matches", nil},
+	}
+	for _, tt := range tests {
+		page, err := store.QueryBookmarks(BookmarkQuery{Filter: tt.filter})
+		if err != nil {
+			t.Fatalf("Failed to filter by %q: %v", tt.filter, err)
+		}
+		if fmt.Sprint(pageIDs(page)) != fmt.Sprint(tt.want) || page.TotalItems != len(tt.want) {
+			t.Errorf("Filter %q: expected %v, got %v with %d total", tt.filter, tt.want, pageIDs(page), page.TotalItems)
+		}
+	}
+
+	// The total counts every match, not just the page
+	page, err := store.QueryBookmarks(BookmarkQuery{Filter: "golang", Limit: 2})
+	if err != nil {
+		t.Fatalf("Failed to filter bookmarks: %v", err)
+	}
+	if len(page.Bookmarks) != 2 || page.TotalItems != 3 {
+		t.Errorf("Expected a page of 2 out of 3 matches, got %d out of %d", len(page.Bookmarks), page.TotalItems)
+	}
+}
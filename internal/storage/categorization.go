@@ -5,8 +5,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"os"
-	"strings"
 	"time"
 )
 
@@ -38,56 +36,6 @@ type BookmarkCategorization struct {
 	CategorizationDate  time.Time           `json:"categorization_date"`
 }
 
-// applyCategorization runs the categorization migration
-func (s *Storage) applyCategorization() error {
-	// Check if migration was already applied by looking for categories table
-	var count int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='categories'").Scan(&count)
-	if err != nil {
-		return fmt.Errorf("failed to check for categories table: %w", err)
-	}
-
-	if count > 0 {
-		// Migration already applied, check for new columns in bookmarks table
-		var columnCount int
-		err = s.db.QueryRow(`
-			SELECT COUNT(*) FROM pragma_table_info('bookmarks') 
-			WHERE name IN ('categorization_date', 'categorization_confidence', 'categorization_status')
-		`).Scan(&columnCount)
-		
-		if err == nil && columnCount >= 3 {
-			return nil // All migration changes already applied
-		}
-	}
-
-	// Read and execute migration
-	migrationPath := "internal/storage/migrations/003_add_categorization.sql"
-	migrationSQL, err := os.ReadFile(migrationPath)
-	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
-	}
-
-	// Split SQL statements and execute them one by one
-	statements := strings.Split(string(migrationSQL), ";")
-	for _, statement := range statements {
-		statement = strings.TrimSpace(statement)
-		if statement == "" {
-			continue
-		}
-
-		_, err = s.db.Exec(statement)
-		if err != nil {
-			// Ignore errors for ALTER TABLE on existing columns
-			if strings.Contains(err.Error(), "duplicate column name") {
-				continue
-			}
-			return fmt.Errorf("failed to execute migration statement '%s': %w", statement, err)
-		}
-	}
-
-	return nil
-}
-
 // SaveCategorizationResult stores AI categorization suggestions
 func (s *Storage) SaveCategorizationResult(ctx context.Context, bookmarkID string, result CategorizationResult) error {
 	return s.retryWithBackoff(func() error {
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrConversationNotFound is returned when a conversation does not exist
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// Conversation represents a chat session
+type Conversation struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Message represents a single message within a conversation
+type Message struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	Role           string     `json:"role"` // user, assistant
+	Content        string     `json:"content"`
+	BookmarkRefs   []string   `json:"bookmark_refs,omitempty"`
+	Citations      []Citation `json:"citations,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Citation links an inline citation marker in an answer to the content chunk it was drawn from
+type Citation struct {
+	Marker     int    `json:"marker"`
+	BookmarkID string `json:"bookmark_id"`
+	ChunkIndex *int   `json:"chunk_index,omitempty"` // Unset when citing a bookmark without embeddings
+	Quote      string `json:"quote"`
+}
+
+// CreateConversation creates a new conversation with the given ID and title
+func (s *Storage) CreateConversation(ctx context.Context, id string, title string) (*Conversation, error) {
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	now := time.Now()
+	err := s.retryWithBackoff(func() error {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO conversations (id, title, created_at, updated_at)
+			VALUES (?, ?, ?, ?)
+		`, id, title, now, now)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conversation: %w", err)
+	}
+
+	return &Conversation{
+		ID:        id,
+		Title:     title,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// GetConversation retrieves a conversation by ID
+func (s *Storage) GetConversation(ctx context.Context, id string) (*Conversation, error) {
+	conversation := &Conversation{}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT c.id, COALESCE(c.title, ''), c.created_at, c.updated_at,
+		       (SELECT COUNT(*) FROM messages m WHERE m.conversation_id = c.id)
+		FROM conversations c
+		WHERE c.id = ?
+	`, id).Scan(&conversation.ID, &conversation.Title, &conversation.CreatedAt, &conversation.UpdatedAt, &conversation.MessageCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// ListConversations returns all conversations, most recently updated first
+func (s *Storage) ListConversations(ctx context.Context) ([]*Conversation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.id, COALESCE(c.title, ''), c.created_at, c.updated_at,
+		       (SELECT COUNT(*) FROM messages m WHERE m.conversation_id = c.id)
+		FROM conversations c
+		ORDER BY c.updated_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := []*Conversation{}
+	for rows.Next() {
+		conversation := &Conversation{}
+		if err := rows.Scan(&conversation.ID, &conversation.Title, &conversation.CreatedAt, &conversation.UpdatedAt, &conversation.MessageCount); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		conversations = append(conversations, conversation)
+	}
+
+	return conversations, rows.Err()
+}
+
+// DeleteConversation removes a conversation and all of its messages
+func (s *Storage) DeleteConversation(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM messages WHERE conversation_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete messages: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM conversations WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrConversationNotFound
+	}
+
+	return tx.Commit()
+}
+
+// CountConversations returns the number of stored conversations
+func (s *Storage) CountConversations(ctx context.Context) (int, error) {
+	var count int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM conversations").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count conversations: %w", err)
+	}
+	return count, nil
+}
+
+// AddMessage appends a message to a conversation and bumps the conversation's updated_at
+func (s *Storage) AddMessage(ctx context.Context, message *Message) error {
+	return s.AddMessages(ctx, message)
+}
+
+// AddMessages appends messages to their conversation in one transaction, so either all of them are
+// stored or none are, and bumps the conversation's updated_at
+func (s *Storage) AddMessages(ctx context.Context, messages ...*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	refsJSON := make([]string, len(messages))
+	citationsJSON := make([]string, len(messages))
+	for i, message := range messages {
+		if message.ID == "" {
+			message.ID = uuid.New().String()
+		}
+		if message.CreatedAt.IsZero() {
+			message.CreatedAt = time.Now()
+		}
+
+		refs, err := json.Marshal(message.BookmarkRefs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal bookmark refs: %w", err)
+		}
+		citations, err := json.Marshal(message.Citations)
+		if err != nil {
+			return fmt.Errorf("failed to marshal citations: %w", err)
+		}
+		refsJSON[i], citationsJSON[i] = string(refs), string(citations)
+	}
+
+	return s.retryWithBackoff(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		for i, message := range messages {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO messages (id, conversation_id, role, content, bookmark_refs, citations, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+			`, message.ID, message.ConversationID, message.Role, message.Content, refsJSON[i], citationsJSON[i], message.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to insert message: %w", err)
+			}
+
+			_, err = tx.ExecContext(ctx, "UPDATE conversations SET updated_at = ? WHERE id = ?", message.CreatedAt, message.ConversationID)
+			if err != nil {
+				return fmt.Errorf("failed to update conversation: %w", err)
+			}
+		}
+
+		return tx.Commit()
+	})
+}
+
+// GetMessages returns the messages of a conversation in chronological order.
+// When limit is greater than zero only the most recent messages are returned.
+func (s *Storage) GetMessages(ctx context.Context, conversationID string, limit int) ([]*Message, error) {
+	query := `
+		SELECT id, conversation_id, role, content, COALESCE(bookmark_refs, ''), COALESCE(citations, ''), created_at
+		FROM messages
+		WHERE conversation_id = ?
+		ORDER BY created_at DESC, rowid DESC
+	`
+	args := []interface{}{conversationID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*Message{}
+	for rows.Next() {
+		message := &Message{}
+		var refsJSON, citationsJSON string
+		if err := rows.Scan(&message.ID, &message.ConversationID, &message.Role, &message.Content, &refsJSON, &citationsJSON, &message.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		if refsJSON != "" && refsJSON != "null" {
+			if err := json.Unmarshal([]byte(refsJSON), &message.BookmarkRefs); err != nil {
+				return nil, fmt.Errorf("failed to parse bookmark refs: %w", err)
+			}
+		}
+		if citationsJSON != "" && citationsJSON != "null" {
+			if err := json.Unmarshal([]byte(citationsJSON), &message.Citations); err != nil {
+				return nil, fmt.Errorf("failed to parse citations: %w", err)
+			}
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	// Rows were fetched newest first so LIMIT keeps the latest ones; restore chronological order
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAddMessagesIsAtomic(t *testing.T) {
+	store := newTestStorage(t)
+	ctx := context.Background()
+
+	conversation, err := store.CreateConversation(ctx, "", "Go questions")
+	if err != nil {
+		t.Fatalf("Failed to create conversation: %v", err)
+	}
+
+	// The answer violates the role constraint, so the question must not be stored either
+	err = store.AddMessages(ctx,
+		&Message{ConversationID: conversation.ID, Role: "user", Content: "What is a goroutine?"},
+		&Message{ConversationID: conversation.ID, Role: "bot", Content: "A lightweight thread."},
+	)
+	if err == nil {
+		t.Fatal("Expected an error for an invalid role")
+	}
+	if messages, _ := store.GetMessages(ctx, conversation.ID, 0); len(messages) != 0 {
+		t.Errorf("Expected no messages after a failed exchange, got %d", len(messages))
+	}
+
+	err = store.AddMessages(ctx,
+		&Message{ConversationID: conversation.ID, Role: "user", Content: "What is a goroutine?"},
+		&Message{ConversationID: conversation.ID, Role: "assistant", Content: "A lightweight thread [1].",
+			Citations: []Citation{{Marker: 1, BookmarkID: "b1", Quote: "goroutines"}}},
+	)
+	if err != nil {
+		t.Fatalf("Failed to add messages: %v", err)
+	}
+
+	messages, err := store.GetMessages(ctx, conversation.ID, 0)
+	if err != nil {
+		t.Fatalf("Failed to get messages: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Role != "user" || messages[1].Role != "assistant" {
+		t.Fatalf("Expected the question then the answer, got %d messages", len(messages))
+	}
+	if len(messages[1].Citations) != 1 || messages[1].Citations[0].ChunkIndex != nil {
+		t.Errorf("Expected the citation without a chunk index to round-trip, got %+v", messages[1].Citations)
+	}
+
+	updated, err := store.GetConversation(ctx, conversation.ID)
+	if err != nil {
+		t.Fatalf("Failed to get conversation: %v", err)
+	}
+	if updated.MessageCount != 2 || updated.UpdatedAt.Before(conversation.UpdatedAt) {
+		t.Errorf("Expected the conversation to count both messages and be bumped, got %+v", updated)
+	}
+}
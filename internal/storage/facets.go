@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxFacetValues is how many values each facet returns, most frequent first
+const maxFacetValues = 20
+
+// FacetCount is the number of matching bookmarks that share a facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets groups the bookmarks matching a search by common attributes
+type SearchFacets struct {
+	FolderPaths []FacetCount `json:"folder_paths"`
+	Domains     []FacetCount `json:"domains"`
+	Categories  []FacetCount `json:"categories"` // Primary category only
+	Tags        []FacetCount `json:"tags"`       // Lowercased
+	Statuses    []FacetCount `json:"statuses"`
+	Years       []FacetCount `json:"years"` // Year the bookmark was created, newest first
+}
+
+// facetQueries aggregate the matched CTE, which holds the ids of the bookmarks to count
+var facetQueries = []struct {
+	name   string
+	target func(*SearchFacets) *[]FacetCount
+	query  string
+}{
+	{"folder", func(f *SearchFacets) *[]FacetCount { return &f.FolderPaths }, `
+		SELECT b.folder_path, COUNT(*) FROM matched m JOIN bookmarks b ON b.id = m.id
+		WHERE COALESCE(b.folder_path, '') != ''
+		GROUP BY b.folder_path ORDER BY COUNT(*) DESC, b.folder_path LIMIT ?`},
+	{"domain", func(f *SearchFacets) *[]FacetCount { return &f.Domains }, `
+		SELECT b.domain, COUNT(*) FROM matched m JOIN bookmarks b ON b.id = m.id
+		WHERE COALESCE(b.domain, '') != ''
+		GROUP BY b.domain ORDER BY COUNT(*) DESC, b.domain LIMIT ?`},
+	{"category", func(f *SearchFacets) *[]FacetCount { return &f.Categories }, `
+		SELECT cat.name, COUNT(DISTINCT m.id) FROM matched m
+		JOIN bookmark_categories bc ON bc.bookmark_id = m.id AND bc.is_primary
+		JOIN categories cat ON cat.id = bc.category_id
+		GROUP BY cat.name ORDER BY COUNT(DISTINCT m.id) DESC, cat.name LIMIT ?`},
+	{"tag", func(f *SearchFacets) *[]FacetCount { return &f.Tags }, `
+		SELECT tag, COUNT(*) FROM (
+			SELECT m.id, lower(trim(j.value)) AS tag
+			FROM matched m JOIN bookmarks b ON b.id = m.id, json_each(COALESCE(b.tags, '[]')) j
+			UNION
+			SELECT m.id, lower(t.name) AS tag
+			FROM matched m JOIN bookmark_tags bt ON bt.bookmark_id = m.id JOIN tags t ON t.id = bt.tag_id
+		)
+		WHERE tag != ''
+		GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT ?`},
+	{"status", func(f *SearchFacets) *[]FacetCount { return &f.Statuses }, `
+		SELECT b.status, COUNT(*) FROM matched m JOIN bookmarks b ON b.id = m.id
+		GROUP BY b.status ORDER BY COUNT(*) DESC, b.status LIMIT ?`},
+	// created_at may hold a timestamp string or unix seconds depending on how it was written
+	{"year", func(f *SearchFacets) *[]FacetCount { return &f.Years }, `
+		SELECT year, COUNT(*) FROM (
+			SELECT CASE WHEN typeof(b.created_at) = 'integer' THEN strftime('%Y', b.created_at, 'unixepoch')
+			            ELSE substr(b.created_at, 1, 4) END AS year
+			FROM matched m JOIN bookmarks b ON b.id = m.id
+		)
+		WHERE year IS NOT NULL
+		GROUP BY year ORDER BY year DESC LIMIT ?`},
+}
+
+// SearchFacets counts facet values across the given bookmarks, the results of a search, so the
+// counts match what drilling into a value returns
+func (s *Storage) SearchFacets(bookmarkIDs []string) (*SearchFacets, error) {
+	if len(bookmarkIDs) == 0 {
+		return s.facets("WITH matched AS (SELECT id FROM bookmarks WHERE 0)", nil)
+	}
+
+	args := make([]interface{}, len(bookmarkIDs))
+	for i, id := range bookmarkIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(bookmarkIDs)), ",")
+	return s.facets("WITH matched AS (SELECT id FROM bookmarks WHERE id IN ("+placeholders+"))", args)
+}
+
+// FilterFacets counts facet values across every bookmark that passes the filters in opts.
+// opts.Limit is ignored.
+func (s *Storage) FilterFacets(opts SearchOptions) (*SearchFacets, error) {
+	filterClause, filterArgs := opts.filterClause()
+	return s.facets("WITH matched AS (SELECT b.id FROM bookmarks b WHERE 1=1"+filterClause+")", filterArgs)
+}
+
+// facets runs every facet query over the bookmarks selected by the matched CTE
+func (s *Storage) facets(matched string, args []interface{}) (*SearchFacets, error) {
+	facets := &SearchFacets{}
+	for _, facet := range facetQueries {
+		facetArgs := append(append([]interface{}{}, args...), maxFacetValues)
+		counts, err := s.facetCounts(matched+facet.query, facetArgs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s facet: %w", facet.name, err)
+		}
+		*facet.target(facets) = counts
+	}
+
+	return facets, nil
+}
+
+// facetCounts runs a facet query returning value and count columns
+func (s *Storage) facetCounts(query string, args []interface{}) ([]FacetCount, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []FacetCount{}
+	for rows.Next() {
+		var count FacetCount
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
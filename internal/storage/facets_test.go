@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+)
+
+// execSQL runs setup statements for a test
+func execSQL(t *testing.T, store *Storage, statements ...string) {
+	t.Helper()
+	for _, statement := range statements {
+		if _, err := store.db.Exec(statement); err != nil {
+			t.Fatalf("Failed to run %q: %v", statement, err)
+		}
+	}
+}
+
+// addFacetBookmarks creates bookmarks covering every facet and returns the IDs of three search
+// results and of a bookmark the search did not return
+func addFacetBookmarks(t *testing.T, store *Storage) (results []string, other string) {
+	t.Helper()
+	go1 := addBookmark(t, store, "https://www.Go.dev/doc", "Go docs")
+	go2 := addBookmark(t, store, "https://blog.go.dev/post", "Go blog")
+	misc := addBookmark(t, store, "https://example.com/", "Example")
+	unrelated := addBookmark(t, store, "https://unrelated.example.org/", "Unrelated")
+
+	execSQL(t, store,
+		// created_at may hold a timestamp string or unix seconds
+		fmt.Sprintf(`UPDATE bookmarks SET folder_path = 'Dev/Go', status = 'completed', tags = '["Go", " Web "]',
+			created_at = '2023-05-01 10:00:00' WHERE id = '%s'`, go1.ID),
+		fmt.Sprintf(`UPDATE bookmarks SET folder_path = 'Dev/Go', created_at = 1717200000 WHERE id = '%s'`, go2.ID),
+		fmt.Sprintf(`UPDATE bookmarks SET tags = '["go"]', created_at = '2024-02-01 00:00:00' WHERE id = '%s'`, misc.ID),
+		fmt.Sprintf(`UPDATE bookmarks SET folder_path = 'Other', tags = '["other"]' WHERE id = '%s'`, unrelated.ID),
+
+		// Tags can also live in the normalized tables; a tag in both places counts once
+		`INSERT INTO tags (name) VALUES ('go'), ('web'), ('Backend'), ('other')`,
+		fmt.Sprintf(`INSERT INTO bookmark_tags (bookmark_id, tag_id) SELECT '%s', id FROM tags WHERE name IN ('go', 'Backend')`, go1.ID),
+		fmt.Sprintf(`INSERT INTO bookmark_tags (bookmark_id, tag_id) SELECT '%s', id FROM tags WHERE name = 'web'`, go2.ID),
+
+		// Only primary categories count
+		`INSERT INTO categories (name) VALUES ('Programming'), ('Reading')`,
+		fmt.Sprintf(`INSERT INTO bookmark_categories (bookmark_id, category_id, is_primary)
+			SELECT '%s', id, name = 'Programming' FROM categories`, go1.ID),
+		fmt.Sprintf(`INSERT INTO bookmark_categories (bookmark_id, category_id, is_primary)
+			SELECT '%s', id, TRUE FROM categories WHERE name = 'Programming'`, misc.ID),
+		fmt.Sprintf(`INSERT INTO bookmark_categories (bookmark_id, category_id, is_primary)
+			SELECT '%s', id, TRUE FROM categories WHERE name = 'Reading'`, unrelated.ID),
+	)
+	return []string{go1.ID, go2.ID, misc.ID}, unrelated.ID
+}
+
+func TestSearchFacets(t *testing.T) {
+	store := newTestStorage(t)
+	results, _ := addFacetBookmarks(t, store)
+
+	facets, err := store.SearchFacets(results)
+	if err != nil {
+		t.Fatalf("Failed to count facets: %v", err)
+	}
+
+	tests := []struct {
+		facet string
+		got   []FacetCount
+		want  string
+	}{
+		{"folder", facets.FolderPaths, "[{Dev/Go 2}]"},
+		{"domain", facets.Domains, "[{blog.go.dev 1} {example.com 1} {go.dev 1}]"},
+		{"category", facets.Categories, "[{Programming 2}]"},
+		{"tag", facets.Tags, "[{go 2} {web 2} {backend 1}]"},
+		{"status", facets.Statuses, "[{pending 2} {completed 1}]"},
+		{"year", facets.Years, "[{2024 2} {2023 1}]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(tt.got); got != tt.want {
+			t.Errorf("Expected %s facet %s, got %s", tt.facet, tt.want, got)
+		}
+	}
+
+	// No results, no counts
+	empty, err := store.SearchFacets(nil)
+	if err != nil {
+		t.Fatalf("Failed to count facets: %v", err)
+	}
+	if len(empty.Domains) != 0 || len(empty.Tags) != 0 || len(empty.Years) != 0 {
+		t.Errorf("Expected no facet values without results, got %+v", empty)
+	}
+}
+
+func TestFilterFacets(t *testing.T) {
+	store := newTestStorage(t)
+	addFacetBookmarks(t, store)
+
+	facets, err := store.FilterFacets(SearchOptions{Domain: "go.dev"})
+	if err != nil {
+		t.Fatalf("Failed to count facets: %v", err)
+	}
+	if got := fmt.Sprint(facets.Domains); got != "[{blog.go.dev 1} {go.dev 1}]" {
+		t.Errorf("Expected only the filtered domains, got %s", got)
+	}
+
+	all, err := store.FilterFacets(SearchOptions{})
+	if err != nil {
+		t.Fatalf("Failed to count facets: %v", err)
+	}
+	if got := fmt.Sprint(all.FolderPaths); got != "[{Dev/Go 2} {Other 1}]" {
+		t.Errorf("Expected every bookmark counted without filters, got %s", got)
+	}
+}
@@ -0,0 +1,540 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job types
+const (
+	JobTypeScrape     = "scrape"
+	JobTypeEmbed      = "embed"
+	JobTypeCategorize = "categorize"
+	JobTypeLinkCheck  = "link_check"
+)
+
+// Job states
+const (
+	JobQueued    = "queued"
+	JobPaused    = "paused" // Held back until resumed
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobDead      = "dead" // Failed on its last allowed attempt
+	JobCancelled = "cancelled"
+)
+
+// defaultJobMaxAttempts is used when a job is enqueued without MaxAttempts
+const defaultJobMaxAttempts = 5
+
+var (
+	// ErrJobNotFound is returned when a job does not exist
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobLeaseLost is returned when a worker reports on a job it no longer holds the lease for
+	ErrJobLeaseLost = errors.New("job lease lost")
+	// ErrJobStateConflict is returned when a job cannot be cancelled or retried in its current state
+	ErrJobStateConflict = errors.New("job state conflict")
+)
+
+// Job is a unit of background work on a bookmark
+type Job struct {
+	ID             string     `json:"id"`
+	Type           string     `json:"type"`
+	BookmarkID     string     `json:"bookmark_id,omitempty"`
+	BatchID        string     `json:"batch_id,omitempty"`
+	State          string     `json:"state"`
+	Priority       int        `json:"priority"`
+	Attempts       int        `json:"attempts"`
+	MaxAttempts    int        `json:"max_attempts"`
+	RunAt          time.Time  `json:"run_at"`
+	LeaseOwner     string     `json:"lease_owner,omitempty"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	Result         string     `json:"result,omitempty"` // JSON written by the job handler
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+const jobColumns = `id, type, COALESCE(bookmark_id, ''), COALESCE(batch_id, ''), state, priority, attempts,
+	max_attempts, run_at, COALESCE(lease_owner, ''), lease_expires_at, COALESCE(last_error, ''),
+	COALESCE(result, ''), created_at, updated_at, finished_at`
+
+// activeJobStates are the states covered by the one-unfinished-job-per-bookmark index
+const activeJobStates = `('queued', 'paused', 'running')`
+
+// finishedJobStates are the states a job ends in
+const finishedJobStates = `('succeeded', 'dead', 'cancelled')`
+
+// hostJobTypes are the job types that send requests to the bookmark's host; claims spread them
+// across hosts
+const hostJobTypes = `('scrape', 'link_check')`
+
+// JobQuery selects one page of jobs
+type JobQuery struct {
+	Type  string // Only jobs of this type when set
+	State string // Only jobs in this state when set
+	Page  int    // 1-based page number
+	Limit int    // Page size
+}
+
+// JobPage is a page of jobs along with the total number of matches
+type JobPage struct {
+	Jobs       []*Job
+	TotalItems int
+}
+
+// EnqueueJobs adds jobs to the queue in one transaction. A bookmark already having an unfinished job
+// of the same type keeps that job instead, which is moved into the new batch, raised to the higher
+// priority and resumed if paused. Missing IDs, states, run times and attempt limits are filled in.
+func (s *Storage) EnqueueJobs(jobs []*Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO jobs (id, type, bookmark_id, batch_id, state, priority, max_attempts, run_at, created_at, updated_at)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (type, bookmark_id) WHERE state IN ` + activeJobStates + ` DO UPDATE SET
+			batch_id = COALESCE(excluded.batch_id, jobs.batch_id),
+			priority = MAX(jobs.priority, excluded.priority),
+			state = CASE WHEN jobs.state = 'paused' THEN 'queued' ELSE jobs.state END,
+			updated_at = excluded.updated_at`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare job insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, job := range jobs {
+		if job.ID == "" {
+			job.ID = uuid.New().String()
+		}
+		if job.State == "" {
+			job.State = JobQueued
+		}
+		if job.MaxAttempts <= 0 {
+			job.MaxAttempts = defaultJobMaxAttempts
+		}
+		if job.RunAt.IsZero() {
+			job.RunAt = now
+		}
+		job.CreatedAt, job.UpdatedAt = now, now
+
+		_, err := stmt.Exec(job.ID, job.Type, job.BookmarkID, job.BatchID, job.State, job.Priority,
+			job.MaxAttempts, job.RunAt.Unix(), now.Unix(), now.Unix())
+		if err != nil {
+			return fmt.Errorf("failed to enqueue %s job: %w", job.Type, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ClaimJob leases the next runnable job of one of the given types to owner: the highest priority
+// queued job that is due, or a running job whose lease expired because its worker died. Each claim
+// counts as an attempt. It returns nil when no job is runnable.
+//
+// Jobs that request the bookmark's host are spread across hosts: among jobs of equal priority, hosts
+// with the fewest such jobs running go first, and hosts already running maxPerHost are skipped
+// (0 means no cap). The cap is best effort, since concurrent claims may overshoot it briefly.
+func (s *Storage) ClaimJob(owner string, types []string, lease time.Duration, maxPerHost int) (*Job, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	typeArgs := make([]interface{}, len(types))
+	for i, jobType := range types {
+		typeArgs[i] = jobType
+	}
+	runnable := `type IN (` + strings.TrimSuffix(strings.Repeat("?,", len(types)), ",") + `)
+		AND ((state = 'queued' AND run_at <= ?) OR (state = 'running' AND lease_expires_at < ?))`
+
+	// Another worker may claim the same job between the select and the update, so try again
+	for attempt := 0; attempt < 3; attempt++ {
+		now := time.Now().Unix()
+		args := append(append([]interface{}{}, typeArgs...), now, now)
+
+		hostCap := ""
+		selectArgs := append([]interface{}{now}, args...)
+		if maxPerHost > 0 {
+			hostCap = ` AND COALESCE(busy.running, 0) < ?`
+			selectArgs = append(selectArgs, maxPerHost)
+		}
+
+		var id string
+		err := s.db.QueryRow(`
+			WITH busy AS (
+				SELECT b.domain, COUNT(*) AS running
+				FROM jobs r JOIN bookmarks b ON b.id = r.bookmark_id
+				WHERE r.state = 'running' AND r.lease_expires_at >= ? AND r.type IN `+hostJobTypes+`
+				GROUP BY b.domain
+			)
+			SELECT jobs.id FROM jobs
+			LEFT JOIN bookmarks ON bookmarks.id = jobs.bookmark_id
+			LEFT JOIN busy ON busy.domain = bookmarks.domain AND jobs.type IN `+hostJobTypes+`
+			WHERE `+runnable+hostCap+`
+			ORDER BY jobs.priority DESC, COALESCE(busy.running, 0), jobs.run_at, jobs.created_at
+			LIMIT 1`, selectArgs...).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find runnable job: %w", err)
+		}
+
+		updateArgs := append([]interface{}{owner, time.Now().Add(lease).Unix(), now, id}, args...)
+		result, err := s.db.Exec(`
+			UPDATE jobs SET state = 'running', lease_owner = ?, lease_expires_at = ?,
+				attempts = attempts + 1, updated_at = ?
+			WHERE id = ? AND `+runnable, updateArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim job: %w", err)
+		}
+		if claimed, _ := result.RowsAffected(); claimed == 1 {
+			return s.GetJob(id)
+		}
+	}
+
+	return nil, nil
+}
+
+// ExtendJobLease keeps a running job leased to owner for another lease duration
+func (s *Storage) ExtendJobLease(id, owner string, lease time.Duration) error {
+	now := time.Now()
+	return s.updateLeasedJob(id, owner, `lease_expires_at = ?, updated_at = ?`, now.Add(lease).Unix(), now.Unix())
+}
+
+// CompleteJob marks a leased job as succeeded with its JSON result
+func (s *Storage) CompleteJob(id, owner, result string) error {
+	now := time.Now().Unix()
+	return s.updateLeasedJob(id, owner, `state = 'succeeded', result = NULLIF(?, ''), lease_owner = NULL,
+		lease_expires_at = NULL, updated_at = ?, finished_at = ?`, result, now, now)
+}
+
+// RetryJob puts a leased job that failed back in the queue, to be claimed again at runAt
+func (s *Storage) RetryJob(id, owner, message string, runAt time.Time) error {
+	return s.updateLeasedJob(id, owner, `state = 'queued', last_error = ?, run_at = ?, lease_owner = NULL,
+		lease_expires_at = NULL, updated_at = ?`, message, runAt.Unix(), time.Now().Unix())
+}
+
+// DeadLetterJob marks a leased job that will not be retried as dead
+func (s *Storage) DeadLetterJob(id, owner, message string) error {
+	now := time.Now().Unix()
+	return s.updateLeasedJob(id, owner, `state = 'dead', last_error = ?, lease_owner = NULL,
+		lease_expires_at = NULL, updated_at = ?, finished_at = ?`, message, now, now)
+}
+
+// ReleaseJob returns a leased job to the queue without counting the attempt, for example when the
+// worker shuts down mid-job
+func (s *Storage) ReleaseJob(id, owner string) error {
+	return s.updateLeasedJob(id, owner, `state = 'queued', attempts = MAX(attempts - 1, 0), lease_owner = NULL,
+		lease_expires_at = NULL, updated_at = ?`, time.Now().Unix())
+}
+
+// updateLeasedJob applies set to a running job only while owner still holds its lease
+func (s *Storage) updateLeasedJob(id, owner, set string, args ...interface{}) error {
+	args = append(args, id, owner)
+	var result sql.Result
+	err := s.retryWithBackoff(func() error {
+		var err error
+		result, err = s.db.Exec(`UPDATE jobs SET `+set+` WHERE id = ? AND lease_owner = ? AND state = 'running'`, args...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return fmt.Errorf("%w: %s", ErrJobLeaseLost, id)
+	}
+	return nil
+}
+
+// GetJob retrieves a job by ID
+func (s *Storage) GetJob(id string) (*Job, error) {
+	job, err := scanJob(s.db.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return job, nil
+}
+
+// QueryJobs returns one page of jobs, most recently updated first
+func (s *Storage) QueryJobs(query JobQuery) (*JobPage, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 20
+	}
+
+	var conditions []string
+	var args []interface{}
+	if query.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, query.Type)
+	}
+	if query.State != "" {
+		conditions = append(conditions, "state = ?")
+		args = append(args, query.State)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	page := &JobPage{Jobs: []*Job{}}
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM jobs"+where, args...).Scan(&page.TotalItems); err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+
+	rows, err := s.db.Query(`SELECT `+jobColumns+` FROM jobs`+where+` ORDER BY updated_at DESC, rowid DESC LIMIT ? OFFSET ?`,
+		append(args, query.Limit, (query.Page-1)*query.Limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		page.Jobs = append(page.Jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read jobs: %w", err)
+	}
+
+	return page, nil
+}
+
+// CancelJob cancels an unfinished job. A running job loses its lease, so its worker's outcome is
+// discarded. Finished jobs return ErrJobStateConflict.
+func (s *Storage) CancelJob(id string) (*Job, error) {
+	now := time.Now().Unix()
+	result, err := s.db.Exec(`
+		UPDATE jobs SET state = 'cancelled', lease_owner = NULL, lease_expires_at = NULL,
+			updated_at = ?, finished_at = ?
+		WHERE id = ? AND state IN `+activeJobStates, now, now, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	return s.jobAfterTransition(id, result, "cancel")
+}
+
+// RequeueJob queues a dead or cancelled job again with a fresh set of attempts. It returns
+// ErrJobStateConflict for other states, or when the bookmark already has an unfinished job of the
+// same type.
+func (s *Storage) RequeueJob(id string) (*Job, error) {
+	now := time.Now().Unix()
+	result, err := s.db.Exec(`
+		UPDATE jobs SET state = 'queued', attempts = 0, run_at = ?, last_error = NULL, result = NULL,
+			updated_at = ?, finished_at = NULL
+		WHERE id = ? AND state IN ('dead', 'cancelled') AND NOT EXISTS (
+			SELECT 1 FROM jobs other
+			WHERE other.type = jobs.type AND other.bookmark_id = jobs.bookmark_id
+			  AND other.state IN `+activeJobStates+`
+		)`, now, now, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue job: %w", err)
+	}
+	return s.jobAfterTransition(id, result, "retry")
+}
+
+// jobAfterTransition returns the job a state change was applied to, telling a missing job apart
+// from one whose state did not allow the change
+func (s *Storage) jobAfterTransition(id string, result sql.Result, action string) (*Job, error) {
+	job, err := s.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return nil, fmt.Errorf("%w: cannot %s %s job %s", ErrJobStateConflict, action, job.State, id)
+	}
+	return job, nil
+}
+
+// PurgeJobs deletes finished jobs in the given state that finished before the given time, or any
+// time when before is zero. It returns how many were deleted.
+func (s *Storage) PurgeJobs(state string, before time.Time) (int64, error) {
+	query := `DELETE FROM jobs WHERE state = ? AND state IN ` + finishedJobStates
+	args := []interface{}{state}
+	if !before.IsZero() {
+		query += ` AND finished_at < ?`
+		args = append(args, before.Unix())
+	}
+
+	result, err := s.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// BatchJobs returns the jobs of a batch in the order they were enqueued
+func (s *Storage) BatchJobs(batchID string) ([]*Job, error) {
+	rows, err := s.db.Query(`SELECT `+jobColumns+` FROM jobs WHERE batch_id = ? ORDER BY created_at, rowid`, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list batch jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// TransitionBatchJobs moves the jobs of a batch that are in one of the from states to state,
+// returning how many moved. Running jobs are never moved.
+func (s *Storage) TransitionBatchJobs(batchID, state string, from ...string) (int64, error) {
+	var fromStates []interface{}
+	for _, fromState := range from {
+		if fromState != JobRunning {
+			fromStates = append(fromStates, fromState)
+		}
+	}
+	if len(fromStates) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now().Unix()
+	args := append([]interface{}{state, now, state, now, batchID}, fromStates...)
+	result, err := s.db.Exec(`
+		UPDATE jobs SET state = ?, updated_at = ?,
+			finished_at = CASE WHEN ? IN ('succeeded', 'dead', 'cancelled') THEN ? ELSE NULL END
+		WHERE batch_id = ? AND state IN (`+strings.TrimSuffix(strings.Repeat("?,", len(fromStates)), ",")+`)`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update batch jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// SuspendBatchJobs pauses the queued and running jobs of a batch. Running jobs are assumed to be
+// left over from a previous process, so their lease is dropped and their attempt not counted; call
+// it only before the job queue starts.
+func (s *Storage) SuspendBatchJobs(batchID string) (int64, error) {
+	result, err := s.db.Exec(`
+		UPDATE jobs SET state = 'paused',
+			attempts = CASE WHEN state = 'running' THEN MAX(attempts - 1, 0) ELSE attempts END,
+			lease_owner = NULL, lease_expires_at = NULL, updated_at = ?
+		WHERE batch_id = ? AND state IN ('queued', 'running')`, time.Now().Unix(), batchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to suspend batch jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// BookmarkIDsWithoutActiveJobs returns the IDs of bookmarks in the given processing status that
+// have no unfinished job of any of the given types
+func (s *Storage) BookmarkIDsWithoutActiveJobs(status string, jobTypes ...string) ([]string, error) {
+	return s.bookmarkIDsWithoutActiveJobs("b.status = ?", status, jobTypes)
+}
+
+// UnscrapedBookmarkIDsWithoutActiveJobs is BookmarkIDsWithoutActiveJobs limited to bookmarks that
+// have never been scraped
+func (s *Storage) UnscrapedBookmarkIDsWithoutActiveJobs(status string, jobTypes ...string) ([]string, error) {
+	return s.bookmarkIDsWithoutActiveJobs("b.status = ? AND b.scraped_at IS NULL", status, jobTypes)
+}
+
+// bookmarkIDsWithoutActiveJobs returns the IDs of bookmarks matching condition, which takes the
+// status as its only argument, that have no unfinished job of any of the given types
+func (s *Storage) bookmarkIDsWithoutActiveJobs(condition, status string, jobTypes []string) ([]string, error) {
+	args := []interface{}{status}
+	for _, jobType := range jobTypes {
+		args = append(args, jobType)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT b.id FROM bookmarks b
+		WHERE `+condition+` AND NOT EXISTS (
+			SELECT 1 FROM jobs j
+			WHERE j.bookmark_id = b.id AND j.state IN `+activeJobStates+`
+			  AND j.type IN (`+strings.TrimSuffix(strings.Repeat("?,", len(jobTypes)), ",")+`)
+		)
+		ORDER BY b.created_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+// BookmarkIDsDueForJob returns the IDs of bookmarks with no unfinished job of the given type and
+// no job of that type finished since the given time
+func (s *Storage) BookmarkIDsDueForJob(jobType string, finishedBefore time.Time) ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT b.id FROM bookmarks b
+		WHERE NOT EXISTS (
+			SELECT 1 FROM jobs j
+			WHERE j.bookmark_id = b.id AND j.type = ?
+			  AND (j.state IN `+activeJobStates+` OR j.finished_at >= ?)
+		)
+		ORDER BY b.created_at`, jobType, finishedBefore.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks due for %s: %w", jobType, err)
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+// scanJob reads a row selected with jobColumns
+func scanJob(row interface{ Scan(...interface{}) error }) (*Job, error) {
+	job := &Job{}
+	var runAt, createdAt, updatedAt int64
+	var leaseExpiresAt, finishedAt sql.NullInt64
+
+	err := row.Scan(&job.ID, &job.Type, &job.BookmarkID, &job.BatchID, &job.State, &job.Priority,
+		&job.Attempts, &job.MaxAttempts, &runAt, &job.LeaseOwner, &leaseExpiresAt, &job.LastError,
+		&job.Result, &createdAt, &updatedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	job.RunAt = time.Unix(runAt, 0)
+	job.CreatedAt = time.Unix(createdAt, 0)
+	job.UpdatedAt = time.Unix(updatedAt, 0)
+	if leaseExpiresAt.Valid {
+		expires := time.Unix(leaseExpiresAt.Int64, 0)
+		job.LeaseExpiresAt = &expires
+	}
+	if finishedAt.Valid {
+		finished := time.Unix(finishedAt.Int64, 0)
+		job.FinishedAt = &finished
+	}
+	return job, nil
+}
+
+// scanIDs reads a single id column
+func scanIDs(rows *sql.Rows) ([]string, error) {
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
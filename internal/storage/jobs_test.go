@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// enqueueJob adds a single job for a test
+func enqueueJob(t *testing.T, store *Storage, job *Job) *Job {
+	t.Helper()
+	if err := store.EnqueueJobs([]*Job{job}); err != nil {
+		t.Fatalf("Failed to enqueue job: %v", err)
+	}
+	return job
+}
+
+// claimJob claims the next scrape job, failing the test when none is runnable
+func claimJob(t *testing.T, store *Storage, owner string, lease time.Duration) *Job {
+	t.Helper()
+	job, err := store.ClaimJob(owner, []string{JobTypeScrape}, lease, 0)
+	if err != nil {
+		t.Fatalf("Failed to claim job: %v", err)
+	}
+	if job == nil {
+		t.Fatal("Expected a runnable job, got none")
+	}
+	return job
+}
+
+func TestEnqueueJobsMergesIntoUnfinishedJob(t *testing.T) {
+	store := newTestStorage(t)
+	bookmark := addBookmark(t, store, "https://example.com/a", "A")
+
+	first := enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: bookmark.ID, Priority: 1})
+	enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: bookmark.ID, Priority: 10, BatchID: "batch"})
+
+	page, err := store.QueryJobs(JobQuery{Type: JobTypeScrape})
+	if err != nil {
+		t.Fatalf("Failed to query jobs: %v", err)
+	}
+	if page.TotalItems != 1 {
+		t.Fatalf("Expected the second job to merge into the first, got %d jobs", page.TotalItems)
+	}
+	merged := page.Jobs[0]
+	if merged.ID != first.ID || merged.Priority != 10 || merged.BatchID != "batch" {
+		t.Errorf("Expected the first job raised to priority 10 in the new batch, got %+v", merged)
+	}
+
+	// A paused job is resumed by enqueueing it again
+	if _, err := store.TransitionBatchJobs("batch", JobPaused, JobQueued); err != nil {
+		t.Fatalf("Failed to pause batch: %v", err)
+	}
+	enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: bookmark.ID})
+	if job, _ := store.GetJob(first.ID); job.State != JobQueued {
+		t.Errorf("Expected the paused job to be queued again, got %s", job.State)
+	}
+
+	// Once the job is finished, enqueueing adds a new one
+	claimed := claimJob(t, store, "worker", time.Minute)
+	if err := store.CompleteJob(claimed.ID, "worker", ""); err != nil {
+		t.Fatalf("Failed to complete job: %v", err)
+	}
+	second := enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: bookmark.ID})
+	if page, _ := store.QueryJobs(JobQuery{Type: JobTypeScrape}); page.TotalItems != 2 || second.ID == first.ID {
+		t.Errorf("Expected a new job after the first finished, got %d jobs", page.TotalItems)
+	}
+}
+
+func TestClaimJobPriority(t *testing.T) {
+	store := newTestStorage(t)
+	low := addBookmark(t, store, "https://example.com/low", "Low")
+	high := addBookmark(t, store, "https://example.org/high", "High")
+
+	enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: low.ID, Priority: 1})
+	enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: high.ID, Priority: 10})
+	enqueueJob(t, store, &Job{Type: JobTypeEmbed, BookmarkID: low.ID, Priority: 100})
+
+	job := claimJob(t, store, "worker", time.Minute)
+	if job.BookmarkID != high.ID || job.State != JobRunning || job.Attempts != 1 || job.LeaseOwner != "worker" {
+		t.Errorf("Expected the high priority scrape job leased to the worker, got %+v", job)
+	}
+	if job := claimJob(t, store, "worker", time.Minute); job.BookmarkID != low.ID {
+		t.Errorf("Expected the low priority scrape job next, got %+v", job)
+	}
+	if job, err := store.ClaimJob("worker", []string{JobTypeScrape}, time.Minute, 0); err != nil || job != nil {
+		t.Errorf("Expected no runnable scrape job, got %+v, %v", job, err)
+	}
+}
+
+func TestClaimJobReclaimsExpiredLease(t *testing.T) {
+	store := newTestStorage(t)
+	bookmark := addBookmark(t, store, "https://example.com/a", "A")
+	enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: bookmark.ID})
+
+	// A lease that already ran out, as if the worker had crashed
+	crashed := claimJob(t, store, "crashed", -time.Minute)
+
+	job := claimJob(t, store, "survivor", time.Minute)
+	if job.ID != crashed.ID || job.Attempts != 2 || job.LeaseOwner != "survivor" {
+		t.Errorf("Expected the expired job reclaimed on its second attempt, got %+v", job)
+	}
+	if err := store.CompleteJob(job.ID, "crashed", ""); !errors.Is(err, ErrJobLeaseLost) {
+		t.Errorf("Expected the old owner to have lost the lease, got %v", err)
+	}
+	if err := store.CompleteJob(job.ID, "survivor", `{"ok":true}`); err != nil {
+		t.Errorf("Failed to complete job: %v", err)
+	}
+	if job, _ := store.GetJob(job.ID); job.State != JobSucceeded || job.FinishedAt == nil || job.Result != `{"ok":true}` {
+		t.Errorf("Expected a succeeded job with its result, got %+v", job)
+	}
+}
+
+func TestClaimJobHostCap(t *testing.T) {
+	store := newTestStorage(t)
+	urls := []string{"https://busy.com/1", "https://www.busy.com/2", "https://busy.com/3", "https://quiet.org/1"}
+	for i, url := range urls {
+		bookmark := addBookmark(t, store, url, url)
+		// Queued in order, so only the host spread reorders the claims
+		runAt := time.Now().Add(time.Duration(i-len(urls)) * time.Second)
+		enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: bookmark.ID, RunAt: runAt})
+	}
+
+	var claimed []string
+	for {
+		job, err := store.ClaimJob("worker", []string{JobTypeScrape}, time.Minute, 2)
+		if err != nil {
+			t.Fatalf("Failed to claim job: %v", err)
+		}
+		if job == nil {
+			break
+		}
+		bookmark, err := store.GetBookmark(job.BookmarkID)
+		if err != nil {
+			t.Fatalf("Failed to get bookmark: %v", err)
+		}
+		claimed = append(claimed, bookmark.URL)
+	}
+
+	// The quiet host goes before the busy host's second job, and the busy host's third job waits
+	expected := []string{"https://busy.com/1", "https://quiet.org/1", "https://www.busy.com/2"}
+	if len(claimed) != len(expected) {
+		t.Fatalf("Expected claims %v, got %v", expected, claimed)
+	}
+	for i := range expected {
+		if claimed[i] != expected[i] {
+			t.Errorf("Expected claims %v, got %v", expected, claimed)
+			break
+		}
+	}
+}
+
+func TestRetryAndDeadLetterJob(t *testing.T) {
+	store := newTestStorage(t)
+	bookmark := addBookmark(t, store, "https://example.com/a", "A")
+	enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: bookmark.ID, MaxAttempts: 2})
+
+	job := claimJob(t, store, "worker", time.Minute)
+	if err := store.RetryJob(job.ID, "worker", "timeout", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to retry job: %v", err)
+	}
+	job, _ = store.GetJob(job.ID)
+	if job.State != JobQueued || job.LastError != "timeout" || job.LeaseOwner != "" || job.Attempts != 1 {
+		t.Errorf("Expected a queued job keeping its attempt and error, got %+v", job)
+	}
+	if next, _ := store.ClaimJob("worker", []string{JobTypeScrape}, time.Minute, 0); next != nil {
+		t.Error("Expected the retried job to wait for its backoff")
+	}
+
+	if _, err := store.db.Exec(`UPDATE jobs SET run_at = ? WHERE id = ?`, time.Now().Add(-time.Second).Unix(), job.ID); err != nil {
+		t.Fatalf("Failed to make job due: %v", err)
+	}
+	job = claimJob(t, store, "worker", time.Minute)
+	if job.Attempts != job.MaxAttempts {
+		t.Fatalf("Expected the last allowed attempt, got %d of %d", job.Attempts, job.MaxAttempts)
+	}
+	if err := store.DeadLetterJob(job.ID, "worker", "still failing"); err != nil {
+		t.Fatalf("Failed to dead-letter job: %v", err)
+	}
+	job, _ = store.GetJob(job.ID)
+	if job.State != JobDead || job.LastError != "still failing" || job.FinishedAt == nil {
+		t.Errorf("Expected a dead job with its last error, got %+v", job)
+	}
+	if next, _ := store.ClaimJob("worker", []string{JobTypeScrape}, time.Minute, 0); next != nil {
+		t.Error("Expected a dead job never to be claimed")
+	}
+}
+
+func TestReleaseJobKeepsAttempt(t *testing.T) {
+	store := newTestStorage(t)
+	bookmark := addBookmark(t, store, "https://example.com/a", "A")
+	enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: bookmark.ID})
+
+	job := claimJob(t, store, "worker", time.Minute)
+	if err := store.ReleaseJob(job.ID, "worker"); err != nil {
+		t.Fatalf("Failed to release job: %v", err)
+	}
+	job, _ = store.GetJob(job.ID)
+	if job.State != JobQueued || job.Attempts != 0 || job.LeaseOwner != "" || job.LeaseExpiresAt != nil {
+		t.Errorf("Expected the released job queued without spending an attempt, got %+v", job)
+	}
+	if job := claimJob(t, store, "restarted", time.Minute); job.Attempts != 1 {
+		t.Errorf("Expected the released job to be claimed on its first attempt, got %d", job.Attempts)
+	}
+}
@@ -0,0 +1,389 @@
+package storage
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationFilePattern matches migration file names such as 003_add_categorization.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+
+// Migration is a versioned schema change embedded in the binary
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationStatus reports whether a migration has been applied to the database
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// loadMigrations reads the embedded migrations ordered by version
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	migrations := []Migration{}
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		content, err := fs.ReadFile(migrationFiles, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    match[2],
+			SQL:     string(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// legacySchema lists the migrations a database created before versioned migrations may already
+// contain, each with the table or column it adds
+var legacySchema = []struct {
+	version int
+	table   string
+	column  string
+}{
+	{version: 1, table: "bookmarks"},
+	{version: 2, table: "conversations"},
+	{version: 3, table: "bookmarks", column: "categorization_status"},
+	{version: 4, table: "messages", column: "citations"},
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// MigrationsInitialized reports whether the database has a schema_migrations table yet
+func (s *Storage) MigrationsInitialized() (bool, error) {
+	return tableExists(s.db, "schema_migrations")
+}
+
+// initializeMigrations creates the table that records applied migrations on first run. A database
+// created before versioned migrations already contains some of them; those are recorded as applied
+// so they are not run again.
+func (s *Storage) initializeMigrations(migrations []Migration) error {
+	initialized, err := s.MigrationsInitialized()
+	if err != nil || initialized {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	names := make(map[int]string, len(migrations))
+	for _, migration := range migrations {
+		names[migration.Version] = migration.Name
+	}
+
+	for _, legacy := range legacySchema {
+		present, err := tableExists(tx, legacy.table)
+		if err == nil && present && legacy.column != "" {
+			present, err = columnExists(tx, legacy.table, legacy.column)
+		}
+		if err != nil {
+			return err
+		}
+		if !present {
+			continue
+		}
+
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			legacy.version, names[legacy.version], time.Now()); err != nil {
+			return fmt.Errorf("failed to record existing migration %03d: %w", legacy.version, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// tableExists reports whether the database has a table with the given name
+func tableExists(db queryRower, table string) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up table %s: %w", table, err)
+	}
+	return count > 0, nil
+}
+
+// columnExists reports whether a table has a column with the given name
+func columnExists(db queryRower, table, column string) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up column %s.%s: %w", table, column, err)
+	}
+	return count > 0, nil
+}
+
+// appliedMigrations returns the applied migration versions with their application time
+func (s *Storage) appliedMigrations() (map[int]time.Time, error) {
+	rows, err := s.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations row: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// Migrate applies all pending migrations in version order, each inside its own transaction
+func (s *Storage) Migrate() error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	if err := s.initializeMigrations(migrations); err != nil {
+		return err
+	}
+
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if err := s.applyMigration(migration); err != nil {
+			return fmt.Errorf("migration %03d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs a single migration and records it in schema_migrations
+func (s *Storage) applyMigration(migration Migration) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(migration.SQL) {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to execute statement '%s': %w", statement, err)
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+		migration.Version, migration.Name, time.Now()); err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// MigrationStatus lists every embedded migration and whether it has been applied. It does not change
+// the database; before the first migration run every migration is reported as pending.
+func (s *Storage) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	initialized, err := s.MigrationsInitialized()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]time.Time)
+	if initialized {
+		if applied, err = s.appliedMigrations(); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+// splitStatements splits a SQL script into statements. Semicolons inside string literals, comments
+// and CREATE TRIGGER ... BEGIN ... END bodies do not end a statement.
+func splitStatements(script string) []string {
+	var statements []string
+	var current, word strings.Builder
+	var leadingWords []string
+	depth := 0
+
+	isTrigger := func() bool {
+		// CREATE [TEMP|TEMPORARY] TRIGGER ...
+		if len(leadingWords) < 2 || leadingWords[0] != "CREATE" {
+			return false
+		}
+		if leadingWords[1] == "TRIGGER" {
+			return true
+		}
+		return len(leadingWords) > 2 && (leadingWords[1] == "TEMP" || leadingWords[1] == "TEMPORARY") && leadingWords[2] == "TRIGGER"
+	}
+
+	endWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := strings.ToUpper(word.String())
+		word.Reset()
+
+		if len(leadingWords) < 3 {
+			leadingWords = append(leadingWords, w)
+		}
+		if !isTrigger() {
+			return
+		}
+		switch w {
+		case "BEGIN", "CASE":
+			depth++
+		case "END":
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+		if !isWordRune {
+			endWord()
+		}
+
+		switch {
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			// Line comment
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+			continue
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			// Block comment
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i++
+			current.WriteRune(' ')
+			continue
+
+		case r == '\'' || r == '"' || r == '`':
+			// Quoted literal or identifier; doubled quotes are escapes
+			current.WriteRune(r)
+			for i++; i < len(runes); i++ {
+				current.WriteRune(runes[i])
+				if runes[i] == r {
+					if i+1 < len(runes) && runes[i+1] == r {
+						i++
+						current.WriteRune(runes[i])
+						continue
+					}
+					break
+				}
+			}
+			continue
+
+		case r == ';' && depth == 0:
+			if statement := strings.TrimSpace(current.String()); statement != "" {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+			leadingWords = leadingWords[:0]
+			continue
+		}
+
+		if isWordRune {
+			word.WriteRune(r)
+		}
+		current.WriteRune(r)
+	}
+
+	endWord()
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
@@ -0,0 +1,72 @@
+-- Folders table for hierarchical structure
+CREATE TABLE IF NOT EXISTS folders (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    parent_id TEXT,
+    path TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (parent_id) REFERENCES folders(id) ON DELETE CASCADE
+);
+
+-- Bookmarks table
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id TEXT PRIMARY KEY,
+    url TEXT UNIQUE NOT NULL,
+    title TEXT,
+    description TEXT,
+    status TEXT DEFAULT 'pending' CHECK(status IN ('pending', 'completed', 'failed')),
+    imported_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    scraped_at TIMESTAMP,
+    folder_id TEXT,
+    folder_path TEXT,
+    favicon_url TEXT,
+    tags TEXT, -- JSON array of tags
+    FOREIGN KEY (folder_id) REFERENCES folders(id) ON DELETE SET NULL
+);
+
+-- Content table
+CREATE TABLE IF NOT EXISTS content (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bookmark_id TEXT NOT NULL,
+    raw_content TEXT,
+    clean_text TEXT,
+    scraped_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    content_type TEXT DEFAULT 'text/html',
+    FOREIGN KEY (bookmark_id) REFERENCES bookmarks(id) ON DELETE CASCADE
+);
+
+-- Embeddings table with vector support
+CREATE TABLE IF NOT EXISTS embeddings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id INTEGER NOT NULL,
+    chunk_index INTEGER DEFAULT 0,
+    chunk_text TEXT,
+    embedding BLOB,
+    model_version TEXT DEFAULT 'text-embedding-3-small',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (content_id) REFERENCES content(id) ON DELETE CASCADE
+);
+
+-- FTS5 virtual table for bookmarks full-text search
+CREATE VIRTUAL TABLE IF NOT EXISTS bookmarks_fts USING fts5(
+    title,
+    description
+);
+
+-- FTS5 virtual table for content full-text search
+CREATE VIRTUAL TABLE IF NOT EXISTS content_fts USING fts5(
+    clean_text
+);
+
+-- Indexes for performance
+CREATE INDEX IF NOT EXISTS idx_folders_parent_id ON folders(parent_id);
+CREATE INDEX IF NOT EXISTS idx_folders_path ON folders(path);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_status ON bookmarks(status);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_url ON bookmarks(url);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_folder_id ON bookmarks(folder_id);
+CREATE INDEX IF NOT EXISTS idx_content_bookmark_id ON content(bookmark_id);
+CREATE INDEX IF NOT EXISTS idx_embeddings_content_id ON embeddings(content_id);
+CREATE INDEX IF NOT EXISTS idx_embeddings_content_chunk ON embeddings(content_id, chunk_index);
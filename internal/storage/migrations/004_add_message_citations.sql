@@ -0,0 +1,2 @@
+-- Citation markers resolved to bookmark chunks (JSON array of citation objects)
+ALTER TABLE messages ADD COLUMN citations TEXT;
@@ -0,0 +1,4 @@
+-- Indexes backing the sort options of the bookmark list
+CREATE INDEX IF NOT EXISTS idx_bookmarks_created_at ON bookmarks(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_updated_at ON bookmarks(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_title ON bookmarks(title COLLATE NOCASE, id);
@@ -0,0 +1,47 @@
+-- bookmarks_fts was keyed by the implicit rowid of bookmarks, which is not stable for a table
+-- with a TEXT primary key (VACUUM may renumber it). Key it by the bookmark ID instead.
+DROP TABLE IF EXISTS bookmarks_fts;
+
+CREATE VIRTUAL TABLE bookmarks_fts USING fts5(
+    bookmark_id UNINDEXED,
+    title,
+    description
+);
+
+INSERT INTO bookmarks_fts (bookmark_id, title, description)
+SELECT id, COALESCE(title, ''), COALESCE(description, '') FROM bookmarks;
+
+-- Triggers keep bookmarks_fts in sync with every write to bookmarks
+CREATE TRIGGER IF NOT EXISTS bookmarks_fts_insert AFTER INSERT ON bookmarks BEGIN
+    INSERT INTO bookmarks_fts (bookmark_id, title, description)
+    VALUES (new.id, COALESCE(new.title, ''), COALESCE(new.description, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS bookmarks_fts_update AFTER UPDATE OF title, description ON bookmarks BEGIN
+    DELETE FROM bookmarks_fts WHERE bookmark_id = old.id;
+    INSERT INTO bookmarks_fts (bookmark_id, title, description)
+    VALUES (new.id, COALESCE(new.title, ''), COALESCE(new.description, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS bookmarks_fts_delete AFTER DELETE ON bookmarks BEGIN
+    DELETE FROM bookmarks_fts WHERE bookmark_id = old.id;
+END;
+
+-- content.id is an INTEGER PRIMARY KEY, so content_fts can stay keyed by rowid
+DELETE FROM content_fts;
+
+INSERT INTO content_fts (rowid, clean_text)
+SELECT id, COALESCE(clean_text, '') FROM content;
+
+CREATE TRIGGER IF NOT EXISTS content_fts_insert AFTER INSERT ON content BEGIN
+    INSERT INTO content_fts (rowid, clean_text) VALUES (new.id, COALESCE(new.clean_text, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS content_fts_update AFTER UPDATE OF clean_text ON content BEGIN
+    DELETE FROM content_fts WHERE rowid = old.id;
+    INSERT INTO content_fts (rowid, clean_text) VALUES (new.id, COALESCE(new.clean_text, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS content_fts_delete AFTER DELETE ON content BEGIN
+    DELETE FROM content_fts WHERE rowid = old.id;
+END;
@@ -0,0 +1,34 @@
+-- Move embeddings to a typed F32_BLOB(1536) column so they can be indexed with libsql_vector_idx.
+-- The width must match storage.VectorDimensions.
+CREATE TABLE embeddings_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id INTEGER NOT NULL,
+    chunk_index INTEGER DEFAULT 0,
+    chunk_text TEXT,
+    embedding F32_BLOB(1536),
+    model_version TEXT DEFAULT 'text-embedding-3-small',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (content_id) REFERENCES content(id) ON DELETE CASCADE
+);
+
+-- Existing vectors were written with vector32(), so 1536-dimension rows are already valid F32 blobs
+INSERT INTO embeddings_new (id, content_id, chunk_index, chunk_text, embedding, model_version, created_at)
+SELECT id, content_id, chunk_index, chunk_text, embedding, model_version, created_at
+FROM embeddings
+WHERE embedding IS NOT NULL AND length(embedding) = 1536 * 4;
+
+-- Rows of any other width cannot be copied; queue their bookmarks for re-embedding
+UPDATE bookmarks SET status = 'pending'
+WHERE status = 'completed'
+  AND id IN (
+    SELECT c.bookmark_id FROM content c
+    WHERE EXISTS (SELECT 1 FROM embeddings e WHERE e.content_id = c.id)
+      AND NOT EXISTS (SELECT 1 FROM embeddings_new n WHERE n.content_id = c.id)
+  );
+
+DROP TABLE embeddings;
+ALTER TABLE embeddings_new RENAME TO embeddings;
+
+CREATE INDEX IF NOT EXISTS idx_embeddings_content_id ON embeddings(content_id);
+CREATE INDEX IF NOT EXISTS idx_embeddings_content_chunk ON embeddings(content_id, chunk_index);
+CREATE INDEX IF NOT EXISTS idx_embeddings_vector ON embeddings(libsql_vector_idx(embedding, 'metric=cosine'));
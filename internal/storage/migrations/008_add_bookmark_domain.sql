@@ -0,0 +1,15 @@
+-- Host of each bookmark URL (lowercase, without "www."), used by search filters.
+-- New rows get the value from Go when they are inserted; existing rows are backfilled here.
+ALTER TABLE bookmarks ADD COLUMN domain TEXT;
+
+-- Strip the scheme
+UPDATE bookmarks SET domain = CASE WHEN instr(url, '://') > 0 THEN substr(url, instr(url, '://') + 3) ELSE '' END;
+-- Keep the authority, which ends at the first '/', '?' or '#'
+UPDATE bookmarks SET domain = substr(domain, 1, instr(replace(replace(domain || '/', '?', '/'), '#', '/'), '/') - 1);
+-- Drop user info and port
+UPDATE bookmarks SET domain = substr(domain, instr(domain, '@') + 1);
+UPDATE bookmarks SET domain = substr(domain || ':', 1, instr(domain || ':', ':') - 1);
+UPDATE bookmarks SET domain = lower(domain);
+UPDATE bookmarks SET domain = substr(domain, 5) WHERE domain LIKE 'www.%';
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_domain ON bookmarks(domain);
@@ -0,0 +1,29 @@
+-- Persistent background job queue (scrape, embed, categorize, link_check).
+-- Times are unix seconds so lease and schedule checks compare numbers.
+CREATE TABLE IF NOT EXISTS jobs (
+    id TEXT PRIMARY KEY,
+    type TEXT NOT NULL,
+    bookmark_id TEXT,
+    batch_id TEXT,                               -- Jobs enqueued together, e.g. one bulk scrape
+    state TEXT NOT NULL DEFAULT 'queued',        -- queued, paused, running, succeeded, dead, cancelled
+    priority INTEGER NOT NULL DEFAULT 0,         -- Higher runs first
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    run_at INTEGER NOT NULL,                     -- Earliest time the job may be claimed
+    lease_owner TEXT,
+    lease_expires_at INTEGER,
+    last_error TEXT,
+    result TEXT,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    finished_at INTEGER,
+    FOREIGN KEY (bookmark_id) REFERENCES bookmarks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_claim ON jobs(state, priority DESC, run_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_batch ON jobs(batch_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_bookmark ON jobs(bookmark_id);
+
+-- A bookmark has at most one unfinished job of each type
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_active ON jobs(type, bookmark_id)
+    WHERE state IN ('queued', 'paused', 'running');
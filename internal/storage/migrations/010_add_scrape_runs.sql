@@ -0,0 +1,40 @@
+-- Bulk scrape runs, so a run and the status of each of its bookmarks survive a restart.
+-- Only the latest run is kept. Times are unix seconds, as in jobs.
+CREATE TABLE IF NOT EXISTS scrape_runs (
+    id TEXT PRIMARY KEY,                            -- Also the batch_id of the run's scrape jobs
+    status TEXT NOT NULL,                           -- running, paused, completed, stopped
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    finished_at INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS scrape_run_bookmarks (
+    run_id TEXT NOT NULL,
+    bookmark_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'not-scraped',     -- not-scraped, in-progress, scraped, error
+    error TEXT,
+    updated_at INTEGER NOT NULL,
+    PRIMARY KEY (run_id, bookmark_id),
+    FOREIGN KEY (run_id) REFERENCES scrape_runs(id) ON DELETE CASCADE,
+    FOREIGN KEY (bookmark_id) REFERENCES bookmarks(id) ON DELETE CASCADE
+);
+
+-- Keeps each bookmark's status in sync with its scrape job in the run's batch
+CREATE TRIGGER IF NOT EXISTS jobs_scrape_run_progress AFTER UPDATE OF state ON jobs
+WHEN new.type = 'scrape' AND new.batch_id IS NOT NULL BEGIN
+    UPDATE scrape_run_bookmarks SET
+        status = CASE new.state
+            WHEN 'running' THEN 'in-progress'
+            WHEN 'succeeded' THEN 'scraped'
+            WHEN 'dead' THEN 'error'
+            WHEN 'cancelled' THEN 'error'
+            ELSE 'not-scraped'
+        END,
+        error = CASE new.state
+            WHEN 'succeeded' THEN NULL
+            WHEN 'cancelled' THEN 'Cancelled'
+            ELSE new.last_error
+        END,
+        updated_at = new.updated_at
+    WHERE run_id = new.batch_id AND bookmark_id = new.bookmark_id;
+END;
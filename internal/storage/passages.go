@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxPassagesPerResult is how many matching chunks a folded result keeps
+	maxPassagesPerResult = 3
+	// passageLength is the maximum length in runes of a passage excerpt
+	passageLength = 300
+)
+
+// Passage is an excerpt of a content chunk that matched a search
+type Passage struct {
+	ChunkIndex int `json:"chunk_index"`
+	// Text is scraped page text with query words wrapped in <mark> tags. Everything outside the tags
+	// is unescaped, so clients must HTML-escape it before rendering the marks.
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
+
+// foldChunkResults merges chunk-level results, ordered best first, into one result per bookmark
+// that keeps its best chunks as passages. A limit of 0 keeps every bookmark.
+func foldChunkResults(results []*SearchResult, limit int) []*SearchResult {
+	folded := make(map[string]*SearchResult)
+	var ordered []*SearchResult
+
+	for _, result := range results {
+		existing, ok := folded[result.Bookmark.ID]
+		if !ok {
+			if limit > 0 && len(ordered) >= limit {
+				continue
+			}
+			folded[result.Bookmark.ID] = result
+			ordered = append(ordered, result)
+			continue
+		}
+		for _, passage := range result.Passages {
+			if len(existing.Passages) < maxPassagesPerResult {
+				existing.Passages = append(existing.Passages, passage)
+			}
+		}
+	}
+
+	return ordered
+}
+
+// highlightPassages trims each passage to an excerpt around the first query word and marks the
+// query words in it
+func highlightPassages(results []*SearchResult, queryText string) {
+	words := ftsWords(strings.ToLower(queryText))
+	for _, result := range results {
+		for _, passage := range result.Passages {
+			passage.Text = highlightExcerpt(passage.Text, words, passageLength)
+		}
+	}
+}
+
+// highlightExcerpt returns at most maxLength runes of text, starting shortly before the first
+// query word, with every query word wrapped in <mark> tags
+func highlightExcerpt(text string, words []string, maxLength int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := []rune(strings.ToLower(string(runes)))
+
+	isWordAt := func(i int, word []rune) bool {
+		if i+len(word) > len(lower) || string(lower[i:i+len(word)]) != string(word) {
+			return false
+		}
+		// Only match whole words, except that a query word may be the start of a longer one
+		return i == 0 || !isWordRune(lower[i-1])
+	}
+
+	wordRunes := make([][]rune, 0, len(words))
+	for _, word := range words {
+		wordRunes = append(wordRunes, []rune(word))
+	}
+
+	matchAt := func(i int) int {
+		for _, word := range wordRunes {
+			if isWordAt(i, word) {
+				return len(word)
+			}
+		}
+		return 0
+	}
+
+	// Start the excerpt a little before the first match, at a word boundary
+	start := 0
+	for i := range lower {
+		if matchAt(i) > 0 {
+			start = max(0, i-maxLength/4)
+			for start > 0 && start < i && isWordRune(runes[start-1]) {
+				start++
+			}
+			break
+		}
+	}
+	end := min(len(runes), start+maxLength)
+	for end < len(runes) && end > start && isWordRune(runes[end]) && isWordRune(runes[end-1]) {
+		end--
+	}
+	if end == start {
+		// A single word longer than the excerpt is cut mid-word
+		end = min(len(runes), start+maxLength)
+	}
+	for end < len(runes) && end > start && unicode.IsSpace(runes[end-1]) {
+		end--
+	}
+
+	var excerpt strings.Builder
+	if start > 0 {
+		excerpt.WriteString("...")
+	}
+	for i := start; i < end; {
+		if n := matchAt(i); n > 0 && i+n <= end {
+			excerpt.WriteString("<mark>" + string(runes[i:i+n]) + "</mark>")
+			i += n
+			continue
+		}
+		excerpt.WriteRune(runes[i])
+		i++
+	}
+	if end < len(runes) {
+		excerpt.WriteString("...")
+	}
+
+	return excerpt.String()
+}
+
+// isWordRune reports whether r is part of a word for highlighting purposes
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHighlightExcerpt(t *testing.T) {
+	filler := strings.Repeat("alpha beta gamma ", 10)
+
+	tests := []struct {
+		name      string
+		text      string
+		words     []string
+		maxLength int
+		want      string
+	}{
+		{"match at start", "Go is fun", []string{"go"}, 300, "<mark>Go</mark> is fun"},
+		{"match in middle", "Learn about channels today", []string{"channels"}, 300, "Learn about <mark>channels</mark> today"},
+		{"match at end", "The language is golang", []string{"golang"}, 300, "The language is <mark>golang</mark>"},
+		{"several words", "Channels and goroutines share memory", []string{"channels", "memory"}, 300,
+			"<mark>Channels</mark> and goroutines share <mark>memory</mark>"},
+		{"prefix of a longer word", "Goroutines in Go", []string{"go"}, 300, "<mark>Go</mark>routines in <mark>Go</mark>"},
+		{"not inside a word", "ergo", []string{"go"}, 300, "ergo"},
+		{"whitespace collapsed", "Go\n\n  is\tfun", []string{"fun"}, 300, "Go is <mark>fun</mark>"},
+		{"trimmed around a late match", filler + "needle " + filler, []string{"needle"}, 40,
+			"...gamma <mark>needle</mark> alpha beta gamma alpha beta..."},
+		{"trimmed without a match", filler, []string{"needle"}, 20, "alpha beta gamma..."},
+		{"one word longer than the excerpt", strings.Repeat("x", 30), []string{"needle"}, 10, strings.Repeat("x", 10) + "..."},
+		{"page text left as is", "<b>needle</b> & co", []string{"needle"}, 300, "<b><mark>needle</mark></b> & co"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlightExcerpt(tt.text, tt.words, tt.maxLength)
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+
+			plain := strings.NewReplacer("<mark>", "", "</mark>", "", "...", "").Replace(got)
+			if n := utf8.RuneCountInString(plain); n > tt.maxLength {
+				t.Errorf("Expected at most %d runes of text, got %d", tt.maxLength, n)
+			}
+		})
+	}
+}
+
+func TestFoldChunkResults(t *testing.T) {
+	chunk := func(bookmarkID string, index int, score float64) *SearchResult {
+		return &SearchResult{
+			Bookmark:       &Bookmark{ID: bookmarkID},
+			RelevanceScore: score,
+			Passages:       []*Passage{{ChunkIndex: index, Score: score}},
+		}
+	}
+	// Chunk results arrive best first
+	results := []*SearchResult{
+		chunk("a", 4, 0.9), chunk("b", 0, 0.85), chunk("a", 1, 0.8), chunk("a", 7, 0.7),
+		chunk("c", 2, 0.65), chunk("a", 0, 0.6), chunk("b", 3, 0.5), chunk("d", 1, 0.4),
+	}
+
+	folded := foldChunkResults(results, 0)
+	if got := fmt.Sprint(resultIDs(folded)); got != "[a b c d]" {
+		t.Fatalf("Expected one result per bookmark in order of its best chunk, got %s", got)
+	}
+	if folded[0].RelevanceScore != 0.9 {
+		t.Errorf("Expected a folded result to keep its best score, got %f", folded[0].RelevanceScore)
+	}
+
+	passages := func(result *SearchResult) string {
+		var indexes []int
+		for _, passage := range result.Passages {
+			indexes = append(indexes, passage.ChunkIndex)
+		}
+		return fmt.Sprint(indexes)
+	}
+	// a matched with four chunks; only its best three are kept
+	if got := passages(folded[0]); got != "[4 1 7]" {
+		t.Errorf("Expected a's three best chunks, got %s", got)
+	}
+	if got := passages(folded[1]); got != "[0 3]" {
+		t.Errorf("Expected both of b's chunks, got %s", got)
+	}
+
+	// The limit counts bookmarks, and later chunks of kept bookmarks are still folded in
+	limited := foldChunkResults([]*SearchResult{
+		chunk("a", 0, 0.9), chunk("b", 0, 0.8), chunk("c", 0, 0.7), chunk("b", 1, 0.6),
+	}, 2)
+	if got := fmt.Sprint(resultIDs(limited)); got != "[a b]" {
+		t.Fatalf("Expected the first 2 bookmarks, got %s", got)
+	}
+	if got := passages(limited[1]); got != "[0 1]" {
+		t.Errorf("Expected b's later chunk to be folded in past the limit, got %s", got)
+	}
+}
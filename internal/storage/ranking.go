@@ -0,0 +1,320 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FusionMethod selects how semantic and keyword result lists are merged
+type FusionMethod string
+
+const (
+	// FusionRRF combines result lists by reciprocal rank, ignoring the raw score scales
+	FusionRRF FusionMethod = "rrf"
+	// FusionWeighted combines the normalized scores with a weighted sum
+	FusionWeighted FusionMethod = "weighted"
+)
+
+// DefaultRankingProfile is used when no profile is configured or requested
+const DefaultRankingProfile = "balanced"
+
+// ErrUnknownRankingProfile is returned when a ranking profile name is not defined
+var ErrUnknownRankingProfile = errors.New("unknown ranking profile")
+
+// RankingProfile configures how HybridSearch fuses and boosts results.
+// Boosts are fractions in [0,1] of the headroom left above a score, so a boosted
+// score approaches but never exceeds 1.
+type RankingProfile struct {
+	Name             string       `json:"name"`
+	Fusion           FusionMethod `json:"fusion"`
+	SemanticWeight   float64      `json:"semantic_weight"`
+	KeywordWeight    float64      `json:"keyword_weight"`
+	RRFConstant      float64      `json:"rrf_constant"`       // k in 1/(k+rank); dampens the lead of top ranks
+	MinSemanticScore float64      `json:"min_semantic_score"` // cosine similarity below which semantic hits are dropped
+	MinKeywordScore  float64      `json:"min_keyword_score"`  // normalized BM25 below which keyword hits are dropped
+	TitlePhraseBoost float64      `json:"title_phrase_boost"` // the whole query appears in the title
+	TitleTermBoost   float64      `json:"title_term_boost"`   // scaled by the share of query terms found in the title
+	URLBoost         float64      `json:"url_boost"`          // the whole query appears in the URL
+	DescriptionBoost float64      `json:"description_boost"`  // a query term appears in the description
+	ContentBoost     float64      `json:"content_boost"`      // a query term appears in the content
+	Limit            int          `json:"limit"`
+}
+
+// rankingProfiles are the built-in named profiles
+var rankingProfiles = map[string]RankingProfile{
+	"balanced": {
+		Name:             "balanced",
+		Fusion:           FusionRRF,
+		SemanticWeight:   1.0,
+		KeywordWeight:    1.0,
+		RRFConstant:      60,
+		MinSemanticScore: 0.3,
+		MinKeywordScore:  0.0,
+		TitlePhraseBoost: 0.5,
+		TitleTermBoost:   0.3,
+		URLBoost:         0.3,
+		DescriptionBoost: 0.1,
+		ContentBoost:     0.05,
+		Limit:            20,
+	},
+	"semantic": {
+		Name:             "semantic",
+		Fusion:           FusionWeighted,
+		SemanticWeight:   0.7,
+		KeywordWeight:    0.3,
+		MinSemanticScore: 0.3,
+		MinKeywordScore:  0.15,
+		TitlePhraseBoost: 0.3,
+		TitleTermBoost:   0.2,
+		URLBoost:         0.2,
+		DescriptionBoost: 0.05,
+		Limit:            20,
+	},
+	"keyword": {
+		Name:             "keyword",
+		Fusion:           FusionWeighted,
+		SemanticWeight:   0.3,
+		KeywordWeight:    0.7,
+		MinSemanticScore: 0.4,
+		MinKeywordScore:  0.05,
+		TitlePhraseBoost: 0.6,
+		TitleTermBoost:   0.4,
+		URLBoost:         0.4,
+		DescriptionBoost: 0.15,
+		ContentBoost:     0.1,
+		Limit:            20,
+	},
+}
+
+// LookupRankingProfile returns the named ranking profile; an empty name selects the default
+func LookupRankingProfile(name string) (RankingProfile, error) {
+	if name == "" {
+		name = DefaultRankingProfile
+	}
+	profile, ok := rankingProfiles[strings.ToLower(name)]
+	if !ok {
+		return RankingProfile{}, fmt.Errorf("%w: %s (available: %s)", ErrUnknownRankingProfile, name, strings.Join(RankingProfileNames(), ", "))
+	}
+	return profile, nil
+}
+
+// RankingProfileNames lists the available ranking profiles
+func RankingProfileNames() []string {
+	names := make([]string, 0, len(rankingProfiles))
+	for name := range rankingProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fusionCandidate tracks one bookmark across the semantic and keyword result lists
+type fusionCandidate struct {
+	result        *SearchResult
+	semanticRank  int // 1-based, 0 when absent
+	keywordRank   int
+	semanticScore float64
+	keywordScore  float64
+}
+
+// fuseResults merges semantic and keyword results per bookmark according to the profile.
+// Keyword scores must already be normalized to [0,1].
+func fuseResults(semanticResults, keywordResults []*SearchResult, profile RankingProfile, queryText string) []*SearchResult {
+	candidates := make(map[string]*fusionCandidate)
+	var order []string
+
+	// Results are ordered best first and may hold several chunks per bookmark; keep the best one
+	rank := 0
+	for _, result := range semanticResults {
+		if result.RelevanceScore < profile.MinSemanticScore {
+			continue
+		}
+		if _, exists := candidates[result.Bookmark.ID]; exists {
+			continue
+		}
+		rank++
+		candidates[result.Bookmark.ID] = &fusionCandidate{result: result, semanticRank: rank, semanticScore: result.RelevanceScore}
+		order = append(order, result.Bookmark.ID)
+	}
+
+	rank = 0
+	for _, result := range keywordResults {
+		if result.RelevanceScore < profile.MinKeywordScore {
+			continue
+		}
+		candidate, exists := candidates[result.Bookmark.ID]
+		if exists && candidate.keywordRank > 0 {
+			continue
+		}
+		rank++
+		if !exists {
+			candidate = &fusionCandidate{result: result}
+			candidates[result.Bookmark.ID] = candidate
+			order = append(order, result.Bookmark.ID)
+		} else if result.MatchedSnippet != "" {
+			candidate.result.MatchedSnippet = result.MatchedSnippet
+		}
+		candidate.keywordRank = rank
+		candidate.keywordScore = result.RelevanceScore
+	}
+
+	results := make([]*SearchResult, 0, len(order))
+	for _, id := range order {
+		candidate := candidates[id]
+		result := candidate.result
+
+		switch {
+		case candidate.semanticRank > 0 && candidate.keywordRank > 0:
+			result.SearchType = "hybrid"
+		case candidate.semanticRank > 0:
+			result.SearchType = "semantic"
+		default:
+			result.SearchType = "keyword"
+		}
+
+		score := fusedScore(candidate, profile)
+		boost := queryMatchBoost(result, queryText, profile)
+		result.RelevanceScore = clampScore(score + boost*(1-score))
+
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].RelevanceScore > results[j].RelevanceScore
+	})
+
+	if profile.Limit > 0 && len(results) > profile.Limit {
+		results = results[:profile.Limit]
+	}
+
+	return results
+}
+
+// fusedScore combines a candidate's semantic and keyword evidence into a score in [0,1]
+func fusedScore(candidate *fusionCandidate, profile RankingProfile) float64 {
+	totalWeight := profile.SemanticWeight + profile.KeywordWeight
+	if totalWeight <= 0 {
+		return 0
+	}
+
+	if profile.Fusion == FusionRRF {
+		k := profile.RRFConstant
+		if k <= 0 {
+			k = 60
+		}
+		score := 0.0
+		if candidate.semanticRank > 0 {
+			score += profile.SemanticWeight / (k + float64(candidate.semanticRank))
+		}
+		if candidate.keywordRank > 0 {
+			score += profile.KeywordWeight / (k + float64(candidate.keywordRank))
+		}
+		// Normalize by the score of a result ranked first in both lists
+		return clampScore(score / (totalWeight / (k + 1)))
+	}
+
+	return clampScore((profile.SemanticWeight*candidate.semanticScore + profile.KeywordWeight*candidate.keywordScore) / totalWeight)
+}
+
+// queryMatchBoost returns the combined boost for literal query matches in the bookmark's fields.
+// Independent boosts combine as 1 - Π(1 - b), which also stays within [0,1].
+func queryMatchBoost(result *SearchResult, queryText string, profile RankingProfile) float64 {
+	queryLower := strings.ToLower(strings.TrimSpace(queryText))
+	if queryLower == "" {
+		return 0
+	}
+	queryWords := strings.Fields(queryLower)
+
+	titleLower := strings.ToLower(result.Bookmark.Title)
+	var boosts []float64
+
+	if strings.Contains(titleLower, queryLower) {
+		boosts = append(boosts, profile.TitlePhraseBoost)
+	} else if strings.Contains(strings.ToLower(result.Bookmark.URL), queryLower) {
+		boosts = append(boosts, profile.URLBoost)
+	}
+
+	titleWords := make(map[string]bool)
+	for _, word := range strings.Fields(titleLower) {
+		titleWords[word] = true
+	}
+	titleMatches := 0
+	for _, word := range queryWords {
+		if titleWords[word] {
+			titleMatches++
+		}
+	}
+	if titleMatches > 0 {
+		boosts = append(boosts, profile.TitleTermBoost*float64(titleMatches)/float64(len(queryWords)))
+	}
+
+	if containsAnyWord(result.Bookmark.Description, queryWords) {
+		boosts = append(boosts, profile.DescriptionBoost)
+	} else if result.Content != nil && containsAnyWord(result.Content.CleanText, queryWords) {
+		boosts = append(boosts, profile.ContentBoost)
+	} else if passagesContainAnyWord(result.Passages, queryWords) {
+		boosts = append(boosts, profile.ContentBoost)
+	}
+
+	remaining := 1.0
+	for _, boost := range boosts {
+		remaining *= 1 - clampScore(boost)
+	}
+	return 1 - remaining
+}
+
+// containsAnyWord reports whether text contains any of the lowercase words
+func containsAnyWord(text string, words []string) bool {
+	if text == "" {
+		return false
+	}
+	textLower := strings.ToLower(text)
+	for _, word := range words {
+		if strings.Contains(textLower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// passagesContainAnyWord reports whether any passage contains any of the lowercase words
+func passagesContainAnyWord(passages []*Passage, words []string) bool {
+	for _, passage := range passages {
+		if containsAnyWord(passage.Text, words) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeBM25Scores converts FTS5 bm25() values, where more negative is better,
+// into scores in [0,1] relative to the best result
+func normalizeBM25Scores(results []*SearchResult) {
+	maxScore := 0.0
+	for _, result := range results {
+		if -result.RelevanceScore > maxScore {
+			maxScore = -result.RelevanceScore
+		}
+	}
+
+	for _, result := range results {
+		if maxScore <= 0 {
+			result.RelevanceScore = 0
+			continue
+		}
+		result.RelevanceScore = clampScore(-result.RelevanceScore / maxScore)
+	}
+}
+
+// clampScore limits a score to [0,1]
+func clampScore(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// rankedResult builds a search result for a bookmark with the given score
+func rankedResult(id string, score float64) *SearchResult {
+	return &SearchResult{
+		Bookmark:       &Bookmark{ID: id, Title: id, URL: "https://example.com/" + id},
+		RelevanceScore: score,
+	}
+}
+
+// resultIDs returns the bookmark IDs of results in order
+func resultIDs(results []*SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.Bookmark.ID
+	}
+	return ids
+}
+
+func TestFuseResultsRRF(t *testing.T) {
+	profile, _ := LookupRankingProfile("balanced")
+	semantic := []*SearchResult{rankedResult("a", 0.9), rankedResult("b", 0.8), rankedResult("a", 0.7), rankedResult("e", 0.65), rankedResult("c", 0.6)}
+	keyword := []*SearchResult{rankedResult("c", 1.0), rankedResult("b", 0.5), rankedResult("d", 0.4)}
+
+	results := fuseResults(semantic, keyword, profile, "unmatched query")
+
+	// b is second in both lists, which beats first in one and fourth in the other, or first in one only.
+	// Ranks count bookmarks, so the second chunk of a does not push c down.
+	want := []string{"b", "c", "a", "e", "d"}
+	if got := resultIDs(results); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("Expected RRF order %v, got %v", want, got)
+	}
+	types := map[string]string{"a": "semantic", "b": "hybrid", "c": "hybrid", "d": "keyword", "e": "semantic"}
+	for _, result := range results {
+		if result.SearchType != types[result.Bookmark.ID] {
+			t.Errorf("Expected %s to be a %s result, got %s", result.Bookmark.ID, types[result.Bookmark.ID], result.SearchType)
+		}
+	}
+
+	// A result ranked first in both lists scores exactly 1
+	top := fuseResults([]*SearchResult{rankedResult("a", 0.9)}, []*SearchResult{rankedResult("a", 1.0)}, profile, "")
+	if top[0].RelevanceScore != 1 {
+		t.Errorf("Expected a result first in both lists to score 1, got %f", top[0].RelevanceScore)
+	}
+}
+
+func TestFuseResultsWeighted(t *testing.T) {
+	profile, _ := LookupRankingProfile("semantic")
+
+	tests := []struct {
+		name              string
+		semantic, keyword []*SearchResult
+		want              float64
+	}{
+		{"semantic only", []*SearchResult{rankedResult("a", 0.8)}, nil, 0.7 * 0.8},
+		{"keyword only", nil, []*SearchResult{rankedResult("a", 0.6)}, 0.3 * 0.6},
+		{"both", []*SearchResult{rankedResult("a", 0.8)}, []*SearchResult{rankedResult("a", 0.6)}, 0.7*0.8 + 0.3*0.6},
+	}
+	for _, tt := range tests {
+		results := fuseResults(tt.semantic, tt.keyword, profile, "")
+		if len(results) != 1 {
+			t.Fatalf("%s: expected 1 result, got %d", tt.name, len(results))
+		}
+		if math.Abs(results[0].RelevanceScore-tt.want) > 1e-9 {
+			t.Errorf("%s: expected score %f, got %f", tt.name, tt.want, results[0].RelevanceScore)
+		}
+	}
+
+	// Hits below the profile's thresholds are dropped
+	results := fuseResults([]*SearchResult{rankedResult("a", 0.2)}, []*SearchResult{rankedResult("b", 0.1)}, profile, "")
+	if len(results) != 0 {
+		t.Errorf("Expected hits below the thresholds to be dropped, got %v", resultIDs(results))
+	}
+}
+
+func TestFusedScoresStayInRange(t *testing.T) {
+	// Every boost applies: the phrase is in the title and URL, and every word in the title,
+	// description and content
+	boosted := func(id string, score float64) *SearchResult {
+		result := rankedResult(id, score)
+		result.Bookmark.Title = "Go concurrency patterns"
+		result.Bookmark.URL = "https://example.com/go concurrency patterns"
+		result.Bookmark.Description = "go concurrency patterns"
+		result.Content = &Content{CleanText: "go concurrency patterns"}
+		result.Passages = []*Passage{{Text: "go concurrency patterns"}}
+		return result
+	}
+
+	profiles := []RankingProfile{}
+	for _, name := range RankingProfileNames() {
+		profile, err := LookupRankingProfile(name)
+		if err != nil {
+			t.Fatalf("Failed to look up profile %s: %v", name, err)
+		}
+		profiles = append(profiles, profile)
+	}
+	// Out-of-range settings cannot push scores out of range either
+	profiles = append(profiles, RankingProfile{
+		Name: "extreme", Fusion: FusionWeighted, SemanticWeight: 5, KeywordWeight: 5,
+		TitlePhraseBoost: 3, TitleTermBoost: 3, URLBoost: 3, DescriptionBoost: 3, ContentBoost: 3,
+	})
+
+	for _, profile := range profiles {
+		semantic := []*SearchResult{boosted("a", 1.0), boosted("b", 0.95), boosted("c", 0.9)}
+		keyword := []*SearchResult{boosted("a", 1.0), boosted("d", 1.0), boosted("b", 0.9)}
+		for _, result := range fuseResults(semantic, keyword, profile, "Go concurrency patterns") {
+			if result.RelevanceScore < 0 || result.RelevanceScore > 1 {
+				t.Errorf("Profile %s: score %f of %s is outside [0,1]", profile.Name, result.RelevanceScore, result.Bookmark.ID)
+			}
+		}
+
+		boost := queryMatchBoost(boosted("a", 0), "go concurrency patterns", profile)
+		if boost < 0 || boost > 1 {
+			t.Errorf("Profile %s: boost %f is outside [0,1]", profile.Name, boost)
+		}
+	}
+}
+
+func TestNormalizeBM25Scores(t *testing.T) {
+	results := []*SearchResult{rankedResult("a", -8), rankedResult("b", -2), rankedResult("c", 0), rankedResult("d", 1)}
+	normalizeBM25Scores(results)
+
+	want := []float64{1, 0.25, 0, 0}
+	for i, result := range results {
+		if math.Abs(result.RelevanceScore-want[i]) > 1e-9 {
+			t.Errorf("Expected %s to score %f, got %f", result.Bookmark.ID, want[i], result.RelevanceScore)
+		}
+	}
+
+	// Without a single match there is nothing to scale by
+	none := []*SearchResult{rankedResult("a", 0)}
+	normalizeBM25Scores(none)
+	if none[0].RelevanceScore != 0 {
+		t.Errorf("Expected a zero score, got %f", none[0].RelevanceScore)
+	}
+}
+
+func TestLookupRankingProfile(t *testing.T) {
+	profile, err := LookupRankingProfile("")
+	if err != nil || profile.Name != DefaultRankingProfile {
+		t.Errorf("Expected the default profile for an empty name, got %q, %v", profile.Name, err)
+	}
+	if profile, err := LookupRankingProfile("Keyword"); err != nil || profile.Name != "keyword" {
+		t.Errorf("Expected a case-insensitive lookup, got %q, %v", profile.Name, err)
+	}
+	if _, err := LookupRankingProfile("fancy"); !errors.Is(err, ErrUnknownRankingProfile) {
+		t.Errorf("Expected ErrUnknownRankingProfile, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrBookmarkNotEmbedded is returned when a bookmark has no stored embeddings to compare with
+var ErrBookmarkNotEmbedded = errors.New("bookmark has no embeddings")
+
+// maxRelatedCandidates caps how many neighbouring chunks RelatedBookmarks reads
+const maxRelatedCandidates = 2000
+
+// RelatedBookmarks finds the bookmarks closest to the given one using its stored chunk embeddings,
+// so no embedding API call is needed. The bookmark itself is excluded and results are spread
+// across domains.
+func (s *Storage) RelatedBookmarks(bookmarkID string, limit int) ([]*SearchResult, error) {
+	if _, err := s.GetBookmark(bookmarkID); err != nil {
+		return nil, err
+	}
+
+	queryEmbedding, err := s.bookmarkCentroid(bookmarkID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A neighbour with many similar chunks can fill the nearest rows on its own, so widen the search
+	// until there are enough distinct bookmarks to spread across domains, or no more neighbours
+	var results []*SearchResult
+	for candidates := limit * 5; ; candidates *= 2 {
+		chunks, err := s.semanticSearch(queryEmbedding, candidates, SearchOptions{ExcludeIDs: []string{bookmarkID}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search related bookmarks: %w", err)
+		}
+		results = foldChunkResults(chunks, 0)
+		if len(results) >= limit*2 || len(chunks) < candidates || candidates >= maxRelatedCandidates {
+			break
+		}
+	}
+
+	results = diversifyByDomain(results, limit)
+	highlightPassages(results, "")
+	return results, nil
+}
+
+// bookmarkCentroid averages the chunk embeddings of a bookmark into a single query vector
+func (s *Storage) bookmarkCentroid(bookmarkID string) ([]float32, error) {
+	centroids, err := s.BookmarkEmbeddings([]string{bookmarkID})
+	if err != nil {
+		return nil, err
+	}
+
+	centroid, ok := centroids[bookmarkID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBookmarkNotEmbedded, bookmarkID)
+	}
+	return centroid, nil
+}
+
+// BookmarkEmbeddings returns one vector per bookmark, the average of its chunk embeddings.
+// Bookmarks without embeddings are left out of the map.
+func (s *Storage) BookmarkEmbeddings(bookmarkIDs []string) (map[string][]float32, error) {
+	centroids := make(map[string][]float32)
+	if len(bookmarkIDs) == 0 {
+		return centroids, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(bookmarkIDs)), ",")
+	args := make([]interface{}, len(bookmarkIDs))
+	for i, id := range bookmarkIDs {
+		args[i] = id
+	}
+
+	rows, err := s.db.Query(`
+		SELECT c.bookmark_id, vector_extract(e.embedding)
+		FROM embeddings e
+		JOIN content c ON c.id = e.content_id
+		WHERE c.bookmark_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bookmark embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var bookmarkID, embeddingData string
+		if err := rows.Scan(&bookmarkID, &embeddingData); err != nil {
+			return nil, fmt.Errorf("failed to scan embedding: %w", err)
+		}
+
+		var embedding []float32
+		if err := json.Unmarshal([]byte(embeddingData), &embedding); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal embedding: %w", err)
+		}
+
+		centroid, ok := centroids[bookmarkID]
+		if !ok {
+			centroid = make([]float32, len(embedding))
+			centroids[bookmarkID] = centroid
+		}
+		for i := 0; i < len(centroid) && i < len(embedding); i++ {
+			centroid[i] += embedding[i]
+		}
+		counts[bookmarkID]++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Average the chunk vectors
+	for bookmarkID, centroid := range centroids {
+		for i := range centroid {
+			centroid[i] /= float32(counts[bookmarkID])
+		}
+	}
+	return centroids, nil
+}
+
+// diversifyByDomain reorders results, best first, so that each domain contributes its best result
+// before any domain contributes a second one, and keeps at most limit results
+func diversifyByDomain(results []*SearchResult, limit int) []*SearchResult {
+	type rankedResult struct {
+		result *SearchResult
+		round  int // How many better results share the domain
+	}
+
+	seen := make(map[string]int)
+	ranked := make([]rankedResult, len(results))
+	for i, result := range results {
+		domain := bookmarkDomain(result.Bookmark.URL)
+		ranked[i] = rankedResult{result: result, round: seen[domain]}
+		seen[domain]++
+	}
+
+	// Stable sort keeps the score order within each round
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].round < ranked[j].round
+	})
+
+	diversified := make([]*SearchResult, 0, min(limit, len(ranked)))
+	for _, r := range ranked {
+		if len(diversified) >= limit {
+			break
+		}
+		diversified = append(diversified, r.result)
+	}
+	return diversified
+}
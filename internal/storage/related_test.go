@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// nearEmbedding returns base with every dimension moved by up to noise
+func nearEmbedding(base []float32, noise float32) []float32 {
+	embedding := make([]float32, len(base))
+	for i, value := range base {
+		embedding[i] = value + (rand.Float32()*2-1)*noise
+	}
+	return embedding
+}
+
+// addEmbeddedBookmark creates a bookmark with content and one stored embedding per vector
+func addEmbeddedBookmark(t *testing.T, store *Storage, url string, embeddings ...[]float32) *Bookmark {
+	t.Helper()
+	bookmark := addBookmark(t, store, url, url)
+	if err := store.StoreContent(bookmark.ID, "text", "text"); err != nil {
+		t.Fatalf("Failed to store content: %v", err)
+	}
+	content, err := store.GetContent(bookmark.ID)
+	if err != nil {
+		t.Fatalf("Failed to get content: %v", err)
+	}
+	for i, embedding := range embeddings {
+		if err := store.StoreChunkEmbedding(content.ID, i, embedding, fmt.Sprintf("chunk %d", i)); err != nil {
+			t.Fatalf("Failed to store embedding: %v", err)
+		}
+	}
+	return bookmark
+}
+
+func TestRelatedBookmarks(t *testing.T) {
+	store := newTestStorage(t)
+	base := testEmbedding()
+	source := addEmbeddedBookmark(t, store, "https://source.example.com/", base, nearEmbedding(base, 0.01))
+
+	// A mirror whose many chunks are all closer than any other neighbour
+	var mirrorChunks [][]float32
+	for i := 0; i < 60; i++ {
+		mirrorChunks = append(mirrorChunks, nearEmbedding(base, 0.01))
+	}
+	mirror := addEmbeddedBookmark(t, store, "https://mirror.example.net/", mirrorChunks...)
+	var others []string
+	for i := 0; i < 6; i++ {
+		others = append(others, addEmbeddedBookmark(t, store, fmt.Sprintf("https://site%d.example.org/", i), nearEmbedding(base, 0.3)).ID)
+	}
+
+	results, err := store.RelatedBookmarks(source.ID, 5)
+	if err != nil {
+		t.Fatalf("Failed to find related bookmarks: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("Expected 5 related bookmarks despite the mirror's chunks, got %d", len(results))
+	}
+	if results[0].Bookmark.ID != mirror.ID {
+		t.Errorf("Expected the closest bookmark first, got %s", results[0].Bookmark.URL)
+	}
+	for _, result := range results {
+		if result.Bookmark.ID == source.ID {
+			t.Error("Expected the bookmark to be left out of its own related bookmarks")
+		}
+		if len(result.Passages) > maxPassagesPerResult {
+			t.Errorf("Expected at most %d passages, got %d", maxPassagesPerResult, len(result.Passages))
+		}
+	}
+
+	// Not embedded, or not there at all
+	pending := addBookmark(t, store, "https://pending.example.com/", "Pending")
+	if _, err := store.RelatedBookmarks(pending.ID, 5); !errors.Is(err, ErrBookmarkNotEmbedded) {
+		t.Errorf("Expected ErrBookmarkNotEmbedded, got %v", err)
+	}
+	if _, err := store.RelatedBookmarks("missing", 5); err == nil {
+		t.Error("Expected an error for an unknown bookmark")
+	}
+}
+
+func TestBookmarkEmbeddings(t *testing.T) {
+	store := newTestStorage(t)
+	first, second := make([]float32, VectorDimensions), make([]float32, VectorDimensions)
+	first[0], first[1] = 1, 0
+	second[0], second[1] = 0, 1
+	embedded := addEmbeddedBookmark(t, store, "https://example.com/a", first, second)
+	pending := addBookmark(t, store, "https://example.com/b", "B")
+
+	centroids, err := store.BookmarkEmbeddings([]string{embedded.ID, pending.ID})
+	if err != nil {
+		t.Fatalf("Failed to get bookmark embeddings: %v", err)
+	}
+	if _, ok := centroids[pending.ID]; ok {
+		t.Error("Expected a bookmark without embeddings to be left out")
+	}
+	centroid := centroids[embedded.ID]
+	if len(centroid) != VectorDimensions || math.Abs(float64(centroid[0])-0.5) > 1e-6 || math.Abs(float64(centroid[1])-0.5) > 1e-6 {
+		t.Errorf("Expected the average of the chunk embeddings, got %v", centroid[:2])
+	}
+}
+
+func TestDiversifyByDomain(t *testing.T) {
+	result := func(id, url string) *SearchResult {
+		return &SearchResult{Bookmark: &Bookmark{ID: id, URL: url}}
+	}
+	// Best first
+	results := []*SearchResult{
+		result("a1", "https://a.example.com/1"),
+		result("a2", "https://www.a.example.com/2"),
+		result("a3", "https://a.example.com/3"),
+		result("b1", "https://b.example.com/1"),
+		result("b2", "https://b.example.com/2"),
+		result("c1", "https://c.example.com/1"),
+	}
+
+	if got := fmt.Sprint(resultIDs(diversifyByDomain(results, 10))); got != "[a1 b1 c1 a2 b2 a3]" {
+		t.Errorf("Expected domains taken in turns, got %s", got)
+	}
+	if got := fmt.Sprint(resultIDs(diversifyByDomain(results, 4))); got != "[a1 b1 c1 a2]" {
+		t.Errorf("Expected the first 4 in turn order, got %s", got)
+	}
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrScrapeRunNotFound is returned when no bulk scrape run has been started
+var ErrScrapeRunNotFound = errors.New("scrape run not found")
+
+// ScrapeRun is a bulk scrape. Its ID is also the batch ID of its scrape jobs.
+type ScrapeRun struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"` // running, paused, completed or stopped
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ScrapeRunBookmark is the scrape status of one bookmark in a run, kept in sync with its scrape job
+type ScrapeRunBookmark struct {
+	BookmarkID string `json:"bookmark_id"`
+	URL        string `json:"url"`
+	Status     string `json:"status"` // not-scraped, in-progress, scraped or error
+	Error      string `json:"error,omitempty"`
+}
+
+// ScrapeRunProgress counts the bookmarks of a run
+type ScrapeRunProgress struct {
+	Total      int
+	Finished   int    // Scraped or failed
+	CurrentURL string // The bookmark that most recently started scraping, if any is in progress
+}
+
+// CreateScrapeRun records a running bulk scrape over the scrape jobs already enqueued in the batch
+// with the same ID, replacing any earlier run
+func (s *Storage) CreateScrapeRun(id string) (*ScrapeRun, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM scrape_run_bookmarks WHERE run_id != ?`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete earlier scrape run: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM scrape_runs WHERE id != ?`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete earlier scrape run: %w", err)
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`INSERT INTO scrape_runs (id, status, created_at, updated_at) VALUES (?, 'running', ?, ?)`,
+		id, now.Unix(), now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scrape run: %w", err)
+	}
+
+	// Jobs may already have run since they were enqueued, so start from their current state
+	_, err = tx.Exec(`
+		INSERT INTO scrape_run_bookmarks (run_id, bookmark_id, status, error, updated_at)
+		SELECT batch_id, bookmark_id,
+			CASE state
+				WHEN 'running' THEN 'in-progress'
+				WHEN 'succeeded' THEN 'scraped'
+				WHEN 'dead' THEN 'error'
+				WHEN 'cancelled' THEN 'error'
+				ELSE 'not-scraped'
+			END,
+			CASE state WHEN 'succeeded' THEN NULL WHEN 'cancelled' THEN 'Cancelled' ELSE last_error END,
+			?
+		FROM jobs WHERE batch_id = ? AND type = 'scrape' AND bookmark_id IS NOT NULL
+		ORDER BY created_at, rowid`, now.Unix(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record scrape run bookmarks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit scrape run: %w", err)
+	}
+	return &ScrapeRun{ID: id, Status: "running", CreatedAt: now, UpdatedAt: now}, nil
+}
+
+// LatestScrapeRun returns the most recently started bulk scrape
+func (s *Storage) LatestScrapeRun() (*ScrapeRun, error) {
+	run := &ScrapeRun{}
+	var createdAt, updatedAt int64
+	var finishedAt sql.NullInt64
+
+	err := s.db.QueryRow(`SELECT id, status, created_at, updated_at, finished_at FROM scrape_runs
+		ORDER BY created_at DESC, rowid DESC LIMIT 1`).Scan(&run.ID, &run.Status, &createdAt, &updatedAt, &finishedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrScrapeRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scrape run: %w", err)
+	}
+
+	run.CreatedAt = time.Unix(createdAt, 0)
+	run.UpdatedAt = time.Unix(updatedAt, 0)
+	if finishedAt.Valid {
+		finished := time.Unix(finishedAt.Int64, 0)
+		run.FinishedAt = &finished
+	}
+	return run, nil
+}
+
+// UpdateScrapeRunStatus sets a run's status, recording when it finished for completed and stopped runs
+func (s *Storage) UpdateScrapeRunStatus(id, status string) error {
+	now := time.Now().Unix()
+	result, err := s.db.Exec(`
+		UPDATE scrape_runs SET status = ?, updated_at = ?,
+			finished_at = CASE WHEN ? IN ('completed', 'stopped') THEN ? ELSE NULL END
+		WHERE id = ?`, status, now, status, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update scrape run: %w", err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return fmt.Errorf("%w: %s", ErrScrapeRunNotFound, id)
+	}
+	return nil
+}
+
+// ScrapeRunBookmarks returns the status of each bookmark in a run, in the order they were queued
+func (s *Storage) ScrapeRunBookmarks(id string) ([]*ScrapeRunBookmark, error) {
+	rows, err := s.db.Query(`
+		SELECT r.bookmark_id, COALESCE(b.url, ''), r.status, COALESCE(r.error, '')
+		FROM scrape_run_bookmarks r LEFT JOIN bookmarks b ON b.id = r.bookmark_id
+		WHERE r.run_id = ?
+		ORDER BY r.rowid`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scrape run bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	var bookmarks []*ScrapeRunBookmark
+	for rows.Next() {
+		bookmark := &ScrapeRunBookmark{}
+		if err := rows.Scan(&bookmark.BookmarkID, &bookmark.URL, &bookmark.Status, &bookmark.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan scrape run bookmark: %w", err)
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return bookmarks, rows.Err()
+}
+
+// ScrapeRunProgress counts a run's bookmarks without loading them
+func (s *Storage) ScrapeRunProgress(id string) (*ScrapeRunProgress, error) {
+	progress := &ScrapeRunProgress{}
+	err := s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(status IN ('scraped', 'error')), 0),
+			COALESCE((SELECT b.url FROM scrape_run_bookmarks r JOIN bookmarks b ON b.id = r.bookmark_id
+				WHERE r.run_id = ? AND r.status = 'in-progress'
+				ORDER BY r.updated_at DESC, r.rowid DESC LIMIT 1), '')
+		FROM scrape_run_bookmarks WHERE run_id = ?`, id, id).Scan(&progress.Total, &progress.Finished, &progress.CurrentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scrape run bookmarks: %w", err)
+	}
+	return progress, nil
+}
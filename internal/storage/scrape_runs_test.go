@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// enqueueRun adds a bookmark and a scrape job for it in the batch for each URL. Earlier URLs get
+// higher priority, so they are claimed first.
+func enqueueRun(t *testing.T, store *Storage, batchID string, urls ...string) []*Job {
+	t.Helper()
+	var jobs []*Job
+	for i, url := range urls {
+		bookmark := addBookmark(t, store, url, url)
+		jobs = append(jobs, enqueueJob(t, store, &Job{
+			Type: JobTypeScrape, BookmarkID: bookmark.ID, BatchID: batchID, Priority: len(urls) - i, MaxAttempts: 2,
+		}))
+	}
+	return jobs
+}
+
+// runStatuses returns the status and error of each bookmark in a run, keyed by bookmark ID
+func runStatuses(t *testing.T, store *Storage, runID string) map[string]*ScrapeRunBookmark {
+	t.Helper()
+	bookmarks, err := store.ScrapeRunBookmarks(runID)
+	if err != nil {
+		t.Fatalf("Failed to list scrape run bookmarks: %v", err)
+	}
+	statuses := make(map[string]*ScrapeRunBookmark)
+	for _, bookmark := range bookmarks {
+		statuses[bookmark.BookmarkID] = bookmark
+	}
+	return statuses
+}
+
+func TestCreateScrapeRunReplacesEarlierRun(t *testing.T) {
+	store := newTestStorage(t)
+
+	enqueueRun(t, store, "old", "https://example.com/old")
+	if _, err := store.CreateScrapeRun("old"); err != nil {
+		t.Fatalf("Failed to create scrape run: %v", err)
+	}
+
+	jobs := enqueueRun(t, store, "new", "https://example.com/a", "https://example.com/b", "https://example.com/c")
+	// Jobs can run before the run is recorded
+	first := claimJob(t, store, "worker", time.Minute)
+	if err := store.CompleteJob(first.ID, "worker", ""); err != nil {
+		t.Fatalf("Failed to complete job: %v", err)
+	}
+	claimJob(t, store, "worker", time.Minute)
+
+	if _, err := store.CreateScrapeRun("new"); err != nil {
+		t.Fatalf("Failed to create scrape run: %v", err)
+	}
+
+	run, err := store.LatestScrapeRun()
+	if err != nil {
+		t.Fatalf("Failed to get latest scrape run: %v", err)
+	}
+	if run.ID != "new" || run.Status != "running" {
+		t.Errorf("Expected the new run to be running, got %+v", run)
+	}
+	if bookmarks, _ := store.ScrapeRunBookmarks("old"); len(bookmarks) != 0 {
+		t.Errorf("Expected the earlier run's bookmarks to be deleted, got %d", len(bookmarks))
+	}
+
+	bookmarks, err := store.ScrapeRunBookmarks("new")
+	if err != nil {
+		t.Fatalf("Failed to list scrape run bookmarks: %v", err)
+	}
+	want := []string{"scraped", "in-progress", "not-scraped"}
+	if len(bookmarks) != len(want) {
+		t.Fatalf("Expected %d bookmarks in the run, got %d", len(want), len(bookmarks))
+	}
+	for i, bookmark := range bookmarks {
+		if bookmark.BookmarkID != jobs[i].BookmarkID || bookmark.Status != want[i] {
+			t.Errorf("Expected bookmark %d to be %s in queue order, got %+v", i, want[i], bookmark)
+		}
+	}
+
+	progress, err := store.ScrapeRunProgress("new")
+	if err != nil {
+		t.Fatalf("Failed to count scrape run progress: %v", err)
+	}
+	if progress.Total != 3 || progress.Finished != 1 || progress.CurrentURL != "https://example.com/b" {
+		t.Errorf("Expected 1 of 3 finished with b in progress, got %+v", progress)
+	}
+}
+
+func TestScrapeRunProgressFollowsJobState(t *testing.T) {
+	store := newTestStorage(t)
+	jobs := enqueueRun(t, store, "run", "https://example.com/a", "https://example.com/b", "https://example.com/c")
+	if _, err := store.CreateScrapeRun("run"); err != nil {
+		t.Fatalf("Failed to create scrape run: %v", err)
+	}
+
+	// A job outside the run's batch leaves it alone
+	outside := addBookmark(t, store, "https://example.org/", "Outside")
+	enqueueJob(t, store, &Job{Type: JobTypeScrape, BookmarkID: outside.ID, Priority: -1})
+
+	expect := func(job *Job, status, message string) {
+		t.Helper()
+		bookmark := runStatuses(t, store, "run")[job.BookmarkID]
+		if bookmark.Status != status || bookmark.Error != message {
+			t.Errorf("Expected %s with error %q, got %s with error %q", status, message, bookmark.Status, bookmark.Error)
+		}
+	}
+
+	claimed := claimJob(t, store, "worker", time.Minute)
+	expect(jobs[0], "in-progress", "")
+	if err := store.RetryJob(claimed.ID, "worker", "timeout", time.Now()); err != nil {
+		t.Fatalf("Failed to retry job: %v", err)
+	}
+	expect(jobs[0], "not-scraped", "timeout")
+
+	claimed = claimJob(t, store, "worker", time.Minute)
+	if err := store.DeadLetterJob(claimed.ID, "worker", "not found"); err != nil {
+		t.Fatalf("Failed to dead-letter job: %v", err)
+	}
+	expect(jobs[0], "error", "not found")
+
+	claimed = claimJob(t, store, "worker", time.Minute)
+	if err := store.CompleteJob(claimed.ID, "worker", ""); err != nil {
+		t.Fatalf("Failed to complete job: %v", err)
+	}
+	expect(jobs[1], "scraped", "")
+
+	if _, err := store.CancelJob(jobs[2].ID); err != nil {
+		t.Fatalf("Failed to cancel job: %v", err)
+	}
+	expect(jobs[2], "error", "Cancelled")
+
+	if bookmarks := runStatuses(t, store, "run"); len(bookmarks) != 3 || bookmarks[outside.ID] != nil {
+		t.Errorf("Expected only the run's bookmarks to be tracked, got %d", len(bookmarks))
+	}
+}
+
+func TestSuspendBatchJobs(t *testing.T) {
+	store := newTestStorage(t)
+	jobs := enqueueRun(t, store, "run", "https://example.com/a", "https://example.com/b", "https://example.com/c")
+	other := enqueueRun(t, store, "other", "https://example.org/")
+	if _, err := store.CreateScrapeRun("run"); err != nil {
+		t.Fatalf("Failed to create scrape run: %v", err)
+	}
+
+	completed := claimJob(t, store, "worker", time.Minute)
+	if err := store.CompleteJob(completed.ID, "worker", ""); err != nil {
+		t.Fatalf("Failed to complete job: %v", err)
+	}
+	// Left running by a process that exited
+	claimJob(t, store, "worker", time.Minute)
+
+	suspended, err := store.SuspendBatchJobs("run")
+	if err != nil {
+		t.Fatalf("Failed to suspend batch jobs: %v", err)
+	}
+	if suspended != 2 {
+		t.Errorf("Expected the running and queued jobs to be suspended, got %d", suspended)
+	}
+
+	running, _ := store.GetJob(jobs[1].ID)
+	if running.State != JobPaused || running.Attempts != 0 || running.LeaseOwner != "" || running.LeaseExpiresAt != nil {
+		t.Errorf("Expected the running job paused without its lease or attempt, got %+v", running)
+	}
+	if queued, _ := store.GetJob(jobs[2].ID); queued.State != JobPaused {
+		t.Errorf("Expected the queued job to be paused, got %s", queued.State)
+	}
+	if done, _ := store.GetJob(jobs[0].ID); done.State != JobSucceeded {
+		t.Errorf("Expected the finished job to be left alone, got %s", done.State)
+	}
+	if job, _ := store.GetJob(other[0].ID); job.State != JobQueued {
+		t.Errorf("Expected other batches to be left alone, got %s", job.State)
+	}
+
+	statuses := runStatuses(t, store, "run")
+	if statuses[jobs[0].BookmarkID].Status != "scraped" || statuses[jobs[1].BookmarkID].Status != "not-scraped" {
+		t.Errorf("Expected the run to show the interrupted bookmark as not scraped, got %+v and %+v",
+			statuses[jobs[0].BookmarkID], statuses[jobs[1].BookmarkID])
+	}
+}
+
+func TestUpdateScrapeRunStatus(t *testing.T) {
+	store := newTestStorage(t)
+	if _, err := store.LatestScrapeRun(); !errors.Is(err, ErrScrapeRunNotFound) {
+		t.Errorf("Expected ErrScrapeRunNotFound before any run, got %v", err)
+	}
+
+	enqueueRun(t, store, "run", "https://example.com/a")
+	if _, err := store.CreateScrapeRun("run"); err != nil {
+		t.Fatalf("Failed to create scrape run: %v", err)
+	}
+
+	if err := store.UpdateScrapeRunStatus("run", "paused"); err != nil {
+		t.Fatalf("Failed to pause scrape run: %v", err)
+	}
+	if run, _ := store.LatestScrapeRun(); run.Status != "paused" || run.FinishedAt != nil {
+		t.Errorf("Expected a paused run without a finish time, got %+v", run)
+	}
+
+	if err := store.UpdateScrapeRunStatus("run", "completed"); err != nil {
+		t.Fatalf("Failed to complete scrape run: %v", err)
+	}
+	if run, _ := store.LatestScrapeRun(); run.Status != "completed" || run.FinishedAt == nil {
+		t.Errorf("Expected a completed run with a finish time, got %+v", run)
+	}
+
+	if err := store.UpdateScrapeRunStatus("missing", "stopped"); !errors.Is(err, ErrScrapeRunNotFound) {
+		t.Errorf("Expected ErrScrapeRunNotFound for an unknown run, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// filteredSemanticCandidates is the minimum number of vector index neighbours fetched when
+// filters are set, since filtering happens after the index lookup
+const filteredSemanticCandidates = 1000
+
+// SearchOptions defines filtering options for bookmark searches. Zero values are ignored.
+type SearchOptions struct {
+	Status        string
+	FolderPath    string   // Matches the folder and its subfolders
+	Tags          []string // Bookmarks must carry every tag
+	Categories    []string // Bookmarks must belong to at least one category
+	Domain        string   // Matches the host and its subdomains
+	Phrases       []string // Title, description or content must contain every phrase
+	ExcludedTerms []string // Title, description and content must contain none of these words or phrases
+	ExcludeIDs    []string // Bookmarks left out of the results
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	ScrapedAfter  time.Time
+	ScrapedBefore time.Time
+	Limit         int
+}
+
+// HasFilters reports whether any filter is set
+func (opts SearchOptions) HasFilters() bool {
+	return opts.Status != "" || opts.FolderPath != "" || len(opts.Tags) > 0 || len(opts.Categories) > 0 ||
+		opts.Domain != "" || len(opts.Phrases) > 0 || len(opts.ExcludedTerms) > 0 || len(opts.ExcludeIDs) > 0 ||
+		!opts.CreatedAfter.IsZero() || !opts.CreatedBefore.IsZero() ||
+		!opts.ScrapedAfter.IsZero() || !opts.ScrapedBefore.IsZero()
+}
+
+// filterClause renders the filters as SQL conditions on the bookmarks table aliased as b.
+// The returned clause starts with " AND" so it can be appended to an existing WHERE.
+func (opts SearchOptions) filterClause() (string, []interface{}) {
+	var clause strings.Builder
+	var args []interface{}
+
+	if opts.Status != "" {
+		clause.WriteString(" AND b.status = ?")
+		args = append(args, opts.Status)
+	}
+
+	if folderPath := strings.Trim(opts.FolderPath, "/"); folderPath != "" {
+		clause.WriteString(` AND (b.folder_path = ? OR b.folder_path LIKE ? ESCAPE '\')`)
+		args = append(args, folderPath, escapeLike(folderPath)+"/%")
+	}
+
+	for _, tag := range opts.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		// Tags live in the bookmark's JSON column and in the normalized tables written by categorization
+		clause.WriteString(`
+			AND (EXISTS (SELECT 1 FROM json_each(COALESCE(b.tags, '[]')) WHERE lower(json_each.value) = lower(?))
+			  OR EXISTS (SELECT 1 FROM bookmark_tags bt JOIN tags t ON t.id = bt.tag_id
+			             WHERE bt.bookmark_id = b.id AND lower(t.name) = lower(?)))`)
+		args = append(args, tag, tag)
+	}
+
+	if len(opts.Categories) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("lower(?),", len(opts.Categories)), ",")
+		clause.WriteString(`
+			AND EXISTS (SELECT 1 FROM bookmark_categories bc JOIN categories cat ON cat.id = bc.category_id
+			            WHERE bc.bookmark_id = b.id AND lower(cat.name) IN (` + placeholders + `))`)
+		for _, category := range opts.Categories {
+			args = append(args, strings.TrimSpace(category))
+		}
+	}
+
+	if domain := normalizeDomain(opts.Domain); domain != "" {
+		clause.WriteString(` AND (b.domain = ? OR b.domain LIKE ? ESCAPE '\')`)
+		args = append(args, domain, "%."+escapeLike(domain))
+	}
+
+	for _, phrase := range opts.Phrases {
+		if match := ftsPhrase(phrase); match != "" {
+			clause.WriteString(" AND b.id IN (" + ftsMatchingBookmarks + ")")
+			args = append(args, match, match)
+		}
+	}
+
+	for _, term := range opts.ExcludedTerms {
+		if match := ftsPhrase(term); match != "" {
+			clause.WriteString(" AND b.id NOT IN (" + ftsMatchingBookmarks + ")")
+			args = append(args, match, match)
+		}
+	}
+
+	if len(opts.ExcludeIDs) > 0 {
+		clause.WriteString(" AND b.id NOT IN (" + strings.TrimSuffix(strings.Repeat("?,", len(opts.ExcludeIDs)), ",") + ")")
+		for _, id := range opts.ExcludeIDs {
+			args = append(args, id)
+		}
+	}
+
+	if !opts.CreatedAfter.IsZero() {
+		clause.WriteString(" AND b.created_at >= ?")
+		args = append(args, opts.CreatedAfter)
+	}
+	if !opts.CreatedBefore.IsZero() {
+		clause.WriteString(" AND b.created_at <= ?")
+		args = append(args, opts.CreatedBefore)
+	}
+	if !opts.ScrapedAfter.IsZero() {
+		clause.WriteString(" AND b.scraped_at >= ?")
+		args = append(args, opts.ScrapedAfter)
+	}
+	if !opts.ScrapedBefore.IsZero() {
+		clause.WriteString(" AND b.scraped_at <= ?")
+		args = append(args, opts.ScrapedBefore)
+	}
+
+	return clause.String(), args
+}
+
+// ftsMatchingBookmarks selects the bookmarks whose title, description or content match an FTS5
+// expression; it takes the expression twice
+const ftsMatchingBookmarks = `
+	SELECT bookmark_id FROM bookmarks_fts WHERE bookmarks_fts MATCH ?
+	UNION
+	SELECT fc.bookmark_id FROM content_fts JOIN content fc ON fc.id = content_fts.rowid WHERE content_fts MATCH ?`
+
+// ftsMatchExpression turns free text into an FTS5 expression requiring every word. Words are
+// quoted so operators and punctuation in user input cannot cause FTS5 syntax errors.
+// It returns "" when the text has no searchable words.
+func ftsMatchExpression(text string) string {
+	words := ftsWords(text)
+	for i, word := range words {
+		words[i] = `"` + word + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// ftsPhrase turns text into a quoted FTS5 phrase, or "" when it has no searchable words
+func ftsPhrase(text string) string {
+	words := ftsWords(text)
+	if len(words) == 0 {
+		return ""
+	}
+	return `"` + strings.Join(words, " ") + `"`
+}
+
+// ftsWords splits text into words the way the FTS5 unicode61 tokenizer does
+func ftsWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// bookmarkDomain returns the value stored in bookmarks.domain for a URL
+func bookmarkDomain(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	return normalizeDomain(parsed.Hostname())
+}
+
+// normalizeDomain lowercases a host and strips a leading "www."
+func normalizeDomain(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	return strings.TrimPrefix(host, "www.")
+}
+
+// bestResultPerBookmark keeps the highest scoring result of each bookmark, ordered by score
+func bestResultPerBookmark(results []*SearchResult, limit int) []*SearchResult {
+	best := make(map[string]*SearchResult)
+	for _, result := range results {
+		if existing, ok := best[result.Bookmark.ID]; !ok || result.RelevanceScore > existing.RelevanceScore {
+			best[result.Bookmark.ID] = result
+		}
+	}
+
+	deduped := make([]*SearchResult, 0, len(best))
+	for _, result := range best {
+		deduped = append(deduped, result)
+	}
+	sort.SliceStable(deduped, func(i, j int) bool {
+		if deduped[i].RelevanceScore != deduped[j].RelevanceScore {
+			return deduped[i].RelevanceScore > deduped[j].RelevanceScore
+		}
+		return deduped[i].Bookmark.ID < deduped[j].Bookmark.ID
+	})
+
+	if len(deduped) > limit {
+		deduped = deduped[:limit]
+	}
+	return deduped
+}
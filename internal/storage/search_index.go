@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SearchIndexStats reports the result of a full-text index rebuild
+type SearchIndexStats struct {
+	BookmarksIndexed int           `json:"bookmarks_indexed"`
+	ContentIndexed   int           `json:"content_indexed"`
+	Duration         time.Duration `json:"duration"`
+}
+
+// RebuildSearchIndex repopulates bookmarks_fts and content_fts from their source tables.
+// Triggers keep the indexes in sync during normal operation; this repairs any drift.
+func (s *Storage) RebuildSearchIndex(ctx context.Context) (*SearchIndexStats, error) {
+	start := time.Now()
+	stats := &SearchIndexStats{}
+
+	err := s.retryWithBackoff(func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM bookmarks_fts"); err != nil {
+			return fmt.Errorf("failed to clear bookmarks FTS: %w", err)
+		}
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO bookmarks_fts (bookmark_id, title, description)
+			SELECT id, COALESCE(title, ''), COALESCE(description, '') FROM bookmarks
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to rebuild bookmarks FTS: %w", err)
+		}
+		bookmarksIndexed, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM content_fts"); err != nil {
+			return fmt.Errorf("failed to clear content FTS: %w", err)
+		}
+		result, err = tx.ExecContext(ctx, `
+			INSERT INTO content_fts (rowid, clean_text)
+			SELECT id, COALESCE(clean_text, '') FROM content
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to rebuild content FTS: %w", err)
+		}
+		contentIndexed, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit search index rebuild: %w", err)
+		}
+
+		stats.BookmarksIndexed = int(bookmarksIndexed)
+		stats.ContentIndexed = int(contentIndexed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the index b-trees now that they have been rewritten
+	for _, table := range []string{"bookmarks_fts", "content_fts"} {
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s(%s) VALUES('optimize')", table, table)); err != nil {
+			return nil, fmt.Errorf("failed to optimize %s: %w", table, err)
+		}
+	}
+
+	stats.Duration = time.Since(start)
+	return stats, nil
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,6 +13,19 @@ import (
 	_ "github.com/tursodatabase/go-libsql"
 )
 
+// ErrBookmarkNotFound is returned when a bookmark does not exist
+var ErrBookmarkNotFound = errors.New("bookmark not found")
+
+// DuplicateBookmarkError is returned when a bookmark with the same URL already exists
+type DuplicateBookmarkError struct {
+	URL        string
+	BookmarkID string // ID of the existing bookmark
+}
+
+func (e *DuplicateBookmarkError) Error() string {
+	return fmt.Sprintf("bookmark for %s already exists with ID %s", e.URL, e.BookmarkID)
+}
+
 // Storage represents the database storage layer
 type Storage struct {
 	db *sql.DB
@@ -58,15 +72,41 @@ type Content struct {
 
 // SearchResult represents a search result with relevance score
 type SearchResult struct {
-	Bookmark       *Bookmark `json:"bookmark"`
-	Content        *Content  `json:"content,omitempty"`
-	RelevanceScore float64   `json:"relevance_score"`
-	SearchType     string    `json:"search_type"`
-	MatchedSnippet string    `json:"matched_snippet,omitempty"`
+	Bookmark       *Bookmark  `json:"bookmark"`
+	Content        *Content   `json:"content,omitempty"`
+	RelevanceScore float64    `json:"relevance_score"`
+	SearchType     string     `json:"search_type"`
+	MatchedSnippet string     `json:"matched_snippet,omitempty"`
+	Passages       []*Passage `json:"passages,omitempty"` // Best matching content chunks, semantic matches only
 }
 
-// New creates a new Storage instance with a local libSQL database
+// ContentChunk represents a single embedded chunk of a bookmark's content
+type ContentChunk struct {
+	BookmarkID string  `json:"bookmark_id"`
+	ContentID  int     `json:"content_id"`
+	ChunkIndex int     `json:"chunk_index"`
+	Text       string  `json:"text"`
+	Score      float64 `json:"score"`
+	Fallback   bool    `json:"fallback,omitempty"` // Scraped text of a bookmark without embeddings, not an embedded chunk
+}
+
+// New creates a new Storage instance with a local libSQL database and applies pending migrations
 func New(dbPath string) (*Storage, error) {
+	storage, err := Open(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storage.Migrate(); err != nil {
+		storage.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return storage, nil
+}
+
+// Open opens a local libSQL database without applying migrations
+func Open(dbPath string) (*Storage, error) {
 	if dbPath == "" {
 		dbPath = "file:bookmarks.db"
 	}
@@ -86,18 +126,7 @@ func New(dbPath string) (*Storage, error) {
 	db.SetMaxIdleConns(1)     // Keep one idle connection
 	db.SetConnMaxLifetime(0)  // Don't expire connections
 
-	storage := &Storage{db: db}
-
-	if err := storage.initializeSchema(); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
-	}
-
-	// Apply categorization migration
-	if err := storage.applyCategorization(); err != nil {
-		return nil, fmt.Errorf("failed to apply categorization migration: %w", err)
-	}
-
-	return storage, nil
+	return &Storage{db: db}, nil
 }
 
 // retryWithBackoff executes a function with exponential backoff for database lock errors
@@ -138,95 +167,6 @@ func (s *Storage) GetDB() *sql.DB {
 	return s.db
 }
 
-// initializeSchema creates all necessary tables and indexes
-func (s *Storage) initializeSchema() error {
-	schemas := []string{
-		// Folders table for hierarchical structure
-		`CREATE TABLE IF NOT EXISTS folders (
-			id TEXT PRIMARY KEY,
-			name TEXT NOT NULL,
-			parent_id TEXT,
-			path TEXT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (parent_id) REFERENCES folders(id) ON DELETE CASCADE
-		)`,
-
-		// Bookmarks table
-		`CREATE TABLE IF NOT EXISTS bookmarks (
-			id TEXT PRIMARY KEY,
-			url TEXT UNIQUE NOT NULL,
-			title TEXT,
-			description TEXT,
-			status TEXT DEFAULT 'pending' CHECK(status IN ('pending', 'completed', 'failed')),
-			imported_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			scraped_at TIMESTAMP,
-			folder_id TEXT,
-			folder_path TEXT,
-			favicon_url TEXT,
-			tags TEXT, -- JSON array of tags
-			FOREIGN KEY (folder_id) REFERENCES folders(id) ON DELETE SET NULL
-		)`,
-
-		// Content table
-		`CREATE TABLE IF NOT EXISTS content (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			bookmark_id TEXT NOT NULL,
-			raw_content TEXT,
-			clean_text TEXT,
-			scraped_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			content_type TEXT DEFAULT 'text/html',
-			FOREIGN KEY (bookmark_id) REFERENCES bookmarks(id) ON DELETE CASCADE
-		)`,
-
-		// Embeddings table with vector support
-		`CREATE TABLE IF NOT EXISTS embeddings (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			content_id INTEGER NOT NULL,
-			chunk_index INTEGER DEFAULT 0,
-			chunk_text TEXT,
-			embedding BLOB,
-			model_version TEXT DEFAULT 'text-embedding-3-small',
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (content_id) REFERENCES content(id) ON DELETE CASCADE
-		)`,
-
-		// FTS5 virtual table for bookmarks full-text search
-		`CREATE VIRTUAL TABLE IF NOT EXISTS bookmarks_fts USING fts5(
-			title, 
-			description
-		)`,
-
-		// FTS5 virtual table for content full-text search
-		`CREATE VIRTUAL TABLE IF NOT EXISTS content_fts USING fts5(
-			clean_text
-		)`,
-
-		// Create standard indexes for performance
-		`CREATE INDEX IF NOT EXISTS idx_folders_parent_id ON folders(parent_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_folders_path ON folders(path)`,
-		`CREATE INDEX IF NOT EXISTS idx_bookmarks_status ON bookmarks(status)`,
-		`CREATE INDEX IF NOT EXISTS idx_bookmarks_url ON bookmarks(url)`,
-		`CREATE INDEX IF NOT EXISTS idx_bookmarks_folder_id ON bookmarks(folder_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_content_bookmark_id ON content(bookmark_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_embeddings_content_id ON embeddings(content_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_embeddings_content_chunk ON embeddings(content_id, chunk_index)`,
-	}
-
-	for _, schema := range schemas {
-		if _, err := s.db.Exec(schema); err != nil {
-			return fmt.Errorf("failed to execute schema: %s, error: %w", schema, err)
-		}
-	}
-
-	// Note: We'll manually manage FTS table synchronization to avoid trigger conflicts
-	// FTS tables will be updated explicitly when content changes
-
-	return nil
-}
-
 // ImportBookmarks imports bookmarks and folders from a parse result
 func (s *Storage) ImportBookmarks(parseResult *parsers.ParseResult) (*ImportResult, error) {
 	tx, err := s.db.Begin()
@@ -291,9 +231,9 @@ func (s *Storage) ImportBookmarks(parseResult *parsers.ParseResult) (*ImportResu
 		// Insert bookmark
 		folderPath := strings.Join(bookmark.FolderPath, "/")
 		_, err = tx.Exec(`
-			INSERT INTO bookmarks (id, url, title, description, folder_id, folder_path, favicon_url, tags, imported_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			bookmarkID, bookmark.URL, bookmark.Title, "", folderID, folderPath, bookmark.Icon, tagsJSON, bookmark.DateAdded)
+			INSERT INTO bookmarks (id, url, title, description, folder_id, folder_path, favicon_url, tags, domain, imported_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			bookmarkID, bookmark.URL, bookmark.Title, "", folderID, folderPath, bookmark.Icon, tagsJSON, bookmarkDomain(bookmark.URL), bookmark.DateAdded)
 
 		if err != nil {
 			result.Failed++
@@ -327,6 +267,75 @@ func (s *Storage) ImportBookmarks(parseResult *parsers.ParseResult) (*ImportResu
 	return result, nil
 }
 
+// CreateBookmark inserts a single pending bookmark. The URL unique constraint decides duplicates,
+// which are reported as a *DuplicateBookmarkError.
+func (s *Storage) CreateBookmark(bookmark *Bookmark) error {
+	if bookmark.ID == "" {
+		bookmark.ID = uuid.New().String()
+	}
+	if bookmark.Tags == nil {
+		bookmark.Tags = []string{}
+	}
+	now := time.Now()
+	bookmark.Status = "pending"
+	bookmark.ImportedAt = now
+	bookmark.CreatedAt = now
+	bookmark.UpdatedAt = now
+
+	tagsJSON, err := json.Marshal(bookmark.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	return s.retryWithBackoff(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		bookmark.FolderID = nil
+		if bookmark.FolderPath != "" {
+			var folderID string
+			err := tx.QueryRow("SELECT id FROM folders WHERE path = ?", bookmark.FolderPath).Scan(&folderID)
+			if err != nil && err != sql.ErrNoRows {
+				return fmt.Errorf("failed to look up folder: %w", err)
+			}
+			if err == nil {
+				bookmark.FolderID = &folderID
+			}
+		}
+
+		result, err := tx.Exec(`
+			INSERT INTO bookmarks (id, url, title, description, status, folder_id, folder_path, favicon_url, tags, domain, imported_at, created_at, updated_at)
+			VALUES (?, ?, ?, ?, 'pending', ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(url) DO NOTHING`,
+			bookmark.ID, bookmark.URL, bookmark.Title, bookmark.Description, bookmark.FolderID, bookmark.FolderPath,
+			bookmark.FaviconURL, string(tagsJSON), bookmarkDomain(bookmark.URL), now, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to insert bookmark: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			var existingID string
+			if err := tx.QueryRow("SELECT id FROM bookmarks WHERE url = ?", bookmark.URL).Scan(&existingID); err != nil {
+				return fmt.Errorf("failed to look up existing bookmark: %w", err)
+			}
+			return &DuplicateBookmarkError{URL: bookmark.URL, BookmarkID: existingID}
+		}
+
+		if err := s.replaceBookmarkTags(tx, bookmark.ID, bookmark.Tags); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
 // createFolderHierarchy recursively creates folder hierarchy
 func (s *Storage) createFolderHierarchy(tx *sql.Tx, folder *parsers.BookmarkFolder, parentID *string, folderMap map[string]string) error {
 	folderID := uuid.New().String()
@@ -373,7 +382,7 @@ func (s *Storage) GetBookmark(bookmarkID string) (*Bookmark, error) {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("bookmark with ID %s not found", bookmarkID)
+			return nil, fmt.Errorf("%w: %s", ErrBookmarkNotFound, bookmarkID)
 		}
 		return nil, fmt.Errorf("failed to get next row: %w", err)
 	}
@@ -426,6 +435,122 @@ func (s *Storage) ListBookmarks() ([]*Bookmark, error) {
 	return bookmarks, nil
 }
 
+// BookmarkQuery selects one page of bookmarks
+type BookmarkQuery struct {
+	Page   int    // 1-based page number
+	Limit  int    // Page size
+	Filter string // Case-insensitive substring matched against title and URL
+	Sort   string // "field:order", e.g. "created_at:desc"
+}
+
+// BookmarkPage is a page of bookmarks along with the total number of matches
+type BookmarkPage struct {
+	Bookmarks  []*Bookmark
+	TotalItems int
+}
+
+// ErrInvalidSort is returned when a bookmark query uses an unsupported sort option
+var ErrInvalidSort = errors.New("invalid sort option")
+
+// bookmarkSortColumns maps the sortable API fields to their SQL expressions
+var bookmarkSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title COLLATE NOCASE",
+}
+
+// bookmarkOrderBy validates a "field:order" sort option and returns the ORDER BY clause
+func bookmarkOrderBy(sort string) (string, error) {
+	if sort == "" {
+		sort = "created_at:desc"
+	}
+
+	field, order, _ := strings.Cut(sort, ":")
+	column, ok := bookmarkSortColumns[field]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown field %q", ErrInvalidSort, field)
+	}
+
+	switch strings.ToLower(order) {
+	case "", "asc":
+		order = "ASC"
+	case "desc":
+		order = "DESC"
+	default:
+		return "", fmt.Errorf("%w: unknown order %q", ErrInvalidSort, order)
+	}
+
+	// id breaks ties so pages are stable when sort values repeat
+	return fmt.Sprintf("%s %s, id %s", column, order, order), nil
+}
+
+// escapeLike escapes LIKE wildcards so the filter matches literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// QueryBookmarks returns one page of bookmarks with filtering, sorting and pagination done in SQL
+func (s *Storage) QueryBookmarks(query BookmarkQuery) (*BookmarkPage, error) {
+	orderBy, err := bookmarkOrderBy(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 20
+	}
+
+	where := ""
+	var args []interface{}
+	if filter := strings.TrimSpace(query.Filter); filter != "" {
+		pattern := "%" + escapeLike(filter) + "%"
+		where = ` WHERE (title LIKE ? ESCAPE '\' OR url LIKE ? ESCAPE '\')`
+		args = append(args, pattern, pattern)
+	}
+
+	page := &BookmarkPage{Bookmarks: []*Bookmark{}}
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM bookmarks"+where, args...).Scan(&page.TotalItems); err != nil {
+		return nil, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	rows, err := s.db.Query(`SELECT id, url, COALESCE(title, ''), COALESCE(description, ''), status, imported_at, created_at, updated_at,
+			  scraped_at, folder_id, COALESCE(folder_path, ''), COALESCE(favicon_url, ''), COALESCE(tags, '[]')
+			  FROM bookmarks`+where+" ORDER BY "+orderBy+" LIMIT ? OFFSET ?",
+		append(args, query.Limit, (query.Page-1)*query.Limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		bookmark := &Bookmark{}
+		var tagsJSON string
+		err := rows.Scan(
+			&bookmark.ID, &bookmark.URL, &bookmark.Title, &bookmark.Description, &bookmark.Status,
+			&bookmark.ImportedAt, &bookmark.CreatedAt, &bookmark.UpdatedAt,
+			&bookmark.ScrapedAt, &bookmark.FolderID, &bookmark.FolderPath, &bookmark.FaviconURL, &tagsJSON,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bookmark: %w", err)
+		}
+
+		if tagsJSON != "" {
+			if err := json.Unmarshal([]byte(tagsJSON), &bookmark.Tags); err != nil {
+				bookmark.Tags = []string{}
+			}
+		}
+
+		page.Bookmarks = append(page.Bookmarks, bookmark)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	return page, nil
+}
+
 // GetBookmarksWithFolders retrieves all bookmarks organized by folders
 func (s *Storage) GetBookmarksWithFolders() ([]*BookmarkFolder, error) {
 	// Get all folders
@@ -553,21 +678,136 @@ func (s *Storage) UpdateBookmark(bookmark *Bookmark) error {
 		return fmt.Errorf("bookmark with ID %s not found", bookmark.ID)
 	}
 
-	// Get the bookmark's rowid to update FTS
-	var rowid int64
-	err = tx.QueryRow("SELECT rowid FROM bookmarks WHERE id = ?", bookmark.ID).Scan(&rowid)
+	return tx.Commit()
+}
+
+// BookmarkUpdate holds user-editable bookmark fields; nil fields are left unchanged
+type BookmarkUpdate struct {
+	Title       *string
+	Description *string
+	FolderPath  *string
+	Tags        *[]string
+}
+
+// UpdateBookmarkMetadata applies a user edit to a bookmark. It reports whether the searchable text (title or description) changed.
+func (s *Storage) UpdateBookmarkMetadata(bookmarkID string, update BookmarkUpdate) (*Bookmark, bool, error) {
+	bookmark, err := s.GetBookmark(bookmarkID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	textChanged := false
+	if update.Title != nil && *update.Title != bookmark.Title {
+		bookmark.Title = *update.Title
+		textChanged = true
+	}
+	if update.Description != nil && *update.Description != bookmark.Description {
+		bookmark.Description = *update.Description
+		textChanged = true
+	}
+	if update.FolderPath != nil {
+		bookmark.FolderPath = *update.FolderPath
+	}
+	if update.Tags != nil {
+		bookmark.Tags = *update.Tags
+	}
+
+	tagsJSON, err := json.Marshal(bookmark.Tags)
 	if err != nil {
-		return fmt.Errorf("failed to get bookmark rowid: %w", err)
+		return nil, false, fmt.Errorf("failed to marshal tags: %w", err)
 	}
+	bookmark.UpdatedAt = time.Now()
 
-	// Update or insert into FTS table
-	_, err = tx.Exec("INSERT OR REPLACE INTO bookmarks_fts(rowid, title, description) VALUES (?, ?, ?)",
-		rowid, bookmark.Title, bookmark.Description)
+	err = s.retryWithBackoff(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		// Link the bookmark to the folder at its new path, if that folder exists
+		var folderID *string
+		if update.FolderPath != nil {
+			var id string
+			err := tx.QueryRow("SELECT id FROM folders WHERE path = ?", bookmark.FolderPath).Scan(&id)
+			if err != nil && err != sql.ErrNoRows {
+				return fmt.Errorf("failed to look up folder: %w", err)
+			}
+			if err == nil {
+				folderID = &id
+			}
+		} else {
+			folderID = bookmark.FolderID
+		}
+
+		result, err := tx.Exec(`
+			UPDATE bookmarks
+			SET title = ?, description = ?, folder_path = ?, folder_id = ?, tags = ?, updated_at = ?
+			WHERE id = ?
+		`, bookmark.Title, bookmark.Description, bookmark.FolderPath, folderID, string(tagsJSON), bookmark.UpdatedAt, bookmarkID)
+		if err != nil {
+			return fmt.Errorf("failed to update bookmark: %w", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		} else if rowsAffected == 0 {
+			return fmt.Errorf("%w: %s", ErrBookmarkNotFound, bookmarkID)
+		}
+		bookmark.FolderID = folderID
+
+		if update.Tags != nil {
+			if err := s.replaceBookmarkTags(tx, bookmarkID, bookmark.Tags); err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update bookmarks FTS: %w", err)
+		return nil, false, err
 	}
 
-	return tx.Commit()
+	return bookmark, textChanged, nil
+}
+
+// replaceBookmarkTags swaps the normalized tag links of a bookmark, keeping tag usage counts in step
+func (s *Storage) replaceBookmarkTags(tx *sql.Tx, bookmarkID string, tags []string) error {
+	if err := releaseBookmarkTags(tx, bookmarkID); err != nil {
+		return err
+	}
+
+	for _, tagName := range tags {
+		tagName = strings.TrimSpace(tagName)
+		if tagName == "" {
+			continue
+		}
+		tagID, err := s.getOrCreateTag(tx, tagName)
+		if err != nil {
+			return fmt.Errorf("failed to create tag %s: %w", tagName, err)
+		}
+		if _, err := tx.Exec("INSERT OR IGNORE INTO bookmark_tags (bookmark_id, tag_id) VALUES (?, ?)", bookmarkID, tagID); err != nil {
+			return fmt.Errorf("failed to link tag %s: %w", tagName, err)
+		}
+	}
+
+	return nil
+}
+
+// releaseBookmarkTags unlinks all tags from a bookmark and decrements their usage counts
+func releaseBookmarkTags(tx *sql.Tx, bookmarkID string) error {
+	_, err := tx.Exec(`
+		UPDATE tags SET usage_count = usage_count - 1
+		WHERE usage_count > 0 AND id IN (SELECT tag_id FROM bookmark_tags WHERE bookmark_id = ?)
+	`, bookmarkID)
+	if err != nil {
+		return fmt.Errorf("failed to update tag usage: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM bookmark_tags WHERE bookmark_id = ?", bookmarkID); err != nil {
+		return fmt.Errorf("failed to delete bookmark tags: %w", err)
+	}
+
+	return nil
 }
 
 // ImportResult represents the result of an import operation
@@ -589,33 +829,35 @@ func (s *Storage) StoreContent(bookmarkID string, rawContent string, cleanText s
 	}
 	defer tx.Rollback()
 
-	// Delete any existing content for this bookmark
-	_, err = tx.Exec("DELETE FROM content WHERE bookmark_id = ?", bookmarkID)
-	if err != nil {
-		// Log but don't fail - the content might not exist yet
+	// Delete any existing content for this bookmark, along with its search index entries and embeddings
+	if err := deleteBookmarkContent(tx, bookmarkID); err != nil {
+		return err
 	}
 
 	// Insert new content
 	query := `INSERT INTO content (bookmark_id, raw_content, clean_text, scraped_at, content_type) 
 	          VALUES (?, ?, ?, CURRENT_TIMESTAMP, 'text/html')`
-	result, err := tx.Exec(query, bookmarkID, rawContent, cleanText)
+	// content_fts is maintained by triggers
+	_, err = tx.Exec(query, bookmarkID, rawContent, cleanText)
 	if err != nil {
 		return fmt.Errorf("failed to store content: %w", err)
 	}
 
-	// Get the content ID for FTS update
-	contentID, err := result.LastInsertId()
+	return tx.Commit()
+}
+
+// deleteBookmarkContent removes a bookmark's content and its embeddings; triggers clean up content_fts
+func deleteBookmarkContent(tx *sql.Tx, bookmarkID string) error {
+	_, err := tx.Exec("DELETE FROM embeddings WHERE content_id IN (SELECT id FROM content WHERE bookmark_id = ?)", bookmarkID)
 	if err != nil {
-		return fmt.Errorf("failed to get content ID: %w", err)
+		return fmt.Errorf("failed to delete embeddings: %w", err)
 	}
 
-	// Update content FTS table
-	_, err = tx.Exec("INSERT INTO content_fts(rowid, clean_text) VALUES (?, ?)", contentID, cleanText)
-	if err != nil {
-		return fmt.Errorf("failed to update content FTS: %w", err)
+	if _, err := tx.Exec("DELETE FROM content WHERE bookmark_id = ?", bookmarkID); err != nil {
+		return fmt.Errorf("failed to delete content: %w", err)
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // GetContent retrieves content by bookmark ID
@@ -646,14 +888,38 @@ func (s *Storage) StoreEmbedding(contentID int, embedding []float32) error {
 	return s.StoreChunkEmbedding(contentID, 0, embedding, "")
 }
 
+// VectorDimensions is the width of the F32_BLOB embedding column. Shorter embeddings are
+// zero-padded to fit, which leaves their cosine distances unchanged.
+const VectorDimensions = 1536
+
+// vectorJSON converts an embedding into the JSON form accepted by vector32(), padded to VectorDimensions
+func vectorJSON(embedding []float32) (string, error) {
+	if len(embedding) > VectorDimensions {
+		return "", fmt.Errorf("embedding has %d dimensions but the vector index holds at most %d; set EMBEDDING_DIMENSIONS=%d",
+			len(embedding), VectorDimensions, VectorDimensions)
+	}
+
+	padded := embedding
+	if len(embedding) < VectorDimensions {
+		padded = make([]float32, VectorDimensions)
+		copy(padded, embedding)
+	}
+
+	data, err := json.Marshal(padded)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal embedding: %w", err)
+	}
+	return string(data), nil
+}
+
 // StoreChunkEmbedding stores a vector embedding for a specific chunk of content
 func (s *Storage) StoreChunkEmbedding(contentID int, chunkIndex int, embedding []float32, chunkText string) error {
 	fmt.Printf("[StoreChunkEmbedding] Starting with contentID=%d, chunkIndex=%d, embedding length=%d\n", contentID, chunkIndex, len(embedding))
 
 	// Convert float32 slice to JSON format for vector32() function
-	embeddingJSON, err := json.Marshal(embedding)
+	embeddingJSON, err := vectorJSON(embedding)
 	if err != nil {
-		return fmt.Errorf("failed to marshal embedding: %w", err)
+		return err
 	}
 
 	fmt.Printf("[StoreChunkEmbedding] JSON marshaled, length=%d bytes\n", len(embeddingJSON))
@@ -661,7 +927,7 @@ func (s *Storage) StoreChunkEmbedding(contentID int, chunkIndex int, embedding [
 	query := `INSERT OR REPLACE INTO embeddings (content_id, chunk_index, chunk_text, embedding) VALUES (?, ?, ?, vector32(?))`
 	fmt.Printf("[StoreChunkEmbedding] Executing query for chunk %d\n", chunkIndex)
 
-	result, err := s.db.Exec(query, contentID, chunkIndex, chunkText, string(embeddingJSON))
+	result, err := s.db.Exec(query, contentID, chunkIndex, chunkText, embeddingJSON)
 	if err != nil {
 		fmt.Printf("[StoreChunkEmbedding] ❌ Query execution failed: %v\n", err)
 		return fmt.Errorf("failed to store chunk embedding: %w", err)
@@ -695,12 +961,12 @@ func (s *Storage) StoreMultipleChunkEmbeddings(contentID int, embeddings [][]flo
 	// Insert new chunk embeddings
 	query := `INSERT INTO embeddings (content_id, chunk_index, chunk_text, embedding) VALUES (?, ?, ?, vector32(?))`
 	for i, embedding := range embeddings {
-		embeddingJSON, err := json.Marshal(embedding)
+		embeddingJSON, err := vectorJSON(embedding)
 		if err != nil {
-			return fmt.Errorf("failed to marshal embedding for chunk %d: %w", i, err)
+			return fmt.Errorf("invalid embedding for chunk %d: %w", i, err)
 		}
 
-		_, err = tx.Exec(query, contentID, i, chunks[i], string(embeddingJSON))
+		_, err = tx.Exec(query, contentID, i, chunks[i], embeddingJSON)
 		if err != nil {
 			return fmt.Errorf("failed to store embedding for chunk %d: %w", i, err)
 		}
@@ -724,11 +990,11 @@ func min(a, b int) int {
 
 // GetEmbedding retrieves a vector embedding by content ID
 func (s *Storage) GetEmbedding(contentID int) ([]float32, error) {
-	query := `SELECT embedding FROM embeddings WHERE content_id = ?`
+	query := `SELECT vector_extract(embedding) FROM embeddings WHERE content_id = ? ORDER BY chunk_index LIMIT 1`
 
 	row := s.db.QueryRow(query, contentID)
 
-	var embeddingData []byte
+	var embeddingData string
 	err := row.Scan(&embeddingData)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -738,7 +1004,7 @@ func (s *Storage) GetEmbedding(contentID int) ([]float32, error) {
 	}
 
 	var embedding []float32
-	err = json.Unmarshal(embeddingData, &embedding)
+	err = json.Unmarshal([]byte(embeddingData), &embedding)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal embedding: %w", err)
 	}
@@ -746,234 +1012,106 @@ func (s *Storage) GetEmbedding(contentID int) ([]float32, error) {
 	return embedding, nil
 }
 
-// HybridSearch performs a combined semantic and keyword search
+// hybridSearchCandidates is the smallest number of semantic and keyword rows fused by hybrid search
+const hybridSearchCandidates = 50
+
+// HybridSearch performs a combined semantic and keyword search using the default ranking profile
 func (s *Storage) HybridSearch(queryEmbedding []float32, queryText string) ([]*SearchResult, error) {
-	var allResults []*SearchResult
+	profile, err := LookupRankingProfile(DefaultRankingProfile)
+	if err != nil {
+		return nil, err
+	}
+	return s.HybridSearchWithProfile(queryEmbedding, queryText, profile, SearchOptions{})
+}
+
+// HybridSearchWithProfile performs a combined semantic and keyword search, fusing and boosting
+// results as described by the ranking profile. Relevance scores are always within [0,1].
+// Filters in opts apply to both searches; opts.Limit overrides the profile's limit.
+func (s *Storage) HybridSearchWithProfile(queryEmbedding []float32, queryText string, profile RankingProfile, opts SearchOptions) ([]*SearchResult, error) {
+	if opts.Limit > 0 {
+		profile.Limit = opts.Limit
+	}
+
+	// Chunks fold into one result per bookmark and keyword rows can repeat a bookmark, so both
+	// candidate pools are larger than the limit
+	candidates := max(hybridSearchCandidates, profile.Limit*3)
 
 	// Perform semantic search using vector similarity
-	semanticResults, err := s.semanticSearch(queryEmbedding, 50)
+	semanticResults, err := s.semanticSearch(queryEmbedding, candidates, opts)
 	if err != nil {
 		return nil, fmt.Errorf("semantic search failed: %w", err)
 	}
+	semanticResults = foldChunkResults(semanticResults, 0)
 
 	// Perform keyword search using FTS5
-	keywordResults, err := s.keywordSearch(queryText, 50)
+	keywordResults, err := s.keywordSearch(queryText, candidates, opts)
 	if err != nil {
 		return nil, fmt.Errorf("keyword search failed: %w", err)
 	}
 
-	// Normalize keyword scores to 0-1 range using max BM25 score from results
-	s.normalizeBM25Scores(keywordResults)
-
-	// Combine and deduplicate results
-	resultMap := make(map[string]*SearchResult)
-
-	// Add semantic results with rebalanced weight and threshold
-	for _, result := range semanticResults {
-		// Apply minimum threshold for semantic results (0.3 = 30% similarity)
-		if result.RelevanceScore < 0.3 {
-			continue
-		}
-		
-		result.RelevanceScore *= 0.4 // Rebalanced semantic weight (was 0.7, now 0.4)
-		result.SearchType = "semantic"
-		
-		// Apply exact word match boost
-		s.applyExactMatchBoost(result, queryText)
-		
-		// Apply field-specific boosting (title, URL)
-		s.applyFieldSpecificBoost(result, queryText)
-		
-		resultMap[result.Bookmark.ID] = result
-	}
-
-	// Add keyword results, combining scores if bookmark already exists
-	for _, result := range keywordResults {
-		// Apply minimum threshold for keyword results (0.15 normalized BM25)
-		if result.RelevanceScore < 0.15 {
-			continue
-		}
-		
-		result.RelevanceScore *= 0.6 // Rebalanced keyword weight (was 0.3, now 0.6)
-		
-		// Apply exact word match boost before combining
-		s.applyExactMatchBoost(result, queryText)
-		
-		// Apply field-specific boosting (title, URL)
-		s.applyFieldSpecificBoost(result, queryText)
-		
-		if existing, exists := resultMap[result.Bookmark.ID]; exists {
-			existing.RelevanceScore += result.RelevanceScore
-			existing.SearchType = "hybrid"
-			if result.MatchedSnippet != "" {
-				existing.MatchedSnippet = result.MatchedSnippet
-			}
-		} else {
-			result.SearchType = "keyword"
-			resultMap[result.Bookmark.ID] = result
-		}
-	}
+	// Normalize keyword scores to 0-1 range using the best BM25 score from results
+	normalizeBM25Scores(keywordResults)
 
-	// Convert map to slice and sort by relevance
-	for _, result := range resultMap {
-		allResults = append(allResults, result)
-	}
-
-	// Sort by relevance score (descending)
-	for i := 0; i < len(allResults)-1; i++ {
-		for j := i + 1; j < len(allResults); j++ {
-			if allResults[i].RelevanceScore < allResults[j].RelevanceScore {
-				allResults[i], allResults[j] = allResults[j], allResults[i]
-			}
-		}
-	}
-
-	// Limit results to top 20
-	if len(allResults) > 20 {
-		allResults = allResults[:20]
-	}
-
-	return allResults, nil
-}
-
-// normalizeBM25Scores normalizes BM25 scores to 0-1 range based on the maximum score in results
-func (s *Storage) normalizeBM25Scores(results []*SearchResult) {
-	if len(results) == 0 {
-		return
-	}
-	
-	// Find the maximum BM25 score
-	maxScore := 0.0
-	for _, result := range results {
-		if result.RelevanceScore > maxScore {
-			maxScore = result.RelevanceScore
-		}
-	}
-	
-	// Avoid division by zero
-	if maxScore <= 0 {
-		return
-	}
-	
-	// Normalize all scores to 0-1 range
-	for _, result := range results {
-		result.RelevanceScore = result.RelevanceScore / maxScore
-	}
+	results := fuseResults(semanticResults, keywordResults, profile, queryText)
+	highlightPassages(results, queryText)
+	return results, nil
 }
 
-// applyExactMatchBoost boosts scores for exact word matches in title, description, or content
-func (s *Storage) applyExactMatchBoost(result *SearchResult, queryText string) {
-	if queryText == "" {
-		return
-	}
-	
-	queryWords := strings.Fields(strings.ToLower(queryText))
-	boostApplied := false
-	
-	// Check title for exact word matches
-	titleLower := strings.ToLower(result.Bookmark.Title)
-	for _, word := range queryWords {
-		if strings.Contains(titleLower, word) {
-			result.RelevanceScore *= 1.5 // 50% boost for title matches
-			boostApplied = true
-			break
-		}
+// SemanticSearch performs only vector similarity search. Matching chunks are folded into one result
+// per bookmark whose passages are highlighted for queryText.
+func (s *Storage) SemanticSearch(queryEmbedding []float32, queryText string, opts SearchOptions) ([]*SearchResult, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 20
 	}
-	
-	// Check description for exact word matches (if not already boosted)
-	if !boostApplied && result.Bookmark.Description != "" {
-		descLower := strings.ToLower(result.Bookmark.Description)
-		for _, word := range queryWords {
-			if strings.Contains(descLower, word) {
-				result.RelevanceScore *= 1.3 // 30% boost for description matches
-				boostApplied = true
-				break
-			}
-		}
-	}
-	
-	// Check content for exact word matches (if not already boosted)
-	if !boostApplied && result.Content != nil && result.Content.CleanText != "" {
-		contentLower := strings.ToLower(result.Content.CleanText)
-		for _, word := range queryWords {
-			if strings.Contains(contentLower, word) {
-				result.RelevanceScore *= 1.2 // 20% boost for content matches
-				break
-			}
-		}
-	}
-}
 
-// applyFieldSpecificBoost applies high-priority boosts for exact matches in title and URL
-func (s *Storage) applyFieldSpecificBoost(result *SearchResult, queryText string) {
-	if queryText == "" {
-		return
-	}
-	
-	queryLower := strings.ToLower(queryText)
-	titleLower := strings.ToLower(result.Bookmark.Title)
-	urlLower := strings.ToLower(result.Bookmark.URL)
-	
-	// Check for exact title match (case-insensitive)
-	if strings.Contains(titleLower, queryLower) {
-		result.RelevanceScore *= 3.0 // 3x boost for exact title matches
-		return // Don't apply URL boost if title already matched
-	}
-	
-	// Check for URL matches (domain, path, or query parameters)
-	if strings.Contains(urlLower, queryLower) {
-		result.RelevanceScore *= 2.0 // 2x boost for URL matches
-		return
-	}
-	
-	// Check for individual word matches in title (less aggressive than exact match)
-	queryWords := strings.Fields(queryLower)
-	titleWords := strings.Fields(titleLower)
-	
-	titleMatches := 0
-	for _, queryWord := range queryWords {
-		for _, titleWord := range titleWords {
-			if queryWord == titleWord {
-				titleMatches++
-				break
-			}
-		}
-	}
-	
-	// Apply graduated boost based on word matches in title
-	if titleMatches > 0 {
-		matchRatio := float64(titleMatches) / float64(len(queryWords))
-		if matchRatio >= 0.5 { // 50% or more words match
-			result.RelevanceScore *= 2.0 // 2x boost for high word match ratio
-		} else if matchRatio >= 0.25 { // 25% or more words match
-			result.RelevanceScore *= 1.5 // 1.5x boost for moderate word match ratio
-		}
+	// Several chunks of one bookmark can match; fetch extra neighbours so enough bookmarks remain
+	results, err := s.semanticSearch(queryEmbedding, limit*3, opts)
+	if err != nil {
+		return nil, err
 	}
+	results = foldChunkResults(results, limit)
+	highlightPassages(results, queryText)
+	return results, nil
 }
 
-// semanticSearch performs vector similarity search using libSQL vector functions
-func (s *Storage) semanticSearch(queryEmbedding []float32, limit int) ([]*SearchResult, error) {
+// semanticSearch performs vector similarity search using libSQL vector functions. It returns one
+// result per matching chunk, best first, carrying the chunk as its only passage instead of the page body.
+func (s *Storage) semanticSearch(queryEmbedding []float32, limit int, opts SearchOptions) ([]*SearchResult, error) {
 	// Convert query embedding to JSON for vector32() function
-	queryEmbeddingJSON, err := json.Marshal(queryEmbedding)
+	queryEmbeddingJSON, err := vectorJSON(queryEmbedding)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal query embedding: %w", err)
+		return nil, err
+	}
+
+	// The index knows nothing about filters, so widen the neighbourhood before filtering
+	filterClause, filterArgs := opts.filterClause()
+	candidates := limit
+	if filterClause != "" {
+		candidates = max(limit*20, filteredSemanticCandidates)
 	}
 
+	// vector_top_k walks the libsql_vector_idx index; distances are only computed for the k neighbours
 	query := `
-		SELECT b.id, b.url, b.title, b.status, b.imported_at, b.created_at, b.updated_at,
+		SELECT b.id, b.url, COALESCE(b.title, ''), b.status, b.imported_at, b.created_at, b.updated_at,
 		       COALESCE(b.folder_path, ''), COALESCE(b.description, ''),
-		       c.id, c.bookmark_id, COALESCE(c.raw_content, ''), COALESCE(c.clean_text, ''),
-		       c.scraped_at, c.content_type,
+		       c.id, c.bookmark_id, c.scraped_at, c.content_type,
+		       e.chunk_index, COALESCE(NULLIF(e.chunk_text, ''), substr(COALESCE(c.clean_text, ''), 1, 2000)),
 		       vector_distance_cos(e.embedding, vector32(?)) as similarity
-		FROM embeddings e
+		FROM vector_top_k('idx_embeddings_vector', vector32(?), ?) AS v
+		JOIN embeddings e ON e.id = v.id
 		JOIN content c ON c.id = e.content_id
 		JOIN bookmarks b ON b.id = c.bookmark_id
-		WHERE vector_distance_cos(e.embedding, vector32(?)) < 1.0
+		WHERE 1=1` + filterClause + `
 		ORDER BY similarity ASC
 		LIMIT ?
 	`
 
-	rows, err := s.db.Query(query, string(queryEmbeddingJSON), string(queryEmbeddingJSON), limit)
+	args := []interface{}{queryEmbeddingJSON, queryEmbeddingJSON, candidates}
+	args = append(args, filterArgs...)
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute semantic search: %w", err)
 	}
@@ -983,28 +1121,36 @@ func (s *Storage) semanticSearch(queryEmbedding []float32, limit int) ([]*Search
 	for rows.Next() {
 		bookmark := &Bookmark{}
 		content := &Content{}
+		passage := &Passage{}
 		var similarity float64
 
 		err := rows.Scan(
 			&bookmark.ID, &bookmark.URL, &bookmark.Title, &bookmark.Status,
 			&bookmark.ImportedAt, &bookmark.CreatedAt, &bookmark.UpdatedAt,
 			&bookmark.FolderPath, &bookmark.Description,
-			&content.ID, &content.BookmarkID, &content.RawContent,
-			&content.CleanText, &content.ScrapedAt, &content.ContentType,
+			&content.ID, &content.BookmarkID, &content.ScrapedAt, &content.ContentType,
+			&passage.ChunkIndex, &passage.Text,
 			&similarity,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan semantic search result: %w", err)
 		}
 
+		// Skip neighbours that are orthogonal or opposite to the query
+		if similarity >= 1.0 {
+			continue
+		}
+
 		// Convert cosine distance to similarity score (1 - distance)
 		similarityScore := 1.0 - similarity
+		passage.Score = similarityScore
 
 		result := &SearchResult{
 			Bookmark:       bookmark,
 			Content:        content,
 			RelevanceScore: similarityScore,
 			SearchType:     "semantic",
+			Passages:       []*Passage{passage},
 		}
 
 		results = append(results, result)
@@ -1013,49 +1159,136 @@ func (s *Storage) semanticSearch(queryEmbedding []float32, limit int) ([]*Search
 	return results, nil
 }
 
-// KeywordSearch performs only keyword-based search (public method)
+// GetRelevantChunks returns the content chunks of the given bookmarks ordered by similarity
+// to the query embedding. Bookmarks whose embeddings were stored without chunk text fall back
+// to the beginning of their clean text. A nil query embedding keeps the chunks in document order.
+func (s *Storage) GetRelevantChunks(queryEmbedding []float32, bookmarkIDs []string, limit int) ([]*ContentChunk, error) {
+	if len(bookmarkIDs) == 0 {
+		return []*ContentChunk{}, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(bookmarkIDs)), ",")
+	args := make([]interface{}, 0, len(bookmarkIDs)+2)
+
+	distanceExpr := "0.0"
+	orderBy := "e.chunk_index ASC"
+	if len(queryEmbedding) > 0 {
+		queryEmbeddingJSON, err := vectorJSON(queryEmbedding)
+		if err != nil {
+			return nil, err
+		}
+		distanceExpr = "vector_distance_cos(e.embedding, vector32(?))"
+		orderBy = "distance ASC"
+		args = append(args, queryEmbeddingJSON)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT c.bookmark_id, c.id, e.chunk_index,
+		       COALESCE(NULLIF(e.chunk_text, ''), substr(COALESCE(c.clean_text, ''), 1, 2000)),
+		       %s AS distance
+		FROM embeddings e
+		JOIN content c ON c.id = e.content_id
+		WHERE c.bookmark_id IN (%s)
+		ORDER BY %s
+		LIMIT ?
+	`, distanceExpr, placeholders, orderBy)
+
+	for _, id := range bookmarkIDs {
+		args = append(args, id)
+	}
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relevant chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []*ContentChunk
+	for rows.Next() {
+		chunk := &ContentChunk{}
+		var distance float64
+		if err := rows.Scan(&chunk.BookmarkID, &chunk.ContentID, &chunk.ChunkIndex, &chunk.Text, &distance); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		if strings.TrimSpace(chunk.Text) == "" {
+			continue
+		}
+		chunk.Score = 1.0 - distance
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, rows.Err()
+}
+
+// KeywordSearch performs only keyword-based search (public method). Scores are normalized to [0,1].
 func (s *Storage) KeywordSearch(queryText string, limit int) ([]*SearchResult, error) {
-	return s.keywordSearch(queryText, limit)
+	return s.KeywordSearchWithOptions(queryText, SearchOptions{Limit: limit})
+}
+
+// KeywordSearchWithOptions performs a filtered keyword search, returning the best match per bookmark.
+// Scores are normalized to [0,1].
+func (s *Storage) KeywordSearchWithOptions(queryText string, opts SearchOptions) ([]*SearchResult, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	results, err := s.keywordSearch(queryText, limit*3, opts)
+	if err != nil {
+		return nil, err
+	}
+	normalizeBM25Scores(results)
+	return bestResultPerBookmark(results, limit), nil
 }
 
 // keywordSearch performs BM25-based full-text search
-func (s *Storage) keywordSearch(queryText string, limit int) ([]*SearchResult, error) {
-	// Escape FTS5 special characters and prepare query
-	escapedQuery := strings.ReplaceAll(queryText, "'", "''")
-	// Don't add quotes here - they'll be added by the SQL query
-	ftsQuery := escapedQuery
+func (s *Storage) keywordSearch(queryText string, limit int, opts SearchOptions) ([]*SearchResult, error) {
+	// Quote every word so FTS5 operators and punctuation in the query are matched literally
+	ftsQuery := ftsMatchExpression(queryText)
+	if ftsQuery == "" {
+		return []*SearchResult{}, nil
+	}
+
+	filterClause, filterArgs := opts.filterClause()
 
 	// Use UNION to combine bookmark title/description matches with content matches
 	query := `
-		SELECT b.id, b.url, b.title, b.status, b.imported_at, b.created_at, b.updated_at,
+		SELECT b.id, b.url, COALESCE(b.title, ''), b.status, b.imported_at, b.created_at, b.updated_at,
 		       COALESCE(b.folder_path, ''), COALESCE(b.description, ''),
-		       COALESCE(c.id, 0), COALESCE(c.bookmark_id, ''), COALESCE(c.raw_content, ''), COALESCE(c.clean_text, ''),
+		       COALESCE(c.id, 0), COALESCE(c.bookmark_id, ''), '', COALESCE(c.clean_text, ''),
 		       COALESCE(c.scraped_at, b.created_at), COALESCE(c.content_type, 'text/html'),
 		       bm25(bookmarks_fts) as relevance,
 		       '' as snippet
 		FROM bookmarks_fts
-		JOIN bookmarks b ON b.rowid = bookmarks_fts.rowid
+		JOIN bookmarks b ON b.id = bookmarks_fts.bookmark_id
 		LEFT JOIN content c ON c.bookmark_id = b.id
-		WHERE bookmarks_fts MATCH ?
+		WHERE bookmarks_fts MATCH ?` + filterClause + `
 		
 		UNION
 		
-		SELECT b.id, b.url, b.title, b.status, b.imported_at, b.created_at, b.updated_at,
+		SELECT b.id, b.url, COALESCE(b.title, ''), b.status, b.imported_at, b.created_at, b.updated_at,
 		       COALESCE(b.folder_path, ''), COALESCE(b.description, ''),
-		       c.id, c.bookmark_id, c.raw_content, c.clean_text,
+		       c.id, c.bookmark_id, '', c.clean_text,
 		       c.scraped_at, c.content_type,
 		       bm25(content_fts) as relevance,
 		       snippet(content_fts, 0, '<mark>', '</mark>', '...', 32) as snippet
 		FROM content_fts
 		JOIN content c ON c.id = content_fts.rowid
 		JOIN bookmarks b ON b.id = c.bookmark_id
-		WHERE content_fts MATCH ?
+		WHERE content_fts MATCH ?` + filterClause + `
 		
 		ORDER BY relevance
 		LIMIT ?
 	`
 
-	rows, err := s.db.Query(query, ftsQuery, ftsQuery, limit)
+	args := []interface{}{ftsQuery}
+	args = append(args, filterArgs...)
+	args = append(args, ftsQuery)
+	args = append(args, filterArgs...)
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute keyword search: %w", err)
 	}
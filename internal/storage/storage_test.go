@@ -1,8 +1,11 @@
 package storage
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -31,17 +34,48 @@ func TestStorage(t *testing.T) {
 	t.Run("ErrorHandling", testErrorHandling(store))
 }
 
+// newTestStorage opens a migrated database in the test's temporary directory
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	store, err := New("file:" + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// addBookmark creates a pending bookmark for a test
+func addBookmark(t testing.TB, store *Storage, url, title string) *Bookmark {
+	t.Helper()
+	bookmark := &Bookmark{URL: url, Title: title}
+	if err := store.CreateBookmark(bookmark); err != nil {
+		t.Fatalf("Failed to add bookmark: %v", err)
+	}
+	return bookmark
+}
+
+// testEmbedding returns a random embedding with the dimensions of the embeddings column
+func testEmbedding() []float32 {
+	embedding := make([]float32, 1536)
+	for i := range embedding {
+		embedding[i] = rand.Float32()
+	}
+	return embedding
+}
+
 func testAddBookmark(store *Storage) func(*testing.T) {
 	return func(t *testing.T) {
-		err := store.AddBookmark("https://example.com", "Example Site")
+		err := store.CreateBookmark(&Bookmark{URL: "https://example.com", Title: "Example Site"})
 		if err != nil {
 			t.Errorf("Failed to add bookmark: %v", err)
 		}
 
 		// Test duplicate URL (should fail due to UNIQUE constraint)
-		err = store.AddBookmark("https://example.com", "Duplicate Site")
-		if err == nil {
-			t.Error("Expected error for duplicate URL, but got none")
+		err = store.CreateBookmark(&Bookmark{URL: "https://example.com", Title: "Duplicate Site"})
+		var duplicate *DuplicateBookmarkError
+		if !errors.As(err, &duplicate) {
+			t.Errorf("Expected a duplicate bookmark error for duplicate URL, got %v", err)
 		}
 	}
 }
@@ -49,13 +83,10 @@ func testAddBookmark(store *Storage) func(*testing.T) {
 func testGetBookmark(store *Storage) func(*testing.T) {
 	return func(t *testing.T) {
 		// Add a bookmark first
-		err := store.AddBookmark("https://test.com", "Test Site")
-		if err != nil {
-			t.Fatalf("Failed to add bookmark: %v", err)
-		}
+		added := addBookmark(t, store, "https://test.com", "Test Site")
 
 		// Get the bookmark
-		bookmark, err := store.GetBookmark(1)
+		bookmark, err := store.GetBookmark(added.ID)
 		if err != nil {
 			t.Errorf("Failed to get bookmark: %v", err)
 		}
@@ -98,19 +129,16 @@ func testListBookmarksEmpty(store *Storage) func(*testing.T) {
 func testUpdateBookmarkStatus(store *Storage) func(*testing.T) {
 	return func(t *testing.T) {
 		// Add a bookmark first
-		err := store.AddBookmark("https://status-test.com", "Status Test")
-		if err != nil {
-			t.Fatalf("Failed to add bookmark: %v", err)
-		}
+		added := addBookmark(t, store, "https://status-test.com", "Status Test")
 
 		// Update status
-		err = store.UpdateBookmarkStatus(1, "completed")
+		err := store.UpdateBookmarkStatus(added.ID, "completed")
 		if err != nil {
 			t.Errorf("Failed to update bookmark status: %v", err)
 		}
 
 		// Verify status was updated
-		bookmark, err := store.GetBookmark(1)
+		bookmark, err := store.GetBookmark(added.ID)
 		if err != nil {
 			t.Errorf("Failed to get bookmark: %v", err)
 		}
@@ -124,22 +152,19 @@ func testUpdateBookmarkStatus(store *Storage) func(*testing.T) {
 func testStoreAndGetContent(store *Storage) func(*testing.T) {
 	return func(t *testing.T) {
 		// Add a bookmark first
-		err := store.AddBookmark("https://content-test.com", "Content Test")
-		if err != nil {
-			t.Fatalf("Failed to add bookmark: %v", err)
-		}
+		bookmark := addBookmark(t, store, "https://content-test.com", "Content Test")
 
 		rawContent := "<html><body>Test content</body></html>"
 		cleanText := "Test content"
 
 		// Store content
-		err = store.StoreContent(1, rawContent, cleanText)
+		err := store.StoreContent(bookmark.ID, rawContent, cleanText)
 		if err != nil {
 			t.Errorf("Failed to store content: %v", err)
 		}
 
 		// Get content
-		content, err := store.GetContent(1)
+		content, err := store.GetContent(bookmark.ID)
 		if err != nil {
 			t.Errorf("Failed to get content: %v", err)
 		}
@@ -157,32 +182,30 @@ func testStoreAndGetContent(store *Storage) func(*testing.T) {
 func testStoreAndGetEmbedding(store *Storage) func(*testing.T) {
 	return func(t *testing.T) {
 		// Add bookmark and content first
-		err := store.AddBookmark("https://embedding-test.com", "Embedding Test")
-		if err != nil {
-			t.Fatalf("Failed to add bookmark: %v", err)
-		}
+		bookmark := addBookmark(t, store, "https://embedding-test.com", "Embedding Test")
 
-		err = store.StoreContent(1, "<html><body>Embedding test</body></html>", "Embedding test")
+		err := store.StoreContent(bookmark.ID, "<html><body>Embedding test</body></html>", "Embedding test")
 		if err != nil {
 			t.Fatalf("Failed to store content: %v", err)
 		}
+		content, err := store.GetContent(bookmark.ID)
+		if err != nil {
+			t.Fatalf("Failed to get content: %v", err)
+		}
 
 		// Generate test embedding
-		embedding := make([]float32, 1536)
-		for i := range embedding {
-			embedding[i] = rand.Float32()
-		}
+		embedding := testEmbedding()
 
 		// Store embedding
-		err = store.StoreEmbedding(1, embedding)
+		err = store.StoreEmbedding(content.ID, embedding)
 		if err != nil {
 			t.Errorf("Failed to store embedding: %v", err)
 		}
 
 		// Get embedding
-		retrievedEmbedding, err := store.GetEmbedding(1)
+		retrievedEmbedding, err := store.GetEmbedding(content.ID)
 		if err != nil {
-			t.Errorf("Failed to get embedding: %v", err)
+			t.Fatalf("Failed to get embedding: %v", err)
 		}
 
 		if len(retrievedEmbedding) != len(embedding) {
@@ -213,33 +236,26 @@ func testHybridSearch(store *Storage) func(*testing.T) {
 		}
 
 		for i, bookmark := range testBookmarks {
-			err := store.AddBookmark(bookmark.URL, bookmark.Title)
-			if err != nil {
-				t.Fatalf("Failed to add bookmark %d: %v", i, err)
-			}
+			added := addBookmark(t, store, bookmark.URL, bookmark.Title)
 
-			err = store.StoreContent(i+1, "<html><body>"+bookmark.Content+"</body></html>", bookmark.Content)
+			err := store.StoreContent(added.ID, "<html><body>"+bookmark.Content+"</body></html>", bookmark.Content)
 			if err != nil {
 				t.Fatalf("Failed to store content %d: %v", i, err)
 			}
-
-			// Generate mock embedding
-			embedding := make([]float32, 1536)
-			for j := range embedding {
-				embedding[j] = rand.Float32()
+			content, err := store.GetContent(added.ID)
+			if err != nil {
+				t.Fatalf("Failed to get content %d: %v", i, err)
 			}
 
-			err = store.StoreEmbedding(i+1, embedding)
+			// Generate mock embedding
+			err = store.StoreEmbedding(content.ID, testEmbedding())
 			if err != nil {
 				t.Fatalf("Failed to store embedding %d: %v", i, err)
 			}
 		}
 
 		// Perform search
-		queryEmbedding := make([]float32, 1536)
-		for i := range queryEmbedding {
-			queryEmbedding[i] = rand.Float32()
-		}
+		queryEmbedding := testEmbedding()
 
 		results, err := store.HybridSearch(queryEmbedding, "programming language")
 		if err != nil {
@@ -328,12 +344,9 @@ func testGetStats(store *Storage) func(*testing.T) {
 func testSearchWithFilters(store *Storage) func(*testing.T) {
 	return func(t *testing.T) {
 		// Add a bookmark with specific status
-		err := store.AddBookmark("https://filter-test.com", "Filter Test")
-		if err != nil {
-			t.Fatalf("Failed to add bookmark: %v", err)
-		}
+		bookmark := addBookmark(t, store, "https://filter-test.com", "Filter Test")
 
-		err = store.UpdateBookmarkStatus(1, "completed")
+		err := store.UpdateBookmarkStatus(bookmark.ID, "completed")
 		if err != nil {
 			t.Fatalf("Failed to update status: %v", err)
 		}
@@ -349,6 +362,10 @@ func testSearchWithFilters(store *Storage) func(*testing.T) {
 			t.Errorf("Filtered search failed: %v", err)
 		}
 
+		if len(results) == 0 {
+			t.Error("Expected the completed bookmark in the filtered results, got none")
+		}
+
 		// Verify all results have the correct status
 		for _, result := range results {
 			if result.Bookmark.Status != "completed" {
@@ -361,19 +378,16 @@ func testSearchWithFilters(store *Storage) func(*testing.T) {
 func testDeleteBookmark(store *Storage) func(*testing.T) {
 	return func(t *testing.T) {
 		// Add a bookmark to delete
-		err := store.AddBookmark("https://delete-test.com", "Delete Test")
-		if err != nil {
-			t.Fatalf("Failed to add bookmark: %v", err)
-		}
+		bookmark := addBookmark(t, store, "https://delete-test.com", "Delete Test")
 
 		// Delete the bookmark
-		err = store.DeleteBookmark(1)
+		err := store.DeleteBookmark(bookmark.ID)
 		if err != nil {
 			t.Errorf("Failed to delete bookmark: %v", err)
 		}
 
 		// Verify bookmark is deleted
-		_, err = store.GetBookmark(1)
+		_, err = store.GetBookmark(bookmark.ID)
 		if err == nil {
 			t.Error("Expected error when getting deleted bookmark, but got none")
 		}
@@ -383,19 +397,19 @@ func testDeleteBookmark(store *Storage) func(*testing.T) {
 func testErrorHandling(store *Storage) func(*testing.T) {
 	return func(t *testing.T) {
 		// Test getting non-existent bookmark
-		_, err := store.GetBookmark(9999)
+		_, err := store.GetBookmark("missing")
 		if err == nil {
 			t.Error("Expected error for non-existent bookmark, got none")
 		}
 
 		// Test updating non-existent bookmark
-		err = store.UpdateBookmarkStatus(9999, "completed")
+		err = store.UpdateBookmarkStatus("missing", "completed")
 		if err == nil {
 			t.Error("Expected error for non-existent bookmark update, got none")
 		}
 
 		// Test getting content for non-existent bookmark
-		_, err = store.GetContent(9999)
+		_, err = store.GetContent("missing")
 		if err == nil {
 			t.Error("Expected error for non-existent content, got none")
 		}
@@ -407,7 +421,7 @@ func testErrorHandling(store *Storage) func(*testing.T) {
 		}
 
 		// Test deleting non-existent bookmark
-		err = store.DeleteBookmark(9999)
+		err = store.DeleteBookmark("missing")
 		if err == nil {
 			t.Error("Expected error for deleting non-existent bookmark, got none")
 		}
@@ -424,7 +438,7 @@ func BenchmarkAddBookmark(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		store.AddBookmark("https://example.com/"+string(rune(i)), "Benchmark Test "+string(rune(i)))
+		store.CreateBookmark(&Bookmark{URL: fmt.Sprintf("https://example.com/%d", i), Title: fmt.Sprintf("Benchmark Test %d", i)})
 	}
 }
 
@@ -438,23 +452,37 @@ func BenchmarkHybridSearch(b *testing.B) {
 
 	// Setup test data
 	for i := 0; i < 100; i++ {
-		store.AddBookmark("https://example.com/"+string(rune(i)), "Test Bookmark "+string(rune(i)))
-		store.StoreContent(i+1, "<html><body>Test content "+string(rune(i))+"</body></html>", "Test content "+string(rune(i)))
+		bookmark := addBookmark(b, store, fmt.Sprintf("https://example.com/%d", i), fmt.Sprintf("Test Bookmark %d", i))
+		store.StoreContent(bookmark.ID, fmt.Sprintf("<html><body>Test content %d</body></html>", i), fmt.Sprintf("Test content %d", i))
 
-		embedding := make([]float32, 1536)
-		for j := range embedding {
-			embedding[j] = rand.Float32()
+		if content, err := store.GetContent(bookmark.ID); err == nil {
+			store.StoreEmbedding(content.ID, testEmbedding())
 		}
-		store.StoreEmbedding(i+1, embedding)
 	}
 
-	queryEmbedding := make([]float32, 1536)
-	for i := range queryEmbedding {
-		queryEmbedding[i] = rand.Float32()
-	}
+	queryEmbedding := testEmbedding()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		store.HybridSearch(queryEmbedding, "test content")
 	}
 }
+
+func TestHybridSearchHonorsLargeLimits(t *testing.T) {
+	store := newTestStorage(t)
+	for i := 0; i < 120; i++ {
+		addBookmark(t, store, fmt.Sprintf("https://example.com/%d", i), fmt.Sprintf("Gopher notes %d", i))
+	}
+
+	profile, err := LookupRankingProfile(DefaultRankingProfile)
+	if err != nil {
+		t.Fatalf("Failed to look up ranking profile: %v", err)
+	}
+	results, err := store.HybridSearchWithProfile(testEmbedding(), "gopher", profile, SearchOptions{Limit: 100})
+	if err != nil {
+		t.Fatalf("Hybrid search failed: %v", err)
+	}
+	if len(results) != 100 {
+		t.Errorf("Expected 100 of the 120 matching bookmarks, got %d", len(results))
+	}
+}